@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Форматы, в которых можно указывать дедлайны в конфиге
+var deadlineLayouts = []string{
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	time.RFC3339,
+}
+
+// Элемент объединенного календаря: личный дедлайн или контест
+type AgendaItem struct {
+	Title string
+	Kind  string // deadline, contest
+	Start time.Time
+	End   time.Time // для контестов, иначе нулевое значение
+	Note  string
+	ID    string // ID контеста, если есть
+}
+
+func parseDeadlineTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range deadlineLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			// Дедлайн без времени считаем на конец дня
+			if layout == "2006-01-02" {
+				t = t.Add(23*time.Hour + 59*time.Minute)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New(T("agenda.bad_date", value))
+}
+
+// Человекочитаемый обратный отсчет: "2д 3ч 15м"
+func formatCountdown(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, T("time.days", days))
+	}
+	if hours > 0 {
+		parts = append(parts, T("time.hours", hours))
+	}
+	if minutes > 0 || len(parts) == 0 {
+		parts = append(parts, T("time.minutes", minutes))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (v *VSCodeExtension) createAgendaCommand() *cobra.Command {
+	var showAll bool
+	var icsFile string
+
+	cmd := &cobra.Command{
+		Use:   "agenda",
+		Short: T("agenda.short"),
+		Long:  T("agenda.long"),
+		Run: func(cmd *cobra.Command, args []string) {
+			v.handleAgenda(cmd.Context(), showAll, icsFile)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&showAll, "all", "a", false, T("flag.agenda_all"))
+	cmd.Flags().StringVar(&icsFile, "ics", "", T("flag.agenda_ics"))
+
+	cmd.AddCommand(v.createAgendaAddCommand(), v.createAgendaRemoveCommand())
+
+	return cmd
+}
+
+func (v *VSCodeExtension) createAgendaAddCommand() *cobra.Command {
+	var note string
+
+	cmd := &cobra.Command{
+		Use:   "add <name> <date>",
+		Short: T("agenda.add_short"),
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := strings.TrimSpace(args[0])
+			due, err := parseDeadlineTime(args[1])
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				return
+			}
+
+			v.config.Deadlines = append(v.config.Deadlines, Deadline{
+				Name: name,
+				Due:  due.Format("2006-01-02 15:04"),
+				Note: note,
+			})
+
+			if err := SaveConfig(v.config); err != nil {
+				fmt.Print(T("config.save_error", err))
+				return
+			}
+
+			fmt.Print(T("agenda.added", name, due.Format("02.01.2006 15:04")))
+		},
+	}
+
+	cmd.Flags().StringVarP(&note, "note", "n", "", T("flag.agenda_note"))
+	return cmd
+}
+
+func (v *VSCodeExtension) createAgendaRemoveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <name>",
+		Short: T("agenda.rm_short"),
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var kept []Deadline
+			removed := 0
+			for _, d := range v.config.Deadlines {
+				if strings.EqualFold(d.Name, args[0]) {
+					removed++
+					continue
+				}
+				kept = append(kept, d)
+			}
+
+			if removed == 0 {
+				fmt.Print(T("agenda.not_found", args[0]))
+				return
+			}
+
+			v.config.Deadlines = kept
+			if err := SaveConfig(v.config); err != nil {
+				fmt.Print(T("config.save_error", err))
+				return
+			}
+
+			fmt.Print(T("agenda.removed", removed))
+		},
+	}
+}
+
+// Собирает дедлайны из конфига и предстоящие контесты в один отсортированный список
+func (v *VSCodeExtension) collectAgenda(ctx context.Context, showAll bool) []AgendaItem {
+	now := time.Now()
+	var items []AgendaItem
+
+	for _, d := range v.config.Deadlines {
+		due, err := parseDeadlineTime(d.Due)
+		if err != nil {
+			progress(T("agenda.skipped", d.Name, err))
+			continue
+		}
+		if !showAll && due.Before(now) {
+			continue
+		}
+		items = append(items, AgendaItem{
+			Title: d.Name,
+			Kind:  "deadline",
+			Start: due,
+			Note:  d.Note,
+		})
+	}
+
+	if v.apiClient.IsAuthenticated() {
+		contests, err := v.apiClient.getUpcomingContests(ctx)
+		if err != nil {
+			progress(T("agenda.contests_failed", err))
+		}
+		for _, contest := range contests {
+			end := time.Unix(contest.Ends, 0)
+			if contest.Ends > 0 && end.Before(now) {
+				continue
+			}
+			items = append(items, AgendaItem{
+				Title: contest.Name,
+				Kind:  "contest",
+				Start: time.Unix(contest.Starts, 0),
+				End:   end,
+				ID:    contest.ID,
+			})
+		}
+	} else {
+		fmt.Println(T("agenda.no_auth"))
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Start.Before(items[j].Start)
+	})
+
+	return items
+}
+
+func (v *VSCodeExtension) handleAgenda(ctx context.Context, showAll bool, icsFile string) {
+	items := v.collectAgenda(ctx, showAll)
+
+	if len(items) == 0 {
+		fmt.Println(T("agenda.empty"))
+		progressln(T("agenda.empty_hint"))
+		return
+	}
+
+	now := time.Now()
+	fmt.Print(T("agenda.header", len(items)))
+
+	for _, item := range items {
+		icon := "📌"
+		if item.Kind == "contest" {
+			icon = "🏆"
+		}
+
+		var when string
+		switch {
+		case item.Kind == "contest" && item.Start.Before(now):
+			when = T("agenda.running", formatCountdown(item.End.Sub(now)))
+		case item.Start.Before(now):
+			when = T("agenda.ago", formatCountdown(now.Sub(item.Start)))
+		default:
+			when = T("agenda.in", formatCountdown(item.Start.Sub(now)))
+		}
+
+		title := item.Title
+		if item.ID != "" {
+			title = fmt.Sprintf("%s (ID: %s)", title, item.ID)
+		}
+
+		fmt.Printf("  %s %s  %-40s ⏳ %s\n", icon, item.Start.Format("02.01 15:04"), title, when)
+		if item.Note != "" {
+			fmt.Printf("     💬 %s\n", item.Note)
+		}
+	}
+
+	if icsFile != "" {
+		if err := writeAgendaICS(icsFile, items); err != nil {
+			fmt.Print(T("agenda.export_failed", err))
+			return
+		}
+		fmt.Print(T("agenda.exported", icsFile))
+	}
+}
+
+// Экспорт календаря в формате iCalendar (RFC 5545)
+func writeAgendaICS(filename string, items []AgendaItem) error {
+	const icsTime = "20060102T150405Z"
+
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	b.WriteString("VERSION:2.0\r\n")
+	b.WriteString("PRODID:-//sortme_plugin//agenda//RU\r\n")
+
+	stamp := time.Now().UTC().Format(icsTime)
+	for i, item := range items {
+		end := item.End
+		if end.IsZero() {
+			end = item.Start
+		}
+
+		b.WriteString("BEGIN:VEVENT\r\n")
+		fmt.Fprintf(&b, "UID:%s-%d-%d@sortme_plugin\r\n", item.Kind, item.Start.Unix(), i)
+		fmt.Fprintf(&b, "DTSTAMP:%s\r\n", stamp)
+		fmt.Fprintf(&b, "DTSTART:%s\r\n", item.Start.UTC().Format(icsTime))
+		fmt.Fprintf(&b, "DTEND:%s\r\n", end.UTC().Format(icsTime))
+		fmt.Fprintf(&b, "SUMMARY:%s\r\n", escapeICS(item.Title))
+		if item.Note != "" {
+			fmt.Fprintf(&b, "DESCRIPTION:%s\r\n", escapeICS(item.Note))
+		}
+		if item.ID != "" {
+			fmt.Fprintf(&b, "URL:https://sort-me.org/contest/%s\r\n", item.ID)
+		}
+		b.WriteString("END:VEVENT\r\n")
+	}
+
+	b.WriteString("END:VCALENDAR\r\n")
+
+	return os.WriteFile(filename, []byte(b.String()), 0644)
+}
+
+func escapeICS(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+	return replacer.Replace(s)
+}
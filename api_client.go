@@ -2,26 +2,31 @@ package main
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"os"
-	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gorilla/websocket"
 )
 
 type APIClient struct {
-	config  *Config
-	client  *http.Client
-	baseURL string
+	config    *Config
+	transport *apiTransport
+	usage     *usageRecorder
+	cache     *Cache
+	limiter   *requestLimiter
+	clock     clock
+	offline   offlineState
+	session   sessionState
+	tape      *apiTape // --record или --replay
 }
 
 // Структуры для API sort-me.org
@@ -46,6 +51,12 @@ type SubmissionStatus struct {
 	Score  int    `json:"score"`
 	Time   string `json:"time"`
 	Memory string `json:"memory"`
+	Test   int    `json:"test,omitempty"` // Номер теста, на котором идет проверка
+
+	FailedTest int       `json:"failed_test,omitempty"` // Первый непройденный тест (shown_test)
+	Subtasks   []Subtask `json:"subtasks,omitempty"`    // Итог по подзадачам
+
+	QueuePosition int `json:"queue_position,omitempty"` // Место в очереди, если сервер его сообщает
 }
 
 type WSMessage struct {
@@ -64,14 +75,45 @@ type SubmissionResult struct {
 	ShownVerdict     int       `json:"shown_verdict"`
 	ShownVerdictText string    `json:"shown_verdict_text"`
 	TotalPoints      int       `json:"total_points"`
+	ShownTest        int       `json:"shown_test"`
 	Subtasks         []Subtask `json:"subtasks"`
 }
 
 type Subtask struct {
-	Skipped     bool        `json:"skipped"`
-	Points      int         `json:"points"`
-	FailedTests interface{} `json:"failed_tests"`
-	WorstTime   int         `json:"worst_time"`
+	Skipped     bool     `json:"skipped"`
+	Points      int      `json:"points"`
+	FailedTests testList `json:"failed_tests"`
+	WorstTime   int      `json:"worst_time"` // мс
+}
+
+// Номера тестов. Сервер присылает их списком чисел или строк, одним числом
+// или null - разбираем любой вариант, чтобы не потерять остальной ответ
+type testList []int
+
+func (t *testList) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*t = nil
+	add := func(value interface{}) {
+		switch n := value.(type) {
+		case float64:
+			*t = append(*t, int(n))
+		case string:
+			if number, err := strconv.Atoi(strings.TrimSpace(n)); err == nil {
+				*t = append(*t, number)
+			}
+		}
+	}
+	if items, ok := raw.([]interface{}); ok {
+		for _, item := range items {
+			add(item)
+		}
+		return nil
+	}
+	add(raw)
+	return nil
 }
 
 // Структуры для списка отправок
@@ -118,215 +160,220 @@ type Contest struct {
 	Name    string `json:"name"`
 	Status  string `json:"status"`  // active, upcoming, archive
 	Started bool   `json:"started"` // Добавляем это поле
+	Starts  int64  `json:"starts,omitempty"`
+	Ends    int64  `json:"ends,omitempty"`
 }
 
-// В методе getArchiveContestSubmissions уберем лишний вывод
-func (a *APIClient) getArchiveContestSubmissions(contestID string, contestInfo *ContestInfo, limit int) ([]Submission, error) {
-	insecureClient := &http.Client{
-		Timeout: 15 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-
+// Получение отправок архивного контеста. Рабочий endpoint запоминается,
+// чтобы в следующий раз не перебирать весь список
+func (a *APIClient) getArchiveContestSubmissions(ctx context.Context, contestID string, contestInfo *ContestInfo, page Page) ([]Submission, error) {
 	// Пробуем разные endpoints для архивных контестов (тихо, без вывода)
-	endpoints := []string{
-		fmt.Sprintf("/getArchiveSubmissions?contest_id=%s", contestID),
-		fmt.Sprintf("/getMyArchiveSubmissions?contest_id=%s", contestID),
-		fmt.Sprintf("/archive/%s/submissions", contestID),
-	}
-
-	for _, endpoint := range endpoints {
-		url := "https://94.103.85.238" + endpoint
+	templates := orderEndpoints("archive_submissions", []string{
+		"/getArchiveSubmissions?contest_id=%s",
+		"/getMyArchiveSubmissions?contest_id=%s",
+		"/archive/%s/submissions",
+	})
 
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
+	for _, template := range templates {
+		body, status, err := a.get(ctx, fmt.Sprintf(template, contestID))
+		if isFatalAPIError(err) {
+			return nil, err
+		}
+		if err != nil || status != http.StatusOK {
 			continue
 		}
 
-		req.Host = "api.sort-me.org"
-		req.Header.Set("Authorization", "Bearer "+a.config.SessionToken)
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := insecureClient.Do(req)
+		// Пробуем разные форматы ответа
+		foundSubmissions, err := a.parseArchiveSubmissions(body, contestInfo)
 		if err != nil {
+			a.quarantine("archive_submissions", fmt.Sprintf(template, contestID), body, err)
 			continue
 		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode == http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-
-			// Пробуем разные форматы ответа
-			foundSubmissions, err := a.parseArchiveSubmissions(body, contestInfo)
-			if err == nil && len(foundSubmissions) > 0 {
-				return foundSubmissions, nil
-			}
+		if len(foundSubmissions) > 0 {
+			rememberEndpoint("archive_submissions", template)
+			sortSubmissions(foundSubmissions)
+			return applyPage(foundSubmissions, page), nil
 		}
 	}
 
 	// Если специальные endpoints не работают, пробуем получить отправки через общий метод
-	return a.getSubmissionsViaTasks(contestID, contestInfo, limit)
+	return a.getSubmissionsViaTasks(ctx, contestID, contestInfo, page)
 }
 
 // В методе getSubmissionsViaTasks упростим вывод
-func (a *APIClient) getSubmissionsViaTasks(contestID string, contestInfo *ContestInfo, limit int) ([]Submission, error) {
+func (a *APIClient) getSubmissionsViaTasks(ctx context.Context, contestID string, contestInfo *ContestInfo, page Page) ([]Submission, error) {
 	var allSubmissions []Submission
 
-	for i, task := range contestInfo.Tasks {
-		// Добавляем небольшую задержку между запросами
-		if i > 0 {
-			time.Sleep(100 * time.Millisecond)
-		}
+	perTask, errs := a.fetchTaskSubmissions(ctx, contestID, contestInfo.Name, contestInfo.Tasks, func(task Task) string {
+		return fmt.Sprintf("/getMySubmissionsByTask?id=%d", task.ID)
+	}, page.need())
+	if err := allFailed(errs); err != nil {
+		return nil, err
+	}
+	for _, taskSubmissions := range perTask {
+		allSubmissions = append(allSubmissions, taskSubmissions...)
+	}
 
-		endpoint := fmt.Sprintf("/getMySubmissionsByTask?id=%d", task.ID)
-		taskSubmissions, err := a.tryGetSubmissions(endpoint, 0)
-		if err != nil {
-			continue
-		}
+	sortSubmissions(allSubmissions)
+	return applyPage(allSubmissions, page), nil
+}
 
-		// Добавляем информацию о задаче к каждой отправке
-		for j := range taskSubmissions {
-			taskSubmissions[j].ProblemID = task.ID
-			taskSubmissions[j].ProblemName = task.Name
-			taskSubmissions[j].ContestID = contestID
-			taskSubmissions[j].ContestName = contestInfo.Name
+// Ошибка, если не удался ни один запрос (сервер недоступен), иначе nil
+func allFailed(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	for _, err := range errs {
+		if err == nil {
+			return nil
 		}
-
-		allSubmissions = append(allSubmissions, taskSubmissions...)
 	}
+	return errs[0]
+}
 
-	// Сортируем по ID (более новые сначала)
-	sort.Slice(allSubmissions, func(i, j int) bool {
-		return allSubmissions[i].ID > allSubmissions[j].ID
-	})
+// Страница списка отправок: пропустить Offset самых новых и вернуть не больше Limit (0 - все)
+type Page struct {
+	Offset int
+	Limit  int
+}
 
-	// Применяем лимит
-	if limit > 0 && limit < len(allSubmissions) {
-		return allSubmissions[:limit], nil
+// Сколько самых новых отправок нужно получить, чтобы собрать страницу (0 - все)
+func (p Page) need() int {
+	if p.Limit <= 0 {
+		return 0
 	}
-
-	return allSubmissions, nil
+	return max(p.Offset, 0) + p.Limit
 }
 
-// В методе tryGetSubmissions убедитесь что он получает все отправки
-func (a *APIClient) tryGetSubmissions(endpoint string, limit int) ([]Submission, error) {
-	client := &http.Client{
-		Timeout: 15 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
+// Вырезает страницу из отсортированного списка
+func applyPage[T any](items []T, p Page) []T {
+	if p.Offset > 0 {
+		if p.Offset >= len(items) {
+			return []T{}
+		}
+		items = items[p.Offset:]
+	}
+	if p.Limit > 0 && p.Limit < len(items) {
+		items = items[:p.Limit]
 	}
+	return items
+}
 
-	baseURL := "https://94.103.85.238"
-	fullURL := baseURL + endpoint
+// Сортирует по ID (более новые сначала)
+func sortSubmissions(submissions []Submission) {
+	sort.Slice(submissions, func(i, j int) bool {
+		return submissions[i].ID > submissions[j].ID
+	})
+}
 
-	req, err := http.NewRequest("GET", fullURL, nil)
-	if err != nil {
-		return nil, err
+// Добавляет параметр к endpoint, у которого уже может быть query
+func withQueryParam(endpoint, key string, value int) string {
+	sep := "?"
+	if strings.Contains(endpoint, "?") {
+		sep = "&"
 	}
+	return fmt.Sprintf("%s%s%s=%d", endpoint, sep, key, value)
+}
 
-	req.Host = "api.sort-me.org"
-	req.Header.Set("Authorization", "Bearer "+a.config.SessionToken)
-	req.Header.Set("Accept", "application/json")
+// В методе tryGetSubmissions убедитесь что он получает все отправки
+func (a *APIClient) tryGetSubmissions(ctx context.Context, endpoint string, limit int) ([]Submission, error) {
+	return a.tryGetSubmissionsPage(ctx, endpoint, Page{Limit: limit})
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+// Сервер может отдавать длинный список частями: count в ответе - общее число отправок.
+// Тогда догружаем продолжение через offset, пока не наберем нужное для страницы
+func (a *APIClient) tryGetSubmissionsPage(ctx context.Context, endpoint string, page Page) ([]Submission, error) {
+	var all []Submission
+	seen := make(map[int]bool)
 
-	if resp.StatusCode != http.StatusOK {
-		if resp.StatusCode == 404 {
-			return []Submission{}, nil
+	for {
+		pageEndpoint := endpoint
+		if len(all) > 0 {
+			pageEndpoint = withQueryParam(endpoint, "offset", len(all))
 		}
-		if resp.StatusCode == 429 {
-			time.Sleep(1 * time.Second)
-			return []Submission{}, fmt.Errorf("rate limit")
+
+		body, status, err := a.get(ctx, pageEndpoint)
+		if err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
-	}
 
-	body, _ := io.ReadAll(resp.Body)
+		if status != http.StatusOK {
+			if status == 404 {
+				break
+			}
+			return nil, newAPIError(status, body)
+		}
 
-	var response struct {
-		Count       int          `json:"count"`
-		Submissions []Submission `json:"submissions"`
-	}
+		var response struct {
+			Count       int          `json:"count"`
+			Submissions []Submission `json:"submissions"`
+		}
 
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, err
-	}
+		if err := json.Unmarshal(body, &response); err != nil {
+			a.quarantine("submissions", pageEndpoint, body, err)
+			return nil, err
+		}
 
-	// Сортируем по ID (более новые сначала)
-	sort.Slice(response.Submissions, func(i, j int) bool {
-		return response.Submissions[i].ID > response.Submissions[j].ID
-	})
+		// Сервер, не понимающий offset, вернет то же самое - тогда останавливаемся
+		added := 0
+		for _, sub := range response.Submissions {
+			if !seen[sub.ID] {
+				seen[sub.ID] = true
+				all = append(all, sub)
+				added++
+			}
+		}
 
-	// Если limit не указан, возвращаем все отправки
-	if limit <= 0 {
-		return response.Submissions, nil
+		if added == 0 || response.Count <= len(all) || (page.need() > 0 && len(all) >= page.need()) {
+			break
+		}
 	}
 
-	if limit < len(response.Submissions) {
-		return response.Submissions[:limit], nil
+	if all == nil {
+		all = []Submission{}
 	}
-
-	return response.Submissions, nil
+	sortSubmissions(all)
+	return applyPage(all, page), nil
 }
 
 // В методе GetContestSubmissions упростим вывод
-func (a *APIClient) GetContestSubmissions(contestID string, limit int) ([]Submission, error) {
+func (a *APIClient) GetContestSubmissions(ctx context.Context, contestID string, limit int) ([]Submission, error) {
+	return a.GetContestSubmissionsPage(ctx, contestID, Page{Limit: limit})
+}
+
+// Страница отправок контеста. По каждой задаче запрашиваем только
+// столько самых новых отправок, сколько нужно для страницы
+func (a *APIClient) GetContestSubmissionsPage(ctx context.Context, contestID string, page Page) ([]Submission, error) {
 	if !a.IsAuthenticated() {
-		return nil, fmt.Errorf("not authenticated")
+		return nil, ErrNotAuthenticated
 	}
 
 	// Получаем информацию о контесте
-	contestInfo, err := a.GetContestInfo(contestID)
+	contestInfo, err := a.GetContestInfo(ctx, contestID)
 	if err != nil {
-		return nil, fmt.Errorf("не удалось получить информацию о контесте: %w", err)
+		return nil, fmt.Errorf("%s: %w", T("api.contest_info_failed"), err)
 	}
 
 	// Для архивных контестов используем специальный метод
 	if contestInfo.Status == "archive" {
-		return a.getArchiveContestSubmissions(contestID, contestInfo, limit)
+		return a.getArchiveContestSubmissions(ctx, contestID, contestInfo, page)
 	}
 
 	var allSubmissions []Submission
 
-	// Для обычных контестов используем старый метод
-	for _, task := range contestInfo.Tasks {
-		taskSubmissions, err := a.tryGetSubmissions(fmt.Sprintf("/getMySubmissionsByTask?id=%d&contestid=%s", task.ID, contestID), 0)
-		if err != nil {
-			continue
-		}
-
-		// Добавляем информацию о задаче к каждой отправке
-		for j := range taskSubmissions {
-			taskSubmissions[j].ProblemID = task.ID
-			taskSubmissions[j].ProblemName = task.Name
-			taskSubmissions[j].ContestID = contestID
-			taskSubmissions[j].ContestName = contestInfo.Name
-		}
-
-		allSubmissions = append(allSubmissions, taskSubmissions...)
+	// Для обычных контестов - отправки по каждой задаче
+	perTask, errs := a.fetchTaskSubmissions(ctx, contestID, contestInfo.Name, contestInfo.Tasks, func(task Task) string {
+		return fmt.Sprintf("/getMySubmissionsByTask?id=%d&contestid=%s", task.ID, contestID)
+	}, page.need())
+	if err := allFailed(errs); err != nil {
+		return nil, err
 	}
-
-	// Сортируем по ID (более новые сначала)
-	sort.Slice(allSubmissions, func(i, j int) bool {
-		return allSubmissions[i].ID > allSubmissions[j].ID
-	})
-
-	// Применяем лимит
-	if limit > 0 && limit < len(allSubmissions) {
-		return allSubmissions[:limit], nil
+	for _, taskSubmissions := range perTask {
+		allSubmissions = append(allSubmissions, taskSubmissions...)
 	}
 
-	return allSubmissions, nil
+	sortSubmissions(allSubmissions)
+	return applyPage(allSubmissions, page), nil
 }
 
 // В методе parseArchiveSubmissions убираем неиспользуемую переменную
@@ -336,7 +383,7 @@ func (a *APIClient) parseArchiveSubmissions(body []byte, contestInfo *ContestInf
 	// Формат 1: Прямой массив отправок
 	var directSubmissions []Submission
 	if err := json.Unmarshal(body, &directSubmissions); err == nil && len(directSubmissions) > 0 {
-		fmt.Printf("     📝 Формат: прямой массив отправок\n")
+		logger.Debug("отправки архива", "format", "array")
 		// Обогащаем данные информацией о контесте
 		for i := range directSubmissions {
 			directSubmissions[i].ContestID = fmt.Sprintf("%d", contestInfo.ID) // Конвертируем int в string
@@ -358,7 +405,7 @@ func (a *APIClient) parseArchiveSubmissions(body []byte, contestInfo *ContestInf
 		Count       int          `json:"count"`
 	}
 	if err := json.Unmarshal(body, &withSubmissionsField); err == nil && withSubmissionsField.Submissions != nil {
-		fmt.Printf("     📝 Формат: объект с submissions\n")
+		logger.Debug("отправки архива", "format", "object")
 		for i := range withSubmissionsField.Submissions {
 			withSubmissionsField.Submissions[i].ContestID = fmt.Sprintf("%d", contestInfo.ID) // Конвертируем int в string
 			withSubmissionsField.Submissions[i].ContestName = contestInfo.Name
@@ -372,39 +419,39 @@ func (a *APIClient) parseArchiveSubmissions(body []byte, contestInfo *ContestInf
 		return withSubmissionsField.Submissions, nil
 	}
 
-	return nil, fmt.Errorf("неизвестный формат ответа")
+	return nil, errors.New(T("api.unknown_response"))
 }
 
 // ФИНАЛЬНАЯ РЕАЛИЗАЦИЯ GetContests
-func (a *APIClient) GetContests() ([]Contest, error) {
+func (a *APIClient) GetContests(ctx context.Context) ([]Contest, error) {
 	if !a.IsAuthenticated() {
-		return nil, fmt.Errorf("not authenticated")
+		return nil, ErrNotAuthenticated
 	}
 
-	fmt.Println("🏆 Получение контестов...")
+	progressln(T("api.contests_loading"))
 
 	var allContests []Contest
 
 	// 1. АКТИВНЫЕ И ПРЕДСТОЯЩИЕ КОНТЕСТЫ
-	activeContests, err := a.getUpcomingContests()
+	activeContests, err := a.getUpcomingContests(ctx)
 	if err != nil {
-		fmt.Printf("⚠️ Не удалось получить активные контесты: %v\n", err)
+		progress(T("api.active_failed", err))
 	} else {
 		allContests = append(allContests, activeContests...)
-		fmt.Printf("🎯 Активные/предстоящие контесты: %d\n", len(activeContests))
+		progress(T("api.active_count", len(activeContests)))
 	}
 
 	// 2. АРХИВНЫЕ КОНТЕСТЫ
-	archiveContests, err := a.getArchiveContestsViaIP()
+	archiveContests, err := a.getArchiveContests(ctx)
 	if err != nil {
-		fmt.Printf("⚠️ Не удалось получить архивные контесты: %v\n", err)
+		progress(T("api.archive_failed", err))
 	} else {
 		allContests = append(allContests, archiveContests...)
-		fmt.Printf("📚 Архивные контесты: %d\n", len(archiveContests))
+		progress(T("api.archive_count", len(archiveContests)))
 	}
 
 	if len(allContests) == 0 {
-		return nil, fmt.Errorf("контесты не найдены")
+		return nil, errors.New(T("api.no_contests"))
 	}
 
 	// Обработка результатов
@@ -414,52 +461,40 @@ func (a *APIClient) GetContests() ([]Contest, error) {
 	// Статистика
 	activeCount, archiveCount, upcomingCount := a.countContestsByDetailedStatus(allContests)
 
-	fmt.Printf("✅ Итого: %d контестов\n", len(allContests))
-	fmt.Printf("📊 Активных: %d, Предстоящих: %d, Архивных: %d\n",
-		activeCount, upcomingCount, archiveCount)
+	progress(T("api.contests_total", len(allContests)))
+	progress(T("api.contests_by_status", activeCount, upcomingCount, archiveCount))
 
 	return allContests, nil
 }
 
 // Метод для получения активных/предстоящих контестов
-func (a *APIClient) getUpcomingContests() ([]Contest, error) {
-	insecureClient := &http.Client{
-		Timeout: 15 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-
-	url := "https://94.103.85.238/getUpcomingContests"
-	req, err := http.NewRequest("GET", url, nil)
+func (a *APIClient) getUpcomingContests(ctx context.Context) ([]Contest, error) {
+	upcomingContests, err := a.getUpcomingContestList(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Host = "api.sort-me.org"
-	req.Header.Set("Authorization", "Bearer "+a.config.SessionToken)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := insecureClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
-	}
-
-	body, _ := io.ReadAll(resp.Body)
+	return a.convertUpcomingToContests(upcomingContests), nil
+}
 
-	var upcomingContests []UpcomingContest
-	if err := json.Unmarshal(body, &upcomingContests); err != nil {
-		return nil, err
-	}
+// Ответ сервера кэшируется, а статус (идет/не начался) считается заново по текущему времени
+func (a *APIClient) getUpcomingContestList(ctx context.Context) ([]UpcomingContest, error) {
+	return cached(a, "contests/upcoming", ttlUpcomingContests, func() ([]UpcomingContest, error) {
+		body, status, err := a.get(ctx, "/getUpcomingContests")
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, newAPIError(status, body)
+		}
 
-	return a.convertUpcomingToContests(upcomingContests), nil
+		var upcomingContests []UpcomingContest
+		if err := json.Unmarshal(body, &upcomingContests); err != nil {
+			a.quarantine("upcoming_contests", "/getUpcomingContests", body, err)
+			return nil, err
+		}
+		return upcomingContests, nil
+	})
 }
 
 // Структура для предстоящих контестов
@@ -474,7 +509,7 @@ type UpcomingContest struct {
 // Конвертация в общую структуру Contest
 func (a *APIClient) convertUpcomingToContests(upcoming []UpcomingContest) []Contest {
 	var contests []Contest
-	currentTime := time.Now().Unix()
+	currentTime := a.clock.Now().Unix()
 
 	for _, uc := range upcoming {
 		status := "active"
@@ -492,16 +527,18 @@ func (a *APIClient) convertUpcomingToContests(upcoming []UpcomingContest) []Cont
 			Name:    uc.Name,
 			Status:  status,
 			Started: started,
+			Starts:  uc.Starts,
+			Ends:    uc.Ends,
 		})
 
-		timeStatus := "активный"
+		timeStatus := T("api.status_active")
 		if status == "upcoming" {
-			timeStatus = "предстоящий"
+			timeStatus = T("api.status_upcoming")
 		} else if status == "archive" {
-			timeStatus = "архивный"
+			timeStatus = T("api.status_archive")
 		}
 
-		fmt.Printf("   🎯 %s: %s (%s)\n", uc.Name, fmt.Sprintf("%d", uc.ID), timeStatus)
+		progressf("   🎯 %s: %s (%s)\n", uc.Name, fmt.Sprintf("%d", uc.ID), timeStatus)
 	}
 
 	return contests
@@ -565,38 +602,21 @@ func (a *APIClient) countContestsByDetailedStatus(contests []Contest) (active, a
 
 // Метод для получения архивных контестов (должен уже быть)
 // Метод для получения архивных контестов
-func (a *APIClient) getArchiveContestsViaIP() ([]Contest, error) {
-	insecureClient := &http.Client{
-		Timeout: 15 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-
-	url := "https://94.103.85.238/getArchivePreviews"
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Host = "api.sort-me.org"
-	req.Header.Set("Authorization", "Bearer "+a.config.SessionToken)
-	req.Header.Set("Accept", "application/json")
+func (a *APIClient) getArchiveContests(ctx context.Context) ([]Contest, error) {
+	return cached(a, "contests/archive", ttlArchiveContests, func() ([]Contest, error) {
+		return a.fetchArchiveContests(ctx)
+	})
+}
 
-	resp, err := insecureClient.Do(req)
+func (a *APIClient) fetchArchiveContests(ctx context.Context) ([]Contest, error) {
+	body, status, err := a.get(ctx, "/getArchivePreviews")
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	if status != http.StatusOK {
+		return nil, newAPIError(status, body)
 	}
 
-	body, _ := io.ReadAll(resp.Body)
-
 	var response struct {
 		Count int `json:"count"`
 		Items []struct {
@@ -606,6 +626,7 @@ func (a *APIClient) getArchiveContestsViaIP() ([]Contest, error) {
 	}
 
 	if err := json.Unmarshal(body, &response); err != nil {
+		a.quarantine("archive_previews", "/getArchivePreviews", body, err)
 		return nil, err
 	}
 
@@ -622,116 +643,121 @@ func (a *APIClient) getArchiveContestsViaIP() ([]Contest, error) {
 	return contests, nil
 }
 
-func (a *APIClient) GetContestInfo(contestID string) (*ContestInfo, error) {
+func (a *APIClient) GetContestInfo(ctx context.Context, contestID string) (*ContestInfo, error) {
 	if !a.IsAuthenticated() {
-		return nil, fmt.Errorf("not authenticated")
+		return nil, ErrNotAuthenticated
 	}
 
-	fmt.Printf("📚 Получение информации о контесте %s...\n", contestID)
+	progress(T("api.contest_loading", contestID))
 
 	// Конвертируем ID в число
 	contestIDInt, err := strconv.Atoi(contestID)
 	if err != nil {
-		return nil, fmt.Errorf("неверный ID контеста: %s", contestID)
+		return nil, errors.New(T("api.bad_contest_id", contestID))
 	}
 
 	// Пробуем разные методы для получения информации о контесте
-	return a.getContestInfoUniversal(contestIDInt)
+	return cached(a, "contest/"+contestID+"/info", ttlContestInfo, func() (*ContestInfo, error) {
+		return a.getContestInfoUniversal(ctx, contestIDInt)
+	})
 }
 
-func (a *APIClient) getContestInfoUniversal(contestID int) (*ContestInfo, error) {
-	// Метод 1: Стандартный endpoint для обычных контестов
-	if contestInfo, err := a.tryStandardEndpoint(contestID); err == nil {
-		return contestInfo, nil
+// Регистрация на контест. Повторная регистрация ошибкой не считается
+func (a *APIClient) RegisterForContest(ctx context.Context, contestID string) error {
+	if !a.IsAuthenticated() {
+		return ErrNotAuthenticated
 	}
 
-	// Метод 2: Archive endpoint для архивных контестов
-	if contestInfo, err := a.tryArchiveEndpoint(contestID); err == nil {
-		return contestInfo, nil
+	contestIDInt, err := strconv.Atoi(contestID)
+	if err != nil {
+		return errors.New(T("api.bad_contest_id", contestID))
 	}
 
-	return nil, fmt.Errorf("контест %d недоступен", contestID)
-}
+	body, err := json.Marshal(map[string]int{"id": contestIDInt})
+	if err != nil {
+		return err
+	}
+	req, err := a.newRequest(ctx, "POST", "/registerForContest", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("network error: %w", err)
+	}
+	defer resp.Body.Close()
 
-func (a *APIClient) tryStandardEndpoint(contestID int) (*ContestInfo, error) {
-	insecureClient := &http.Client{
-		Timeout: 15 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent, http.StatusConflict:
+		// После регистрации становятся видны задачи, старая информация о контесте не годится
+		a.cache.Delete("contest/" + contestID + "/info")
+		return nil
+	case http.StatusForbidden:
+		return errors.New(T("api.registration_closed"))
+	default:
+		respBody, _ := io.ReadAll(resp.Body)
+		return newAPIError(resp.StatusCode, respBody)
 	}
+}
 
-	endpoint := fmt.Sprintf("/getContestTasks?id=%d", contestID)
-	url := "https://94.103.85.238" + endpoint
+func (a *APIClient) getContestInfoUniversal(ctx context.Context, contestID int) (*ContestInfo, error) {
+	// Метод 1: Стандартный endpoint для обычных контестов
+	contestInfo, err := a.tryStandardEndpoint(ctx, contestID)
+	if err == nil {
+		return contestInfo, nil
+	}
 
-	fmt.Printf("  📡 Стандартный endpoint: %s\n", endpoint)
+	// Метод 2: Archive endpoint для архивных контестов
+	contestInfo, archiveErr := a.tryArchiveEndpoint(ctx, contestID)
+	if archiveErr == nil {
+		return contestInfo, nil
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
+	// Оба ответили 404 - контеста нет; иначе важнее причина (токен, лимит, сеть)
+	if errors.Is(err, ErrNotFound) && errors.Is(archiveErr, ErrNotFound) {
+		return nil, fmt.Errorf("%s: %w", T("api.contest_unavailable", contestID), ErrNotFound)
 	}
+	if errors.Is(err, ErrNotFound) {
+		err = archiveErr
+	}
+	return nil, fmt.Errorf("%s: %w", T("api.contest_unavailable", contestID), err)
+}
 
-	req.Host = "api.sort-me.org"
-	req.Header.Set("Authorization", "Bearer "+a.config.SessionToken)
-	req.Header.Set("Accept", "application/json")
+func (a *APIClient) tryStandardEndpoint(ctx context.Context, contestID int) (*ContestInfo, error) {
+	endpoint := fmt.Sprintf("/getContestTasks?id=%d", contestID)
+	logger.Debug("контест: стандартный endpoint", "endpoint", endpoint)
 
-	resp, err := insecureClient.Do(req)
+	body, status, err := a.get(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	if status != http.StatusOK {
+		return nil, newAPIError(status, body)
 	}
 
 	var contestInfo ContestInfo
 	if err := json.Unmarshal(body, &contestInfo); err != nil {
+		a.quarantine("contest_tasks", endpoint, body, err)
 		return nil, err
 	}
 
-	fmt.Printf("  ✅ Контест: %s, задач: %d\n", contestInfo.Name, len(contestInfo.Tasks))
+	progress(T("api.contest_loaded", contestInfo.Name, len(contestInfo.Tasks)))
 	return &contestInfo, nil
 }
 
-func (a *APIClient) tryArchiveEndpoint(contestID int) (*ContestInfo, error) {
-	insecureClient := &http.Client{
-		Timeout: 15 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-
+func (a *APIClient) tryArchiveEndpoint(ctx context.Context, contestID int) (*ContestInfo, error) {
 	endpoint := fmt.Sprintf("/getArchiveById?id=%d", contestID)
-	url := "https://94.103.85.238" + endpoint
-
-	fmt.Printf("  📡 Archive endpoint: %s\n", endpoint)
+	logger.Debug("контест: архивный endpoint", "endpoint", endpoint)
 
-	req, err := http.NewRequest("GET", url, nil)
+	body, status, err := a.get(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Host = "api.sort-me.org"
-	req.Header.Set("Authorization", "Bearer "+a.config.SessionToken)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := insecureClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	if status != http.StatusOK {
+		return nil, newAPIError(status, body)
 	}
 
 	// Парсим архивные данные
@@ -746,7 +772,8 @@ func (a *APIClient) tryArchiveEndpoint(contestID int) (*ContestInfo, error) {
 	}
 
 	if err := json.Unmarshal(body, &archiveData); err != nil {
-		return nil, fmt.Errorf("ошибка парсинга: %w", err)
+		a.quarantine("archive_contest", endpoint, body, err)
+		return nil, fmt.Errorf("%s: %w", T("api.parse_error"), err)
 	}
 
 	// Собираем все задачи из всех seasons
@@ -755,8 +782,7 @@ func (a *APIClient) tryArchiveEndpoint(contestID int) (*ContestInfo, error) {
 		allTasks = append(allTasks, season.Tasks...)
 	}
 
-	fmt.Printf("  ✅ Архивный контест: %s, seasons: %d, задач: %d\n",
-		archiveData.Name, len(archiveData.Seasons), len(allTasks))
+	progress(T("api.archive_loaded", archiveData.Name, len(archiveData.Seasons), len(allTasks)))
 
 	return &ContestInfo{
 		ID:     archiveData.ID,
@@ -767,13 +793,20 @@ func (a *APIClient) tryArchiveEndpoint(contestID int) (*ContestInfo, error) {
 }
 
 func NewAPIClient(config *Config) *APIClient {
+	return newAPIClientWith(config, apiDeps{})
+}
+
+func newAPIClientWith(config *Config, deps apiDeps) *APIClient {
+	if deps.Clock == nil {
+		deps.Clock = systemClock{}
+	}
 	return &APIClient{
-		config: config,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		// ПРАВИЛЬНЫЙ BASE URL - API сервер
-		baseURL: "https://api.sort-me.org",
+		config:    config,
+		transport: newAPITransport(config, deps),
+		usage:     &usageRecorder{},
+		cache:     NewCache(),
+		limiter:   newRequestLimiter(config.RequestsPerSecond, deps.Clock),
+		clock:     deps.Clock,
 	}
 }
 
@@ -792,28 +825,14 @@ func cleanSubmissionID(submissionID string) string {
 	return submissionID
 }
 
-func (a *APIClient) SubmitSolution(contestID, problemID, language, sourceCode string) (*SubmitResponse, error) {
+func (a *APIClient) SubmitSolution(ctx context.Context, contestID, problemID, language, sourceCode string) (*SubmitResponse, error) {
 	if !a.IsAuthenticated() {
-		return nil, fmt.Errorf("not authenticated")
+		return nil, ErrNotAuthenticated
 	}
 
-	// Конвертируем строки в числа
-	contestIDInt, err := strconv.Atoi(contestID)
+	requestData, err := newSubmitRequest(contestID, problemID, language, sourceCode)
 	if err != nil {
-		return nil, fmt.Errorf("invalid contest ID: %s", contestID)
-	}
-
-	problemIDInt, err := strconv.Atoi(problemID)
-	if err != nil {
-		return nil, fmt.Errorf("invalid problem ID: %s", problemID)
-	}
-
-	// Правильная структура с числами
-	requestData := SubmitRequest{
-		TaskID:    problemIDInt,
-		Lang:      language,
-		Code:      sourceCode,
-		ContestID: contestIDInt,
+		return nil, err
 	}
 
 	jsonData, err := json.Marshal(requestData)
@@ -821,38 +840,50 @@ func (a *APIClient) SubmitSolution(contestID, problemID, language, sourceCode st
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	fmt.Printf("📡 Отправка решения...\n")
-	fmt.Printf("📦 Данные: contest_id=%d, task_id=%d, lang=%s\n", contestIDInt, problemIDInt, language)
+	progressln(T("api.submitting"))
+	logger.Info("отправка решения", "contest_id", requestData.ContestID, "task_id", requestData.TaskID,
+		"lang", language, "size", len(sourceCode))
+
+	if shouldUploadChunked(len(sourceCode)) {
+		response, err := a.submitChunked(ctx, requestData)
+		if !errors.Is(err, errChunkedUnsupported) {
+			return response, err
+		}
+		progressln(T("api.chunked_unsupported"))
+	}
 
-	// Используем прямое IP подключение для отправки
-	return a.submitViaIP(jsonData)
+	return a.submitSolutionRequest(ctx, jsonData)
 }
 
-func (a *APIClient) submitViaIP(jsonData []byte) (*SubmitResponse, error) {
-	insecureClient := &http.Client{
-		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
+// Тело запроса /submit: сервер ждет ID числами
+func newSubmitRequest(contestID, problemID, language, sourceCode string) (SubmitRequest, error) {
+	contestIDInt, err := strconv.Atoi(contestID)
+	if err != nil {
+		return SubmitRequest{}, fmt.Errorf("invalid contest ID: %s", contestID)
 	}
 
-	url := "https://94.103.85.238/submit"
-	fmt.Printf("🌐 Отправка через IP: %s\n", url)
+	problemIDInt, err := strconv.Atoi(problemID)
+	if err != nil {
+		return SubmitRequest{}, fmt.Errorf("invalid problem ID: %s", problemID)
+	}
+
+	return SubmitRequest{
+		TaskID:    problemIDInt,
+		Lang:      language,
+		Code:      sourceCode,
+		ContestID: contestIDInt,
+	}, nil
+}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+func (a *APIClient) submitSolutionRequest(ctx context.Context, jsonData []byte) (*SubmitResponse, error) {
+	req, err := a.newRequest(ctx, "POST", "/submit", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+a.config.SessionToken)
-	req.Host = "api.sort-me.org"
+	logger.Debug("токен", "token", maskToken(a.config.SessionToken))
 
-	fmt.Printf("🔑 Используется токен: %s\n", maskToken(a.config.SessionToken))
-
-	resp, err := insecureClient.Do(req)
+	resp, err := a.doRequest(req)
 	if err != nil {
 		return nil, fmt.Errorf("network error: %w", err)
 	}
@@ -860,17 +891,21 @@ func (a *APIClient) submitViaIP(jsonData []byte) (*SubmitResponse, error) {
 
 	body, _ := io.ReadAll(resp.Body)
 
-	fmt.Printf("📥 Ответ сервера: Status %d\n", resp.StatusCode)
-	fmt.Printf("📦 Тело ответа: %s\n", string(body)) // Добавьте это для отладки
+	logger.Debug("ответ на отправку", "status", resp.StatusCode, "body", logBody(body))
+
+	return parseSubmitResponse(resp.StatusCode, body)
+}
 
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("API вернул ошибку %d: %s", resp.StatusCode, string(body))
+// Разбирает ответ на отправку решения: сервер возвращает ID в разных форматах
+func parseSubmitResponse(statusCode int, body []byte) (*SubmitResponse, error) {
+	if statusCode >= 400 {
+		return nil, newAPIError(statusCode, body)
 	}
 
 	var apiResponse SubmitResponse
 	if err := json.Unmarshal(body, &apiResponse); err != nil {
 		// Если не можем распарсить JSON, но статус успешный - пробуем извлечь ID из ответа
-		if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated {
+		if statusCode == http.StatusOK || statusCode == http.StatusCreated {
 			// Пробуем распарсить как объект с полем id
 			var responseObj map[string]interface{}
 			if err := json.Unmarshal(body, &responseObj); err == nil {
@@ -878,7 +913,7 @@ func (a *APIClient) submitViaIP(jsonData []byte) (*SubmitResponse, error) {
 					// Конвертируем ID в строку независимо от его типа
 					apiResponse.ID = fmt.Sprintf("%v", id)
 					apiResponse.Status = "submitted"
-					apiResponse.Message = "Решение успешно отправлено"
+					apiResponse.Message = T("api.submitted")
 					return &apiResponse, nil
 				}
 			}
@@ -887,7 +922,7 @@ func (a *APIClient) submitViaIP(jsonData []byte) (*SubmitResponse, error) {
 			return &SubmitResponse{
 				ID:      string(body),
 				Status:  "submitted",
-				Message: "Решение успешно отправлено",
+				Message: T("api.submitted"),
 			}, nil
 		}
 		return nil, fmt.Errorf("failed to parse response: %w", err)
@@ -907,104 +942,155 @@ func (a *APIClient) submitViaIP(jsonData []byte) (*SubmitResponse, error) {
 	return &apiResponse, nil
 }
 
-func (a *APIClient) getStatusViaWebSocket(submissionID string) (*SubmissionStatus, error) {
-	// Создаем WebSocket URL с IP
-	wsURL := "wss://94.103.85.238/ws/submission?id=" + submissionID + "&token=" + a.config.SessionToken
+func (a *APIClient) getStatusViaWebSocket(ctx context.Context, submissionID string, onUpdate func(*SubmissionStatus)) (*SubmissionStatus, error) {
+	progressln(T("api.waiting_final"))
+
+	status, err := a.watchSubmission(ctx, submissionID, func(status *SubmissionStatus) {
+		progress(T("api.current_status", getStatusEmoji(status.Status)))
+		if status.QueuePosition > 0 {
+			progress(T("api.queue_position", status.QueuePosition))
+		}
+		if status.Score > 0 {
+			progress(T("api.points", status.Score))
+		}
+		if status.Time != "" {
+			progressf(" ⏱️ %s", status.Time)
+		}
+		if status.Memory != "" {
+			progressf(" 💾 %s", status.Memory)
+		}
+		progressln()
+		if onUpdate != nil {
+			onUpdate(status)
+		}
+	})
+	if err == nil && a.isFinalStatus(status.Status) {
+		progress(T("api.final_status", getStatusEmoji(status.Status)))
+	}
+	return status, err
+}
+
+// Слушает WebSocket отправки до финального вердикта и передает в onUpdate
+// каждый промежуточный статус. Если вердикт не пришел за отведенное время,
+// возвращает последний известный статус (он не финальный)
+func (a *APIClient) watchSubmission(ctx context.Context, submissionID string, onUpdate func(*SubmissionStatus)) (*SubmissionStatus, error) {
+	endpoint := "/ws/submission?id=" + submissionID + "&token=" + a.config.SessionToken
 
-	fmt.Printf("🔗 WebSocket URL: wss://api.sort-me.org/ws/submission?id=%s&token=%s\n",
-		submissionID, maskToken(a.config.SessionToken))
+	queue := newQueueTracker(a.clock)
+	var lastStatus *SubmissionStatus
 
-	// Создаем соединение
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true,
-		},
+	for {
+		status, err := a.readSubmissionUpdates(ctx, endpoint, submissionID, queue, onUpdate, &lastStatus)
+		if !errors.Is(err, errStatusTimeout) {
+			return status, err
+		}
+
+		// Решение стоит в загруженной очереди: не сдаемся, а переподключаемся все реже
+		delay, ok := queue.reconnectDelay()
+		if !ok {
+			if lastStatus != nil {
+				progress(T("api.timeout_last_status", lastStatus.Status))
+				return lastStatus, nil
+			}
+			return nil, err
+		}
+		progress(T("api.queue_reconnect", delay))
+		if err := sleepClock(ctx, a.clock, delay); err != nil {
+			return lastStatus, err
+		}
 	}
+}
 
-	conn, _, err := dialer.Dial(wsURL, nil)
+var errStatusTimeout error = localizedError("api.status_timeout")
+
+// Одно подключение к WebSocket: возвращает финальный статус, ошибку
+// или errStatusTimeout, если сообщения перестали приходить
+func (a *APIClient) readSubmissionUpdates(ctx context.Context, endpoint, submissionID string, queue *queueTracker, onUpdate func(*SubmissionStatus), lastStatus **SubmissionStatus) (*SubmissionStatus, error) {
+	conn, err := a.dialWebSocket(ctx, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("WebSocket connection failed: %w", err)
 	}
 	defer conn.Close()
+	defer closeOnCancel(ctx, conn)()
 
-	fmt.Println("✅ WebSocket подключен успешно")
-	fmt.Println("⏳ Ожидаем финальный статус...")
-
-	// Устанавливаем общий таймаут 60 секунд
-	conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-
-	var lastStatus *SubmissionStatus
+	// Первое сообщение ждем дольше, дальше - по загруженности очереди
+	keepalive := startKeepAlive(conn, wsFirstMessageTimeout)
+	defer keepalive.Stop()
 
 	// Читаем сообщения пока не получим финальный статус или не истечет время
 	for {
 		messageType, message, err := conn.ReadMessage()
 		if err != nil {
-			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-				if lastStatus != nil {
-					fmt.Printf("⏰ Таймаут, возвращаем последний известный статус: %s\n", lastStatus.Status)
-					return lastStatus, nil
-				}
-				return nil, fmt.Errorf("таймаут ожидания статуса")
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() || isServerRestartClose(err) {
+				return nil, errStatusTimeout
+			}
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) {
+				return nil, fmt.Errorf("%s: %w", T("api.ws_closed"), err)
 			}
 			return nil, fmt.Errorf("WebSocket read error: %w", err)
 		}
 
-		if messageType == websocket.TextMessage {
-			fmt.Printf("📨 Получено сообщение (%d байт)\n", len(message))
+		logger.Debug("кадр websocket", "type", messageType, "data", logBody(message))
+		a.tape.frame(conn, message)
 
+		if messageType == websocket.TextMessage {
 			// Парсим полученное сообщение
 			status, err := a.parseWebSocketMessage(message)
 			if err != nil {
-				fmt.Printf("❌ Ошибка парсинга: %v\n", err)
+				progress(T("api.ws_parse_error", err))
+				a.quarantine("ws_submission", "/ws/submission?id="+submissionID, message, err)
 				continue
 			}
 			status.ID = submissionID
-			lastStatus = status
-
-			// Выводим текущий статус
-			fmt.Printf("📊 Текущий статус: %s", getStatusEmoji(status.Status))
-			if status.Score > 0 {
-				fmt.Printf(" (%d баллов)", status.Score)
-			}
-			if status.Time != "" {
-				fmt.Printf(" ⏱️ %s", status.Time)
-			}
-			if status.Memory != "" {
-				fmt.Printf(" 💾 %s", status.Memory)
-			}
-			fmt.Println()
+			*lastStatus = status
 
 			// Проверяем финальный ли это статус
 			if a.isFinalStatus(status.Status) {
-				fmt.Printf("🎯 Получен финальный статус: %s\n", getStatusEmoji(status.Status))
 				return status, nil
 			}
+			if queue.observe(status) {
+				progress(T("api.queue_congested", queueMaxWait))
+			}
+			if onUpdate != nil {
+				onUpdate(status)
+			}
 
 			// Обновляем таймаут для следующего чтения
-			conn.SetReadDeadline(time.Now().Add(30 * time.Second))
+			keepalive.message(queue.timeout())
 		}
 	}
 }
 
 func (a *APIClient) parseWebSocketMessage(message []byte) (*SubmissionStatus, error) {
-	fmt.Printf("🔍 Парсим WebSocket сообщение...\n")
-
-	// Пробуем распарсить как SubmissionResult
-	var result SubmissionResult
-	if err := json.Unmarshal(message, &result); err == nil {
-		fmt.Printf("✅ Успешно распарсено как SubmissionResult\n")
-		return a.convertResultToStatus(result), nil
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(message, &fields); err != nil {
+		return nil, errors.New(T("api.unknown_message"))
+	}
+
+	// Итог проверки (SubmissionResult) узнаем по его полям: любой объект разбирается
+	// в структуру без ошибки, и промежуточный статус иначе выглядел бы как CE
+	_, hasCompiled := fields["compiled"]
+	_, hasVerdict := fields["shown_verdict"]
+	if hasCompiled || hasVerdict {
+		var result SubmissionResult
+		if err := json.Unmarshal(message, &result); err == nil {
+			// Без поля compiled ошибкой компиляции ответ не считаем
+			result.Compiled = result.Compiled || !hasCompiled
+			return a.convertResultToStatus(result), nil
+		}
 	}
 
 	// Пробуем распарсить как WSMessage
 	var wsMessage WSMessage
 	if err := json.Unmarshal(message, &wsMessage); err == nil {
-		fmt.Printf("✅ Успешно распарсено как WSMessage\n")
 		return a.parseStatusMessage(wsMessage), nil
 	}
 
-	return nil, fmt.Errorf("неизвестный формат сообщения")
+	return nil, errors.New(T("api.unknown_message"))
 }
 
 func (a *APIClient) convertResultToStatus(result SubmissionResult) *SubmissionStatus {
@@ -1029,6 +1115,10 @@ func (a *APIClient) convertResultToStatus(result SubmissionResult) *SubmissionSt
 	if len(result.Subtasks) > 0 {
 		status.Time = fmt.Sprintf("%d ms", result.Subtasks[0].WorstTime)
 	}
+	status.Subtasks = result.Subtasks
+	if status.Status != "accepted" {
+		status.FailedTest = result.ShownTest
+	}
 
 	return status
 }
@@ -1045,8 +1135,6 @@ func (a *APIClient) parseStatusMessage(message WSMessage) *SubmissionStatus {
 
 	// Парсим данные если они есть
 	if data, ok := message.Data.(map[string]interface{}); ok {
-		fmt.Printf("🔍 Данные: %+v\n", data)
-
 		if id, exists := data["id"]; exists {
 			status.ID = fmt.Sprintf("%v", id)
 		}
@@ -1067,6 +1155,18 @@ func (a *APIClient) parseStatusMessage(message WSMessage) *SubmissionStatus {
 		if memory, exists := data["memory"]; exists {
 			status.Memory = fmt.Sprintf("%v", memory)
 		}
+		for _, key := range []string{"test", "shown_test", "current_test"} {
+			if test, ok := data[key].(float64); ok {
+				status.Test = int(test)
+				break
+			}
+		}
+		for _, key := range []string{"queue_position", "position", "queue"} {
+			if position, ok := data[key].(float64); ok {
+				status.QueuePosition = int(position)
+				break
+			}
+		}
 	}
 
 	// Если ID пустой, используем submission ID из параметров
@@ -1080,7 +1180,7 @@ func (a *APIClient) parseStatusMessage(message WSMessage) *SubmissionStatus {
 func (a *APIClient) isFinalStatus(status string) bool {
 	finalStatuses := []string{
 		"accepted", "wrong_answer", "time_limit_exceeded",
-		"memory_limit_exceeded", "compilation_error", "runtime_error",
+		"memory_limit_exceeded", "compilation_error", "runtime_error", "partial",
 		"AC", "WA", "TLE", "MLE", "CE", "RE",
 	}
 
@@ -1093,78 +1193,69 @@ func (a *APIClient) isFinalStatus(status string) bool {
 }
 
 // Методы для списка отправок
-func (a *APIClient) GetSubmissions(limit int) ([]Submission, error) {
+func (a *APIClient) GetSubmissions(ctx context.Context, limit int) ([]Submission, error) {
 	if !a.IsAuthenticated() {
-		return nil, fmt.Errorf("not authenticated")
+		return nil, ErrNotAuthenticated
 	}
 
 	// Получаем отправки только из активных контестов
-	return a.getAllSubmissions(limit)
+	return a.getAllSubmissions(ctx, limit)
 }
 
 // Быстрый метод для получения последних отправок
-func (a *APIClient) GetRecentSubmissions(limit int) ([]Submission, error) {
+func (a *APIClient) GetRecentSubmissions(ctx context.Context, limit int) ([]Submission, error) {
 	if !a.IsAuthenticated() {
-		return nil, fmt.Errorf("not authenticated")
+		return nil, ErrNotAuthenticated
 	}
 
-	fmt.Printf("🔍 Поиск %d последних отправок...\n", limit)
+	progress(T("api.recent_searching", limit))
 
 	// Пробуем получить отправки только из доступных контестов
-	contests, err := a.GetContests()
+	contests, err := a.GetContests(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	// Берем только первые 2 контеста для скорости
-	if len(contests) > 2 {
-		contests = contests[:2]
+	maxContests := a.pageSize(2, 1)
+	if len(contests) > maxContests {
+		contests = contests[:maxContests]
 	}
 
 	var allSubmissions []Submission
 
 	for _, contest := range contests {
-		fmt.Printf("📚 Контест: %s... ", contest.Name)
+		progress(T("api.recent_contest", contest.Name))
 
 		// Получаем только первые 3 задачи контеста
-		contestInfo, err := a.GetContestInfo(contest.ID)
+		contestInfo, err := a.GetContestInfo(ctx, contest.ID)
 		if err != nil {
-			fmt.Printf("❌\n")
+			progressf("❌\n")
 			continue
 		}
 
 		if len(contestInfo.Tasks) == 0 {
-			fmt.Printf("📭\n")
+			progressf("📭\n")
 			continue
 		}
 
 		// Ограничиваем количество задач
-		maxTasks := 3
+		maxTasks := a.pageSize(3, 2)
 		if len(contestInfo.Tasks) > maxTasks {
 			contestInfo.Tasks = contestInfo.Tasks[:maxTasks]
 		}
 
 		var contestSubmissions []Submission
 
-		for _, task := range contestInfo.Tasks {
-			// Получаем только последние 2 отправки для каждой задачи
-			submissions, err := a.tryGetSubmissions(fmt.Sprintf("/getMySubmissionsByTask?id=%d&contestid=%s", task.ID, contest.ID), 2)
-			if err != nil {
-				continue
-			}
-
-			// Добавляем информацию о задаче
-			for i := range submissions {
-				submissions[i].ProblemID = task.ID
-				submissions[i].ProblemName = task.Name
-				submissions[i].ContestID = contest.ID
-				submissions[i].ContestName = contestInfo.Name
-			}
-
+		// Получаем только последние 2 отправки для каждой задачи
+		perTask, _ := a.fetchTaskSubmissions(ctx, contest.ID, contestInfo.Name, contestInfo.Tasks, func(task Task) string {
+			return fmt.Sprintf("/getMySubmissionsByTask?id=%d&contestid=%s", task.ID, contest.ID)
+		}, a.pageSize(2, 1))
+		for _, submissions := range perTask {
 			contestSubmissions = append(contestSubmissions, submissions...)
 		}
 
-		fmt.Printf("✅ %d отправок\n", len(contestSubmissions))
+		progress(T("api.recent_count", len(contestSubmissions)))
 		allSubmissions = append(allSubmissions, contestSubmissions...)
 	}
 
@@ -1182,73 +1273,60 @@ func (a *APIClient) GetRecentSubmissions(limit int) ([]Submission, error) {
 }
 
 // Получить все отправки (оптимизированная версия)
-func (a *APIClient) getAllSubmissions(limit int) ([]Submission, error) {
+func (a *APIClient) getAllSubmissions(ctx context.Context, limit int) ([]Submission, error) {
 	// Получаем реальные контесты через API
-	contests, err := a.GetContests()
+	contests, err := a.GetContests(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("не удалось получить список контестов: %w", err)
+		return nil, fmt.Errorf("%s: %w", T("api.contests_failed"), err)
 	}
 
 	var allSubmissions []Submission
 
-	fmt.Printf("🔍 Поиск отправок в %d контестах...\n", len(contests))
+	progress(T("api.all_searching", len(contests)))
 
 	// Ограничиваем количество проверяемых контестов для скорости
-	maxContests := 3
+	maxContests := a.pageSize(3, 1)
 	if len(contests) > maxContests {
-		fmt.Printf("⚠️  Ограничиваем до %d контестов для скорости\n", maxContests)
+		progress(T("api.all_limited", maxContests))
 		contests = contests[:maxContests]
 	}
 
 	for i, contest := range contests {
-		fmt.Printf("📚 Контест %d/%d: %s\n", i+1, len(contests), contest.Name)
+		progress(T("api.all_contest", i+1, len(contests), contest.Name))
 
 		// Получаем информацию о контесте
-		contestInfo, err := a.GetContestInfo(contest.ID)
+		contestInfo, err := a.GetContestInfo(ctx, contest.ID)
 		if err != nil {
-			fmt.Printf("   ⚠️  Не удалось получить задачи: %v\n", err)
+			progress(T("api.all_tasks_failed", err))
 			continue
 		}
 
-		fmt.Printf("📚 Задачи контеста (%d): ", len(contestInfo.Tasks))
+		progress(T("api.all_tasks", len(contestInfo.Tasks)))
 
 		var contestSubmissions []Submission
 
 		// Ограничиваем количество проверяемых задач для скорости
-		maxTasks := 5
+		maxTasks := a.pageSize(5, 2)
 		tasksToCheck := contestInfo.Tasks
 		if len(tasksToCheck) > maxTasks {
 			tasksToCheck = tasksToCheck[:maxTasks]
 		}
 
-		// Последовательно получаем отправки для каждой задачи
-		for j, task := range tasksToCheck {
-			// Увеличиваем задержку чтобы избежать rate limiting
-			if j > 0 {
-				time.Sleep(500 * time.Millisecond) // Увеличили до 500мс
-			}
-
-			taskSubmissions, err := a.tryGetSubmissions(fmt.Sprintf("/getMySubmissionsByTask?id=%d&contestid=%s", task.ID, contest.ID), 5) // Ограничиваем 5 отправок на задачу
-			if err != nil {
-				fmt.Printf("❌") // Просто крестик без текста
+		// Отправки задач загружаем параллельно, а отметки выводим в порядке задач
+		perTask, errs := a.fetchTaskSubmissions(ctx, contest.ID, contestInfo.Name, tasksToCheck, func(task Task) string {
+			return fmt.Sprintf("/getMySubmissionsByTask?id=%d&contestid=%s", task.ID, contest.ID)
+		}, a.pageSize(5, 2)) // Ограничиваем отправки на задачу
+		for j, taskSubmissions := range perTask {
+			if errs[j] != nil {
+				progressf("❌") // Просто крестик без текста
 				continue
 			}
-
-			fmt.Printf("✅") // Галочка для успешной загрузки
-
-			// Добавляем информацию о задаче к каждой отправке
-			for k := range taskSubmissions {
-				taskSubmissions[k].ProblemID = task.ID
-				taskSubmissions[k].ProblemName = task.Name
-				taskSubmissions[k].ContestID = contest.ID
-				taskSubmissions[k].ContestName = contestInfo.Name
-			}
-
+			progressf("✅") // Галочка для успешной загрузки
 			contestSubmissions = append(contestSubmissions, taskSubmissions...)
 		}
 
 		allSubmissions = append(allSubmissions, contestSubmissions...)
-		fmt.Printf(" | %d отправок\n", len(contestSubmissions))
+		progress(T("api.all_count", len(contestSubmissions)))
 	}
 
 	// Сортируем по ID (более новые сначала)
@@ -1256,7 +1334,7 @@ func (a *APIClient) getAllSubmissions(limit int) ([]Submission, error) {
 		return allSubmissions[i].ID > allSubmissions[j].ID
 	})
 
-	fmt.Printf("\n🎯 Итого: %d отправок\n", len(allSubmissions))
+	progress(T("api.all_total", len(allSubmissions)))
 
 	// Применяем лимит
 	if limit > 0 && limit < len(allSubmissions) {
@@ -1266,30 +1344,80 @@ func (a *APIClient) getAllSubmissions(limit int) ([]Submission, error) {
 	return allSubmissions, nil
 }
 
+// Прогресс пользователя по задачам контеста
+type ContestProgress struct {
+	Solved     map[int]bool // ID задачи -> решена
+	Points     map[int]int  // ID задачи -> лучший балл
+	Attempts   map[int]int  // ID задачи -> количество отправок
+	LastTaskID int          // Задача последней отправки (0 если отправок нет)
+}
+
+func (p *ContestProgress) SolvedCount() int {
+	count := 0
+	for _, solved := range p.Solved {
+		if solved {
+			count++
+		}
+	}
+	return count
+}
+
+// Собирает прогресс по всем задачам контеста одним проходом по отправкам
+func (a *APIClient) GetContestProgress(ctx context.Context, contestID string, tasks []Task) (*ContestProgress, error) {
+	if !a.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	progress := &ContestProgress{
+		Solved:   make(map[int]bool),
+		Points:   make(map[int]int),
+		Attempts: make(map[int]int),
+	}
+	lastSubmissionID := 0
+
+	for _, task := range tasks {
+		endpoint := fmt.Sprintf("/getMySubmissionsByTask?id=%d&contestid=%s", task.ID, contestID)
+		submissions, err := a.tryGetSubmissions(ctx, endpoint, 0)
+		if err != nil {
+			continue
+		}
+
+		progress.Attempts[task.ID] = len(submissions)
+		for _, sub := range submissions {
+			if sub.TotalPoints > progress.Points[task.ID] {
+				progress.Points[task.ID] = sub.TotalPoints
+			}
+			if sub.TotalPoints == 100 || sub.ShownVerdict == 1 {
+				progress.Solved[task.ID] = true
+			}
+			if sub.ID > lastSubmissionID {
+				lastSubmissionID = sub.ID
+				progress.LastTaskID = task.ID
+			}
+		}
+	}
+
+	return progress, nil
+}
+
+// Размер выборки с учетом режима экономии трафика
+func (a *APIClient) pageSize(normal, lowBandwidth int) int {
+	if a.config.LowBandwidth {
+		return lowBandwidth
+	}
+	return normal
+}
+
 func (a *APIClient) IsAuthenticated() bool {
 	return a.config.SessionToken != "" && a.config.UserID != ""
 }
 
 func (a *APIClient) DetectLanguage(filename string) string {
-	ext := filepath.Ext(filename)
-	switch ext {
-	case ".py":
-		return "python"
-	case ".java":
-		return "java"
-	case ".cpp", ".cc", ".cxx":
-		return "c++"
-	case ".c":
-		return "c"
-	case ".go":
-		return "go"
-	case ".js":
-		return "javascript"
-	case ".rs":
-		return "rust"
-	default:
-		return "unknown"
+	var languages map[string]LanguageConfig
+	if a.config != nil {
+		languages = a.config.Languages
 	}
+	return detectLanguage(filename, languages)
 }
 
 func ReadSourceCode(filename string) (string, error) {
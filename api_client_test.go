@@ -0,0 +1,448 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Часы, которые не ждут: After сразу срабатывает и сдвигает время,
+// запрошенные паузы запоминаются для проверки
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+	// Вызывается вместо паузы; вернуть nil - пауза не закончится никогда
+	after func(d time.Duration) <-chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	now, after := c.now, c.after
+	c.mu.Unlock()
+	if after != nil {
+		return after(d)
+	}
+	ch := make(chan time.Time, 1)
+	ch <- now
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// Сервер из заранее заданных ответов: i-й запрос получает i-й ответ, последний повторяется
+type fakeAPI struct {
+	mu        sync.Mutex
+	responses []func(*http.Request) (*http.Response, error)
+	requests  []*http.Request
+}
+
+func (f *fakeAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	respond := f.responses[min(len(f.requests), len(f.responses))-1]
+	f.mu.Unlock()
+	return respond(req)
+}
+
+func (f *fakeAPI) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests)
+}
+
+func reply(status int, body string, header ...string) func(*http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		resp := &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}
+		for i := 0; i+1 < len(header); i += 2 {
+			resp.Header.Set(header[i], header[i+1])
+		}
+		return resp, nil
+	}
+}
+
+func fail(err error) func(*http.Request) (*http.Response, error) {
+	return func(*http.Request) (*http.Response, error) { return nil, err }
+}
+
+// Сетевой таймаут, как у net/http при истекшем Client.Timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func newTestClient(t *testing.T, deps apiDeps) *APIClient {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	quiet := quietMode
+	quietMode = true
+	t.Cleanup(func() { quietMode = quiet })
+
+	client := newAPIClientWith(&Config{
+		APIBaseURL:   "https://api.test",
+		SessionToken: "token",
+		UserID:       "1",
+		MaxRetries:   3,
+		BackoffBase:  100 * time.Millisecond,
+	}, deps)
+	client.cache.disabled = true
+	return client
+}
+
+func TestRetryBacksOffOnServerErrors(t *testing.T) {
+	api := &fakeAPI{responses: []func(*http.Request) (*http.Response, error){
+		reply(http.StatusServiceUnavailable, ""),
+		reply(http.StatusBadGateway, ""),
+		reply(http.StatusOK, `{"ok":true}`),
+	}}
+	clock := newFakeClock()
+	client := newTestClient(t, apiDeps{Transport: api, Clock: clock})
+
+	body, status, err := client.get(context.Background(), "/contests")
+	if err != nil || status != http.StatusOK || string(body) != `{"ok":true}` {
+		t.Fatalf("get = %q, %d, %v", body, status, err)
+	}
+	if api.calls() != 3 {
+		t.Errorf("запросов %d, ожидалось 3", api.calls())
+	}
+	if len(clock.sleeps) != 2 {
+		t.Fatalf("пауз %v, ожидалось 2", clock.sleeps)
+	}
+	// base * 2^attempt плюс добавка до половины
+	for attempt, delay := range clock.sleeps {
+		base := 100 * time.Millisecond << attempt
+		if delay < base || delay > base+base/2 {
+			t.Errorf("пауза %d: %s, ожидалось от %s до %s", attempt, delay, base, base+base/2)
+		}
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	api := &fakeAPI{responses: []func(*http.Request) (*http.Response, error){
+		reply(http.StatusTooManyRequests, "", "Retry-After", "7"),
+		reply(http.StatusOK, `[]`),
+	}}
+	clock := newFakeClock()
+	client := newTestClient(t, apiDeps{Transport: api, Clock: clock})
+
+	if _, _, err := client.get(context.Background(), "/contests"); err != nil {
+		t.Fatal(err)
+	}
+	if len(clock.sleeps) != 1 || clock.sleeps[0] != 7*time.Second {
+		t.Errorf("паузы %v, ожидалось [7s]", clock.sleeps)
+	}
+}
+
+func TestRateLimitedAfterAllRetries(t *testing.T) {
+	api := &fakeAPI{responses: []func(*http.Request) (*http.Response, error){
+		reply(http.StatusTooManyRequests, "", "Retry-After", "2"),
+	}}
+	client := newTestClient(t, apiDeps{Transport: api, Clock: newFakeClock()})
+
+	_, _, err := client.get(context.Background(), "/contests")
+	var rateLimited *ErrRateLimited
+	if !errors.As(err, &rateLimited) || rateLimited.RetryAfter != 2*time.Second {
+		t.Fatalf("err = %v, ожидался ErrRateLimited через 2s", err)
+	}
+	if api.calls() != 4 {
+		t.Errorf("запросов %d, ожидалось 4 (первый и 3 повтора)", api.calls())
+	}
+}
+
+func TestRetryOnNetworkTimeout(t *testing.T) {
+	api := &fakeAPI{responses: []func(*http.Request) (*http.Response, error){
+		fail(timeoutError{}),
+		reply(http.StatusOK, `{}`),
+	}}
+	client := newTestClient(t, apiDeps{Transport: api, Clock: newFakeClock()})
+
+	if _, _, err := client.get(context.Background(), "/contests"); err != nil {
+		t.Fatal(err)
+	}
+	if api.calls() != 2 {
+		t.Errorf("запросов %d, ожидалось 2", api.calls())
+	}
+}
+
+func TestSubmitNotRetriedAfterTimeout(t *testing.T) {
+	api := &fakeAPI{responses: []func(*http.Request) (*http.Response, error){
+		fail(timeoutError{}),
+		reply(http.StatusOK, `{"id":1}`),
+	}}
+	client := newTestClient(t, apiDeps{Transport: api, Clock: newFakeClock()})
+
+	// Сервер мог принять решение до обрыва: повтор отправил бы его дважды
+	if _, _, err := client.post(context.Background(), "/submit", []byte(`{}`)); err == nil {
+		t.Fatal("ожидалась ошибка")
+	}
+	if api.calls() != 1 {
+		t.Errorf("запросов %d, ожидался 1", api.calls())
+	}
+}
+
+func TestRetryPauseInterruptedByCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock := newFakeClock()
+	clock.after = func(time.Duration) <-chan time.Time {
+		cancel()
+		return nil
+	}
+	api := &fakeAPI{responses: []func(*http.Request) (*http.Response, error){
+		reply(http.StatusServiceUnavailable, ""),
+	}}
+	client := newTestClient(t, apiDeps{Transport: api, Clock: clock})
+
+	if _, _, err := client.get(ctx, "/contests"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, ожидался context.Canceled", err)
+	}
+	if api.calls() != 1 {
+		t.Errorf("запросов %d, ожидался 1", api.calls())
+	}
+}
+
+func TestPollVerdictsUntilFinal(t *testing.T) {
+	api := &fakeAPI{responses: []func(*http.Request) (*http.Response, error){
+		reply(http.StatusOK, `{"id":"5","status":"in_queue"}`),
+		reply(http.StatusOK, `{"id":"5","status":"in_queue"}`),
+		reply(http.StatusOK, `{"shown_verdict":1,"shown_verdict_text":"OK","total_points":100,"compiled":true}`),
+	}}
+	clock := newFakeClock()
+	client := newTestClient(t, apiDeps{Transport: api, Clock: clock})
+
+	var events []VerdictEvent
+	client.pollVerdicts(context.Background(), "5", 3*time.Second, func(event VerdictEvent) bool {
+		events = append(events, event)
+		return true
+	})
+
+	if len(events) != 3 {
+		t.Fatalf("событий %d, ожидалось 3", len(events))
+	}
+	last := events[2]
+	if !last.Final || last.Status.Status != "accepted" || last.Status.ID != "5" || last.Status.Score != 100 {
+		t.Errorf("последнее событие %+v, статус %+v", last, last.Status)
+	}
+	if len(clock.sleeps) != 2 || clock.sleeps[0] != 3*time.Second {
+		t.Errorf("паузы %v, ожидалось две по 3s", clock.sleeps)
+	}
+}
+
+func TestQueueTrackerReconnectTimeout(t *testing.T) {
+	clock := newFakeClock()
+	queue := newQueueTracker(clock)
+
+	if _, ok := queue.reconnectDelay(); ok {
+		t.Fatal("без загруженной очереди переподключаться не нужно")
+	}
+	for i := 0; i < queueCongestionUpdates; i++ {
+		queue.observe(&SubmissionStatus{Status: "in_queue"})
+	}
+	if got := queue.timeout(); got != 2*wsMessageTimeout {
+		t.Errorf("таймаут чтения %s, ожидалось %s", got, 2*wsMessageTimeout)
+	}
+
+	var delays []time.Duration
+	for i := 0; i < 6; i++ {
+		delay, ok := queue.reconnectDelay()
+		if !ok {
+			t.Fatalf("переподключение %d отклонено", i)
+		}
+		delays = append(delays, delay)
+	}
+	want := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Fatalf("паузы %v, ожидалось %v", delays, want)
+		}
+	}
+
+	clock.Advance(queueMaxWait + time.Second)
+	if _, ok := queue.reconnectDelay(); ok {
+		t.Error("после queueMaxWait переподключаться не нужно")
+	}
+}
+
+func TestParseWebSocketMessage(t *testing.T) {
+	client := newTestClient(t, apiDeps{Transport: &fakeAPI{}})
+
+	tests := []struct {
+		message string
+		status  string
+		score   int
+		queue   int
+	}{
+		{`{"compiled":false,"total_points":0}`, "compilation_error", 0, 0},
+		{`{"shown_verdict":1,"total_points":100}`, "accepted", 100, 0},
+		{`{"shown_verdict":2,"total_points":40,"compiled":true}`, "partial", 40, 0},
+		{`{"shown_verdict":2,"total_points":0,"compiled":true}`, "wrong_answer", 0, 0},
+		{`{"type":"status","data":{"status":"in_queue","position":4}}`, "in_queue", 0, 4},
+		{`{"status":"testing","score":10}`, "testing", 10, 0},
+	}
+	for _, tt := range tests {
+		status, err := client.parseWebSocketMessage([]byte(tt.message))
+		if err != nil {
+			t.Errorf("%s: %v", tt.message, err)
+			continue
+		}
+		if status.Status != tt.status || status.Score != tt.score || status.QueuePosition != tt.queue {
+			t.Errorf("%s: %+v", tt.message, status)
+		}
+	}
+
+	if _, err := client.parseWebSocketMessage([]byte(`not json`)); err == nil {
+		t.Error("ожидалась ошибка разбора")
+	}
+}
+
+func TestParseSubmitResponse(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		id     string
+	}{
+		{http.StatusOK, `{"id":"123","status":"submitted"}`, "123"},
+		{http.StatusOK, `{"id":456}`, "456"},
+		{http.StatusCreated, `789`, "789"},
+	}
+	for _, tt := range tests {
+		resp, err := parseSubmitResponse(tt.status, []byte(tt.body))
+		if err != nil || resp.ID != tt.id {
+			t.Errorf("%s: %+v, %v", tt.body, resp, err)
+		}
+	}
+
+	_, err := parseSubmitResponse(http.StatusNotFound, []byte(`{"error":"no task"}`))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("404: %v", err)
+	}
+}
+
+// Подставной dialer получает все WebSocket, включая воспроизведение записи
+type fakeDialer struct {
+	urls []string
+}
+
+func (d *fakeDialer) DialContext(ctx context.Context, urlStr string, header http.Header) (*websocket.Conn, *http.Response, error) {
+	d.urls = append(d.urls, urlStr)
+	return nil, nil, errors.New("no network")
+}
+
+func TestDepsReachAllConnections(t *testing.T) {
+	api := &fakeAPI{responses: []func(*http.Request) (*http.Response, error){reply(http.StatusOK, `{"ok":true}`)}}
+	dialer := &fakeDialer{}
+	client := newTestClient(t, apiDeps{Transport: api, Dialer: dialer, Clock: newFakeClock()})
+
+	if _, err := client.dialWebSocket(context.Background(), "/ws/submission?id=1"); err == nil {
+		t.Fatal("ожидалась ошибка подставного dialer")
+	}
+	if len(dialer.urls) != 1 || !strings.HasPrefix(dialer.urls[0], "wss://api.test/ws/submission") {
+		t.Errorf("адреса dialer: %v", dialer.urls)
+	}
+
+	// Telegram, webhooks и картинки условий идут через тот же транспорт
+	resp, err := client.transport.externalClient(time.Second).Get("https://api.telegram.org/bot/getMe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if api.calls() != 1 || api.requests[0].URL.Host != "api.telegram.org" {
+		t.Errorf("запросы: %d", api.calls())
+	}
+}
+
+func TestStaleCacheOnlyWhenServerUnavailable(t *testing.T) {
+	tests := []struct {
+		name     string
+		response func(*http.Request) (*http.Response, error)
+		stale    bool
+	}{
+		{"5xx", reply(http.StatusServiceUnavailable, ""), true},
+		{"таймаут", fail(timeoutError{}), true},
+		{"401", reply(http.StatusUnauthorized, ""), false},
+		{"404", reply(http.StatusNotFound, ""), false},
+		{"неразобранный ответ", reply(http.StatusOK, "<html>"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{responses: []func(*http.Request) (*http.Response, error){tt.response}}
+			client := newTestClient(t, apiDeps{Transport: api, Clock: newFakeClock()})
+			client.cache = &Cache{dir: t.TempDir()}
+			client.cache.Put("contests", []string{"old"})
+
+			value, err := cached(client, "contests", 0, func() ([]string, error) {
+				body, status, err := client.get(context.Background(), "/contests")
+				if err != nil {
+					return nil, err
+				}
+				if status != http.StatusOK {
+					return nil, newAPIError(status, body)
+				}
+				var contests []string
+				return contests, json.Unmarshal(body, &contests)
+			})
+			if stale := err == nil && len(value) == 1 && value[0] == "old"; stale != tt.stale {
+				t.Errorf("cached = %v, %v; из кэша: %v, ожидалось %v", value, err, stale, tt.stale)
+			}
+		})
+	}
+}
+
+func TestSessionExpiredRemembered(t *testing.T) {
+	api := &fakeAPI{responses: []func(*http.Request) (*http.Response, error){
+		reply(http.StatusUnauthorized, ""),
+	}}
+	client := newTestClient(t, apiDeps{Transport: api, Clock: newFakeClock()})
+	if client.SessionExpired() {
+		t.Fatal("новый токен отмечен истекшим")
+	}
+	if _, _, err := client.get(context.Background(), "/getUserInfo"); !errors.Is(err, errSessionExpired) {
+		t.Fatalf("err = %v, ожидался errSessionExpired", err)
+	}
+
+	// Следующий запуск с тем же токеном видит отметку, с новым - нет
+	next := newAPIClientWith(&Config{SessionToken: "token"}, apiDeps{Transport: &fakeAPI{}, Clock: newFakeClock()})
+	if !next.SessionExpired() {
+		t.Error("отметка не сохранилась между запусками")
+	}
+	renewed := newAPIClientWith(&Config{SessionToken: "new-token"}, apiDeps{Transport: &fakeAPI{}, Clock: newFakeClock()})
+	if renewed.SessionExpired() {
+		t.Error("новый токен после auth отмечен истекшим")
+	}
+
+	next.clearSessionExpired()
+	if next.SessionExpired() {
+		t.Error("отметка не удалена")
+	}
+}
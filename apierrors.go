@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Ошибки APIClient, которые можно различать через errors.Is и errors.As,
+// не сравнивая текст сообщений:
+//
+//	errors.Is(err, ErrNotAuthenticated) - нет токена или сервер его отверг (401)
+//	errors.Is(err, ErrNotFound)         - 404 или объект не найден ни одним способом
+//	errors.As(err, &rateLimited)        - 429 после всех повторов, *ErrRateLimited
+//	errors.As(err, &apiErr)             - прочие ответы с ошибкой, *APIError
+var (
+	ErrNotAuthenticated       = errors.New("not authenticated")
+	ErrNotFound         error = localizedError("error.not_found")
+)
+
+// Сервер ограничил частоту запросов, и повторы в doWithRetry не помогли
+type ErrRateLimited struct {
+	RetryAfter time.Duration // 0 - сервер не сообщил, когда повторить
+}
+
+func (e *ErrRateLimited) Error() string {
+	if e.RetryAfter > 0 {
+		return T("api.rate_limited_retry", e.RetryAfter.Round(time.Second))
+	}
+	return T("api.rate_limited")
+}
+
+// Ответ API с кодом ошибки
+type APIError struct {
+	Status int
+	Body   string
+}
+
+func (e *APIError) Error() string {
+	if e.Body == "" || e.Body == "{}" {
+		return T("api.error_status", e.Status)
+	}
+	return T("api.error_status_body", e.Status, e.Body)
+}
+
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.Status == http.StatusNotFound
+}
+
+func newAPIError(status int, body []byte) error {
+	return &APIError{Status: status, Body: strings.TrimSpace(string(body))}
+}
+
+// Вид ошибки для --json: расширению не нужно разбирать текст сообщения
+func apiErrorKind(err error) string {
+	var rateLimited *ErrRateLimited
+	var apiErr *APIError
+	switch {
+	case errors.Is(err, ErrNotAuthenticated):
+		return "not_authenticated"
+	case errors.As(err, &rateLimited):
+		return "rate_limited"
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.As(err, &apiErr):
+		return "api_error"
+	}
+	return ""
+}
+
+// Ошибка, после которой нет смысла пробовать другие endpoints: токен
+// отвергнут, сервер ограничил запросы или команда отменена
+func isFatalAPIError(err error) bool {
+	var rateLimited *ErrRateLimited
+	return errors.Is(err, ErrNotAuthenticated) || errors.As(err, &rateLimited) || errors.Is(err, context.Canceled)
+}
+
+// Токен отвергнут сервером: текст для пользователя, но errors.Is(err, ErrNotAuthenticated)
+type sessionError string
+
+func (e sessionError) Error() string { return T(string(e)) }
+
+func (e sessionError) Is(target error) bool { return target == ErrNotAuthenticated }
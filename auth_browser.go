@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// Вход через браузер: sortme поднимает HTTP сервер на 127.0.0.1 и открывает
+// страницу входа sort-me.org с адресом возврата. После входа сайт перенаправляет
+// браузер на
+//
+//	http://127.0.0.1:<port>/callback?state=...&token=...&user_id=...&username=...
+//
+// state защищает от подстановки чужого токена другой страницей
+const browserLoginURL = "https://sort-me.org/cli-auth"
+
+const browserCallbackPath = "/callback"
+
+const browserDonePage = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>sortme</title></head>
+<body style="font-family: sans-serif; text-align: center; margin-top: 15vh">
+<h2>%s</h2><p>%s</p>
+</body></html>`
+
+type browserCallback struct {
+	creds ProfileCredentials
+	err   error
+}
+
+func (v *VSCodeExtension) browserLogin(ctx context.Context, timeout time.Duration) (ProfileCredentials, error) {
+	stateBytes := make([]byte, 16)
+	if _, err := rand.Read(stateBytes); err != nil {
+		return ProfileCredentials{}, err
+	}
+	state := hex.EncodeToString(stateBytes)
+
+	// Только loopback: токен не должен быть виден из сети
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return ProfileCredentials{}, fmt.Errorf("%s: %w", T("browser.server_failed"), err)
+	}
+	redirect := fmt.Sprintf("http://%s%s", listener.Addr(), browserCallbackPath)
+
+	results := make(chan browserCallback, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(browserCallbackPath, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// Запросы без верного state (например, от другой вкладки) просто отклоняем
+		if subtle.ConstantTimeCompare([]byte(r.Form.Get("state")), []byte(state)) != 1 {
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return
+		}
+
+		result := browserCallback{creds: ProfileCredentials{
+			SessionToken: r.Form.Get("token"),
+			UserID:       r.Form.Get("user_id"),
+			Username:     r.Form.Get("username"),
+		}}
+		switch {
+		case r.Form.Get("error") != "":
+			result.err = errors.New(T("browser.rejected", r.Form.Get("error")))
+		case result.creds.SessionToken == "":
+			result.err = errors.New(T("browser.no_token"))
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if result.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, browserDonePage, T("browser.failed_title"), result.err)
+		} else {
+			fmt.Fprintf(w, browserDonePage, T("browser.done_title"), T("browser.done_text"))
+		}
+
+		select {
+		case results <- result:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+	defer func() {
+		// Даем браузеру дочитать страницу с результатом
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	loginURL := browserLoginURL + "?" + url.Values{
+		"redirect_uri": {redirect},
+		"state":        {state},
+	}.Encode()
+
+	progressln(T("browser.opening"))
+	if err := openBrowser(loginURL); err != nil {
+		progress(T("browser.open_failed", err))
+	}
+	progress(T("browser.link_hint", loginURL))
+	progress(T("browser.waiting", timeout))
+
+	select {
+	case result := <-results:
+		if result.err != nil {
+			return ProfileCredentials{}, result.err
+		}
+		creds := result.creds
+		// Ручной вход использует username и как user_id
+		if creds.Username == "" {
+			creds.Username = creds.UserID
+		}
+		if creds.UserID == "" {
+			creds.UserID = creds.Username
+		}
+		if creds.UserID == "" {
+			return ProfileCredentials{}, errors.New(T("browser.no_username"))
+		}
+		return creds, nil
+	case <-time.After(timeout):
+		return ProfileCredentials{}, errors.New(T("browser.timeout", timeout))
+	case <-ctx.Done():
+		return ProfileCredentials{}, errors.New(T("browser.cancelled"))
+	}
+}
+
+// Открывает адрес в браузере по умолчанию
+func openBrowser(target string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", target)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+	return cmd.Start()
+}
@@ -0,0 +1,454 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Сборка решения в один файл перед отправкой: сервер принимает один файл,
+// а шаблоны часто лежат отдельно. Для C/C++ подставляются локальные
+// #include "...", для Go - пакеты своего модуля из go.mod
+
+var reLocalInclude = regexp.MustCompile(`^\s*#\s*include\s*"([^"]+)"`)
+var rePragmaOnce = regexp.MustCompile(`^\s*#\s*pragma\s+once\b`)
+
+// Результат сборки: код и файлы, которые в него вошли (кроме основного)
+type Bundle struct {
+	Code  string
+	Files []string
+}
+
+// Собирает решение, если язык это поддерживает. Для остальных языков
+// возвращает код файла как есть
+func bundleSource(filename, language string, languages map[string]LanguageConfig) (*Bundle, error) {
+	switch language {
+	case "c", "c++":
+		return bundleCInclude(filename, languages[language].Include)
+	case "go":
+		return bundleGoPackages(filename)
+	}
+	code, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &Bundle{Code: string(code)}, nil
+}
+
+// Подставляет #include "..." рекурсивно, каждый файл один раз (как с
+// #pragma once или include guard). Заголовок ищется рядом с подключающим
+// файлом, затем в каталогах include из конфига. Не найденные локально
+// #include остаются как есть
+func bundleCInclude(filename string, includeDirs []string) (*Bundle, error) {
+	baseDir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return nil, err
+	}
+	bundle := &Bundle{}
+	seen := make(map[string]bool)
+	var out strings.Builder
+
+	var inline func(path string, top bool) error
+	inline = func(path string, top bool) error {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		seen[abs] = true
+		data, err := os.ReadFile(abs)
+		if err != nil {
+			return err
+		}
+		if !top {
+			name := path
+			if rel, err := filepath.Rel(baseDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+				name = rel
+			}
+			bundle.Files = append(bundle.Files, name)
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !top && rePragmaOnce.MatchString(line) {
+				continue
+			}
+			match := reLocalInclude.FindStringSubmatch(line)
+			if match == nil {
+				out.WriteString(line + "\n")
+				continue
+			}
+			header := findIncludedHeader(match[1], filepath.Dir(abs), includeDirs)
+			if header == "" {
+				out.WriteString(line + "\n")
+				continue
+			}
+			if headerAbs, _ := filepath.Abs(header); seen[headerAbs] {
+				continue
+			}
+			fmt.Fprintf(&out, "// ---- %s ----\n", match[1])
+			if err := inline(header, false); err != nil {
+				return fmt.Errorf("%s: %w", match[1], err)
+			}
+			fmt.Fprint(&out, T("bundle.end_marker", match[1]))
+		}
+		return scanner.Err()
+	}
+
+	if err := inline(filename, true); err != nil {
+		return nil, err
+	}
+	bundle.Code = out.String()
+	return bundle, nil
+}
+
+func findIncludedHeader(name, dir string, includeDirs []string) string {
+	for _, base := range append([]string{dir}, includeDirs...) {
+		if strings.HasPrefix(base, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				base = filepath.Join(home, base[2:])
+			}
+		}
+		path := filepath.Join(base, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// Файл Go при сборке
+type goBundleFile struct {
+	path string
+	src  []byte
+	file *ast.File
+	fset *token.FileSet
+}
+
+// Объединяет main с пакетами своего модуля: код пакетов дописывается в конец,
+// обращения pkg.Name становятся Name, импорты собираются в один блок.
+// Одинаковые имена верхнего уровня в разных пакетах - ошибка: переименовывать
+// их автоматически небезопасно
+func bundleGoPackages(filename string) (*Bundle, error) {
+	modRoot, modPath, err := findGoModule(filepath.Dir(filename))
+	if errors.Is(err, os.ErrNotExist) {
+		// Без модуля подключать нечего
+		code, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		return &Bundle{Code: string(code)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	mainPath, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &Bundle{}
+	var files []*goBundleFile
+	visited := make(map[string]bool)
+	imports := make(map[string]string)  // путь -> псевдоним ("" - без псевдонима)
+	pkgNames := make(map[string]string) // путь пакета модуля -> имя из package
+
+	parseFile := func(path string) (*goBundleFile, error) {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		return &goBundleFile{path: path, src: src, file: file, fset: fset}, nil
+	}
+
+	var main *goBundleFile
+	var addPackage func(importPath, dir string) error
+	var collectImports func(f *goBundleFile) error
+	collectImports = func(f *goBundleFile) error {
+		for _, spec := range f.file.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			if path == modPath || strings.HasPrefix(path, modPath+"/") {
+				if err := addPackage(path, filepath.Join(modRoot, strings.TrimPrefix(path, modPath))); err != nil {
+					return err
+				}
+				continue
+			}
+			alias := ""
+			if spec.Name != nil {
+				alias = spec.Name.Name
+			}
+			imports[path] = alias
+		}
+		return nil
+	}
+	addPackage = func(importPath, dir string) error {
+		if visited[dir] {
+			return nil
+		}
+		visited[dir] = true
+		paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			return err
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			if strings.HasSuffix(path, "_test.go") || path == mainPath {
+				continue
+			}
+			f, err := parseFile(path)
+			if err != nil {
+				return err
+			}
+			if !goFileBuilds(f.file) || (importPath == "" && f.file.Name.Name != main.file.Name.Name) {
+				continue
+			}
+			pkgNames[importPath] = f.file.Name.Name
+			files = append(files, f)
+			if rel, err := filepath.Rel(modRoot, path); err == nil {
+				bundle.Files = append(bundle.Files, rel)
+			}
+			if err := collectImports(f); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	main, err = parseFile(mainPath)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, main)
+	if err := collectImports(main); err != nil {
+		return nil, err
+	}
+	// Остальные файлы пакета main из того же каталога
+	if err := addPackage("", filepath.Dir(mainPath)); err != nil {
+		return nil, err
+	}
+	if len(files) == 1 {
+		return &Bundle{Code: string(main.src)}, nil
+	}
+
+	if err := checkGoNameConflicts(files); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.WriteString("package main\n\n")
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for path := range imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		out.WriteString("import (\n")
+		for _, path := range paths {
+			if alias := imports[path]; alias != "" {
+				fmt.Fprintf(&out, "\t%s %q\n", alias, path)
+			} else {
+				fmt.Fprintf(&out, "\t%q\n", path)
+			}
+		}
+		out.WriteString(")\n")
+	}
+	// Сначала main, затем пакеты в порядке обхода
+	for _, f := range files {
+		if f != main {
+			rel, _ := filepath.Rel(modRoot, f.path)
+			fmt.Fprintf(&out, "\n// ---- %s ----\n", filepath.ToSlash(rel))
+		}
+		out.Write(stripGoPackageRefs(f, pkgNames))
+	}
+
+	code, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", T("bundle.parse_failed"), err)
+	}
+	bundle.Code = string(code)
+	return bundle, nil
+}
+
+// Корень модуля и его путь из ближайшего go.mod
+func findGoModule(dir string) (string, string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	for {
+		data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			for _, line := range strings.Split(string(data), "\n") {
+				if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "module" {
+					return dir, strings.Trim(fields[1], `"`), nil
+				}
+			}
+			return "", "", errors.New(T("bundle.no_module", filepath.Join(dir, "go.mod")))
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", fmt.Errorf("go.mod: %w", os.ErrNotExist)
+		}
+		dir = parent
+	}
+}
+
+// Учитывает //go:build для linux/amd64 - на нем обычно работают тестирующие системы
+func goFileBuilds(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			expr, err := constraint.Parse(comment.Text)
+			if err != nil {
+				continue
+			}
+			return expr.Eval(func(tag string) bool {
+				return tag == "linux" || tag == "amd64" || tag == "unix" || strings.HasPrefix(tag, "go1.")
+			})
+		}
+	}
+	return true
+}
+
+// Текст файла без package и import, с pkg.Name -> Name для пакетов модуля
+func stripGoPackageRefs(f *goBundleFile, pkgNames map[string]string) []byte {
+	local := make(map[string]bool)
+	for _, spec := range f.file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name, ok := pkgNames[path]
+		if !ok {
+			continue
+		}
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		local[name] = true
+	}
+
+	// Удаляемые участки: от package до конца последнего import и префиксы pkg.
+	type cut struct{ from, to int }
+	offset := func(pos token.Pos) int { return f.fset.Position(pos).Offset }
+	start := offset(f.file.Name.End())
+	for _, decl := range f.file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			start = offset(gen.End())
+		}
+	}
+	var cuts []cut
+	ast.Inspect(f.file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok && ident.Obj == nil && local[ident.Name] {
+			cuts = append(cuts, cut{offset(ident.Pos()), offset(sel.Sel.Pos())})
+		}
+		return true
+	})
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i].from < cuts[j].from })
+
+	var out bytes.Buffer
+	pos := start
+	for _, c := range cuts {
+		if c.from < pos {
+			continue
+		}
+		out.Write(f.src[pos:c.from])
+		pos = c.to
+	}
+	out.Write(f.src[pos:])
+	return out.Bytes()
+}
+
+// Одинаковые имена верхнего уровня из разных файлов не собрать в один пакет
+func checkGoNameConflicts(files []*goBundleFile) error {
+	owners := make(map[string]string)
+	for _, f := range files {
+		for _, decl := range f.file.Decls {
+			var names []string
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil && d.Name.Name != "init" && d.Name.Name != "_" {
+					names = append(names, d.Name.Name)
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						names = append(names, s.Name.Name)
+					case *ast.ValueSpec:
+						for _, name := range s.Names {
+							if name.Name != "_" {
+								names = append(names, name.Name)
+							}
+						}
+					}
+				}
+			}
+			for _, name := range names {
+				if owner, ok := owners[name]; ok && filepath.Dir(owner) != filepath.Dir(f.path) {
+					return errors.New(T("bundle.duplicate", name, owner, f.path))
+				}
+				owners[name] = f.path
+			}
+		}
+	}
+	return nil
+}
+
+func (v *VSCodeExtension) createBundleCommand() *cobra.Command {
+	var language, output string
+
+	cmd := &cobra.Command{
+		Use:   "bundle <file>",
+		Short: T("bundle.short"),
+		Long:  T("bundle.long"),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			filename := args[0]
+			if language == "" {
+				language = v.apiClient.DetectLanguage(filename)
+			}
+			bundle, err := bundleSource(filename, language, v.config.Languages)
+			if err != nil {
+				return err
+			}
+			if output == "" || output == "-" {
+				fmt.Print(bundle.Code)
+				return nil
+			}
+			if err := os.WriteFile(output, []byte(bundle.Code), 0644); err != nil {
+				return err
+			}
+			fmt.Print(T("bundle.done", filename, len(bundle.Files), output))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&language, "language", "l", "", T("flag.language"))
+	cmd.Flags().StringVarP(&output, "output", "o", "", T("flag.bundle_output"))
+	return cmd
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Время жизни закэшированных ответов API
+const (
+	ttlUpcomingContests = 5 * time.Minute
+	ttlArchiveContests  = 6 * time.Hour
+	ttlContestInfo      = time.Hour
+	ttlTaskStatement    = 24 * time.Hour
+	ttlSubmissionSource = 30 * 24 * time.Hour
+	ttlJudgeLanguages   = 24 * time.Hour
+	ttlUserProfile      = 10 * time.Minute
+
+	// В режиме экономии трафика данные живут дольше
+	lowBandwidthTTLScale = 4
+)
+
+// Дисковый кэш ответов API в ~/.cache/sortme_plugin.
+// Ключи иерархические: contests/upcoming, contest/456/info, statements/456/2472
+type Cache struct {
+	dir string
+	// --no-cache: не читаем кэш, но свежие ответы сохраняем
+	disabled bool
+}
+
+type cacheEntry struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Data     json.RawMessage `json:"data"`
+	// Сброшено командой cache invalidate: при запросе загружается заново,
+	// но без сети все еще показывается
+	Expired bool `json:"expired,omitempty"`
+}
+
+func getCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(dir, "sortme_plugin")
+}
+
+func NewCache() *Cache {
+	return &Cache{dir: profileDataDir(getCacheDir())}
+}
+
+func (c *Cache) path(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		// Ключ не должен выходить за пределы каталога кэша
+		if part == "" || part == "." || part == ".." {
+			parts[i] = "_"
+		}
+	}
+	return filepath.Join(c.dir, filepath.Join(parts...)+".json")
+}
+
+func (c *Cache) read(key string) (*cacheEntry, error) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, err
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Читает значение, если оно моложе ttl
+func (c *Cache) Get(key string, ttl time.Duration, v interface{}) bool {
+	if c == nil || c.disabled {
+		return false
+	}
+	entry, err := c.read(key)
+	if err != nil || entry.Expired || time.Since(entry.StoredAt) > ttl {
+		return false
+	}
+	return json.Unmarshal(entry.Data, v) == nil
+}
+
+// Читает значение любой давности и возвращает время его сохранения.
+// Нужно, когда сервер недоступен и устаревшие данные лучше, чем никаких
+func (c *Cache) GetStale(key string, v interface{}) (time.Time, bool) {
+	if c == nil || c.disabled {
+		return time.Time{}, false
+	}
+	entry, err := c.read(key)
+	if err != nil || json.Unmarshal(entry.Data, v) != nil {
+		return time.Time{}, false
+	}
+	return entry.StoredAt, true
+}
+
+func (c *Cache) Put(key string, v interface{}) {
+	if c == nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	entry, err := json.Marshal(cacheEntry{StoredAt: time.Now(), Data: data})
+	if err != nil {
+		return
+	}
+
+	filename := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return
+	}
+	// Кэш - не критичные данные, ошибки записи просто игнорируем
+	writeFileAtomic(filename, entry)
+}
+
+// Удаляет значение, например после действия, которое его меняет
+func (c *Cache) Delete(key string) {
+	if c == nil {
+		return
+	}
+	os.Remove(c.path(key))
+}
+
+// Помечает устаревшими все значения с ключами внутри prefix (contests,
+// statements/456) или сам ключ prefix. Возвращает число сброшенных значений
+func (c *Cache) Expire(prefix string) (int, error) {
+	var files []string
+	if _, err := os.Stat(c.path(prefix)); err == nil {
+		files = append(files, c.path(prefix))
+	}
+	root := strings.TrimSuffix(c.path(prefix), ".json")
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".json") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	count := 0
+	for _, filename := range files {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			continue
+		}
+		var entry cacheEntry
+		if json.Unmarshal(data, &entry) != nil {
+			// Испорченное значение толку не принесет
+			os.Remove(filename)
+			count++
+			continue
+		}
+		if entry.Expired {
+			continue
+		}
+		entry.Expired = true
+		if data, err = json.Marshal(entry); err != nil {
+			return count, err
+		}
+		if err := writeFileAtomic(filename, data); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// TTL с учетом режима экономии трафика
+func (a *APIClient) cacheTTL(ttl time.Duration) time.Duration {
+	if a.config.LowBandwidth {
+		return ttl * lowBandwidthTTLScale
+	}
+	return ttl
+}
+
+// Читает значение из кэша, а при промахе загружает его и сохраняет.
+// Если сервер недоступен, отдает последнее сохраненное значение с предупреждением
+func cached[T any](a *APIClient, key string, ttl time.Duration, load func() (T, error)) (T, error) {
+	var value T
+	if a.cache.Get(key, a.cacheTTL(ttl), &value) {
+		return value, nil
+	}
+
+	value, err := load()
+	if err != nil {
+		var stale T
+		if !serverUnavailable(err) {
+			return value, err
+		}
+		if storedAt, ok := a.cache.GetStale(key, &stale); ok {
+			a.offline.note(storedAt, err)
+			return stale, nil
+		}
+		return value, err
+	}
+	a.cache.Put(key, value)
+	return value, nil
+}
+
+// Сеть, таймаут или 5xx: сервер не ответил по существу, и старые данные лучше,
+// чем ничего. 401, 404, 429 и неразобранный ответ - настоящий ответ сервера,
+// его нельзя прятать за кэшем. Ctrl+C сюда не попадает: команда и так завершается
+func serverUnavailable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Status >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// Режим только для чтения при недоступном сервере: какие данные показаны
+// из кэша и от какого они времени. Баннер печатается один раз за запуск
+type offlineState struct {
+	mu     sync.Mutex
+	since  time.Time // самые старые из показанных данных
+	active bool
+}
+
+func (o *offlineState) note(storedAt time.Time, cause error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.active && !storedAt.Before(o.since) {
+		return
+	}
+	first := !o.active
+	o.active = true
+	o.since = storedAt
+	if first {
+		progress(T("cache.stale", cause, formatStaleTime(storedAt)))
+	} else {
+		progress(T("cache.stale_older", formatStaleTime(storedAt)))
+	}
+}
+
+// Показаны ли в этом запуске данные из кэша вместо ответа сервера
+func (a *APIClient) Offline() bool {
+	a.offline.mu.Lock()
+	defer a.offline.mu.Unlock()
+	return a.offline.active
+}
+
+// 14:02 для сегодняшних данных, 12.03 14:02 для более старых
+func formatStaleTime(t time.Time) string {
+	now := time.Now()
+	if t.Year() == now.Year() && t.YearDay() == now.YearDay() {
+		return t.Format("15:04")
+	}
+	return t.Format("02.01 15:04")
+}
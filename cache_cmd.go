@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// Что можно сбросить командой cache invalidate и сколько аргументов нужно
+var cacheInvalidateKinds = map[string]cobra.PositionalArgs{
+	"contests":    cobra.NoArgs,
+	"contest":     cobra.ExactArgs(1),
+	"submissions": cobra.ExactArgs(1),
+	"statements":  cobra.MaximumNArgs(1),
+}
+
+func (v *VSCodeExtension) createCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: T("cache.short"),
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:       "invalidate contests|contest <id>|submissions <contest_id>|statements [contest_id]",
+		Short:     T("cache.invalidate.short"),
+		Long:      T("cache.invalidate.long"),
+		Args:      cobra.MinimumNArgs(1),
+		ValidArgs: []string{"contests", "contest", "submissions", "statements"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			validate, ok := cacheInvalidateKinds[args[0]]
+			if !ok {
+				return errors.New(T("cache.unknown_kind", args[0]))
+			}
+			if err := validate(cmd, args[1:]); err != nil {
+				return err
+			}
+			for _, id := range args[1:] {
+				if _, err := strconv.Atoi(id); err != nil {
+					return errors.New(T("cache.bad_contest_id", id))
+				}
+			}
+			cmd.SilenceUsage = true
+			return v.handleCacheInvalidate(args[0], args[1:])
+		},
+	})
+	return cmd
+}
+
+// Сбрасывает одну группу данных. Значения не удаляются, а помечаются
+// устаревшими: следующий запрос загрузит их заново, а без сети покажет старые
+func (v *VSCodeExtension) handleCacheInvalidate(kind string, args []string) error {
+	cache := v.apiClient.cache
+	var count int
+	var err error
+
+	switch kind {
+	case "contests":
+		count, err = cache.Expire("contests")
+	case "contest":
+		count, err = cache.Expire("contest/" + args[0])
+	case "statements":
+		prefix := "statements"
+		if len(args) > 0 {
+			prefix += "/" + args[0]
+		}
+		count, err = cache.Expire(prefix)
+	case "submissions":
+		return v.invalidateSubmissions(args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", T("cache.invalidate_failed"), err)
+	}
+
+	if count == 0 {
+		fmt.Println(T("cache.nothing"))
+		return nil
+	}
+	fmt.Print(T("cache.invalidated", count))
+	return nil
+}
+
+// Отправки хранятся в локальной базе: сбрасываем отметку о полной синхронизации
+func (v *VSCodeExtension) invalidateSubmissions(contestID string) error {
+	db, err := OpenSubmissionDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	found, err := db.InvalidateContest(contestID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", T("cache.submissions_failed"), err)
+	}
+	if !found {
+		fmt.Print(T("cache.not_synced", contestID))
+		return nil
+	}
+	fmt.Print(T("cache.submissions_reset", contestID, contestID))
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Режим для CI: токен из окружения, никаких вопросов, ожидание вердикта
+// с жестким ограничением и один JSON в stdout. Код завершения тот же,
+// что у submit --watch, так что сборка падает на любом вердикте кроме AC
+
+const (
+	ciTokenEnv  = "SORTME_TOKEN"
+	ciUserIDEnv = "SORTME_USER_ID"
+
+	ciDefaultTimeout = 10 * time.Minute
+)
+
+func (v *VSCodeExtension) createCICommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ci",
+		Short: T("ci.short"),
+	}
+
+	var opts SubmitOptions
+	var timeout time.Duration
+	submit := &cobra.Command{
+		Use:   "submit <file>",
+		Short: T("ci.submit.short"),
+		Long:  T("ci.submit.long"),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			v.enableJSONOutput()
+			return v.handleCISubmit(cmd.Context(), args[0], opts, timeout)
+		},
+	}
+	submit.Flags().StringVarP(&opts.ContestID, "contest", "c", "", T("flag.contest_default"))
+	submit.Flags().StringVarP(&opts.ProblemID, "problem", "p", "", T("flag.problem_required"))
+	submit.Flags().StringVarP(&opts.Language, "language", "l", "", T("flag.language"))
+	submit.Flags().DurationVar(&timeout, "timeout", ciDefaultTimeout, T("flag.ci_timeout"))
+
+	cmd.AddCommand(submit)
+	return cmd
+}
+
+// Учетные данные из окружения важнее конфига: в CI его обычно нет,
+// а токен хранится в секретах репозитория
+func (v *VSCodeExtension) useCIToken() error {
+	token := os.Getenv(ciTokenEnv)
+	if token == "" {
+		if v.apiClient.IsAuthenticated() {
+			return nil
+		}
+		return errors.New(T("ci.token_missing", ciTokenEnv))
+	}
+	v.config.SessionToken = token
+	// Для отправки ID пользователя не нужен, но без него клиент считает,
+	// что входа не было
+	v.config.UserID = cmp.Or(os.Getenv(ciUserIDEnv), v.config.UserID, "ci")
+	return nil
+}
+
+func (v *VSCodeExtension) handleCISubmit(ctx context.Context, filename string, opts SubmitOptions, timeout time.Duration) error {
+	if timeout <= 0 {
+		return errors.New(T("ci.bad_timeout"))
+	}
+	if err := v.useCIToken(); err != nil {
+		return err
+	}
+	v.applyWorkspaceBinding(filename, &opts)
+	opts.ContestID = cmp.Or(opts.ContestID, v.config.CurrentContest)
+	if opts.ContestID == "" {
+		return fmt.Errorf("%s", T("contest.missing"))
+	}
+	if opts.ProblemID == "" {
+		return fmt.Errorf("%s", T("problem.missing"))
+	}
+	// Спросить некого: отправляем всегда, даже уже решенную задачу
+	opts.AssumeYes, opts.Force, opts.Watch = true, true, true
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := v.handleSubmit(ctx, filename, opts)
+	if result == nil {
+		// Причина уже выведена и записана в JSON
+		return &exitCodeError{code: exitError, reason: T("ci.not_submitted")}
+	}
+	submissionID := result["submission_id"].(string)
+	err := v.watchVerdict(ctx, result, submissionID, opts.ContestID, opts.ProblemID)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && !v.output.emitted {
+		v.emitJSON(map[string]interface{}{
+			"submission_id": submissionID,
+			"contest_id":    opts.ContestID,
+			"problem_id":    opts.ProblemID,
+			"error":         T("ci.no_verdict_within", timeout),
+			"kind":          "timeout",
+		})
+		return &exitCodeError{code: exitNoVerdict, reason: T("submit.no_verdict")}
+	}
+	return err
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// Часы клиента API. Паузы между повторами, ограничение частоты запросов,
+// ожидание загруженной очереди и опрос статуса идут через них: с подставными
+// часами проверка таймаутов не ждет минуты по-настоящему
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Пауза по часам c, которую прерывает отмена ctx
+func sleepClock(ctx context.Context, c clock, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	select {
+	case <-c.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
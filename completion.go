@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Дополнение ID контестов и задач в shell (sortme completion bash|zsh|fish).
+// Берется только из кэша: сеть в момент нажатия Tab слишком медленная,
+// а stdout занят протоколом дополнения, так что ничего не печатаем
+
+// Контесты из кэша: предстоящие и архивные, без повторов
+func (v *VSCodeExtension) cachedContests() []Contest {
+	var contests []Contest
+	var upcoming []UpcomingContest
+	if _, ok := v.apiClient.cache.GetStale("contests/upcoming", &upcoming); ok {
+		for _, uc := range upcoming {
+			contests = append(contests, Contest{ID: strconv.Itoa(uc.ID), Name: uc.Name})
+		}
+	}
+	var archive []Contest
+	if _, ok := v.apiClient.cache.GetStale("contests/archive", &archive); ok {
+		contests = append(contests, archive...)
+	}
+
+	seen := make(map[string]bool)
+	unique := contests[:0]
+	for _, contest := range contests {
+		if !seen[contest.ID] {
+			seen[contest.ID] = true
+			unique = append(unique, contest)
+		}
+	}
+	return unique
+}
+
+// Задачи контеста из кэша, иначе из последнего открытого контеста
+func (v *VSCodeExtension) cachedTasks(contestID string) []Task {
+	var info ContestInfo
+	if _, ok := v.apiClient.cache.GetStale("contest/"+contestID+"/info", &info); ok && len(info.Tasks) > 0 {
+		return info.Tasks
+	}
+	var recent RecentContest
+	if err := loadState(recentContestStateFile, &recent); err == nil && recent.ContestID == contestID {
+		return recent.Tasks
+	}
+	return nil
+}
+
+func (v *VSCodeExtension) contestCompletions(toComplete string) []string {
+	var completions []string
+	for _, contest := range v.cachedContests() {
+		if strings.HasPrefix(contest.ID, toComplete) {
+			completions = append(completions, contest.ID+"\t"+contest.Name)
+		}
+	}
+	return completions
+}
+
+func (v *VSCodeExtension) problemCompletions(contestID, toComplete string) []string {
+	var completions []string
+	for i, task := range v.cachedTasks(contestID) {
+		id := strconv.Itoa(task.ID)
+		if strings.HasPrefix(id, toComplete) {
+			completions = append(completions, fmt.Sprintf("%s\t%s. %s", id, taskLetter(i), task.Name))
+		}
+	}
+	return completions
+}
+
+// Контест для дополнения задач: --contest этой команды, затем .sortme.yaml
+// рядом с файлом из аргументов (sortme submit a.cpp -p <Tab>), иначе текущий
+func (v *VSCodeExtension) completionContest(cmd *cobra.Command, args []string) string {
+	if flag := cmd.Flags().Lookup("contest"); flag != nil && flag.Changed {
+		return flag.Value.String()
+	}
+	if len(args) > 0 {
+		if info, err := os.Stat(args[0]); err == nil && !info.IsDir() {
+			if binding, _, err := findWorkspace(filepath.Dir(args[0])); err == nil && binding != nil && binding.ContestID != "" {
+				return binding.ContestID
+			}
+		}
+	}
+	return v.config.CurrentContest
+}
+
+// Подключает дополнение ко всем флагам --contest/--problem и к позиционным
+// contest_id/problem_id из Use команд
+func (v *VSCodeExtension) applyCompletions(root *cobra.Command) {
+	contests := func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return v.contestCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+	problems := func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return v.problemCompletions(v.completionContest(cmd, args), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		if cmd.Flags().Lookup("contest") != nil {
+			cmd.RegisterFlagCompletionFunc("contest", contests)
+		}
+		if cmd.Flags().Lookup("problem") != nil {
+			cmd.RegisterFlagCompletionFunc("problem", problems)
+		}
+		if cmd.ValidArgsFunction == nil {
+			cmd.ValidArgsFunction = v.positionalCompletion(cmd.Use)
+		}
+		for _, child := range cmd.Commands() {
+			walk(child)
+		}
+	}
+	walk(root)
+}
+
+// Дополнение аргументов по Use: "problems [contest_id]",
+// "read [contest_id] <problem_id>". nil - у команды таких аргументов нет
+func (v *VSCodeExtension) positionalCompletion(use string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	fields := strings.Fields(use)
+	if len(fields) < 2 {
+		return nil
+	}
+	params := make([]string, 0, len(fields)-1)
+	for _, field := range fields[1:] {
+		params = append(params, strings.Trim(field, "[]<>."))
+	}
+	if params[0] != "contest_id" {
+		return nil
+	}
+	// Контест необязателен, если за ним идет задача: "read 2472" - задача текущего контеста
+	optionalContest := strings.HasPrefix(fields[1], "[") && len(params) > 1 && params[1] == "problem_id"
+
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		switch {
+		case len(args) == 0 && optionalContest:
+			return append(v.problemCompletions(v.config.CurrentContest, toComplete), v.contestCompletions(toComplete)...), cobra.ShellCompDirectiveNoFileComp
+		case len(args) == 0:
+			return v.contestCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+		case len(args) == 1 && len(params) > 1 && params[1] == "problem_id":
+			return v.problemCompletions(args[0], toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+)
+
+// Условные GET-запросы: для ответов с ETag или Last-Modified тело хранится
+// в кэше вместе с ними, а следующий запрос уходит с If-None-Match /
+// If-Modified-Since. Если на сервере ничего не изменилось, он отвечает
+// 304 без тела, и sync или contests почти не тратят трафик
+
+type validatedResponse struct {
+	Endpoint     string `json:"endpoint"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	// Ответы зависят от пользователя: после входа в другой аккаунт не используем
+	Token string `json:"token"`
+	Body  []byte `json:"body"`
+}
+
+// Ключ в кэше: в endpoint есть ? и &, недопустимые в именах файлов Windows
+func validatedKey(endpoint string) string {
+	sum := sha256.Sum256([]byte(endpoint))
+	return "http/" + hex.EncodeToString(sum[:12])
+}
+
+// Сохраненный ответ для endpoint и заголовки условного запроса к нему
+func (a *APIClient) setValidators(req *http.Request, endpoint string) *validatedResponse {
+	var stored validatedResponse
+	if _, ok := a.cache.GetStale(validatedKey(endpoint), &stored); !ok ||
+		stored.Endpoint != endpoint || stored.Token != tokenHash(a.config.SessionToken) {
+		return nil
+	}
+	if stored.ETag != "" {
+		req.Header.Set("If-None-Match", stored.ETag)
+	}
+	if stored.LastModified != "" {
+		req.Header.Set("If-Modified-Since", stored.LastModified)
+	}
+	return &stored
+}
+
+// Запоминает тело успешного ответа, если сервер дал валидаторы
+func (a *APIClient) storeValidated(endpoint string, resp *http.Response, body []byte) {
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return
+	}
+	a.cache.Put(validatedKey(endpoint), validatedResponse{
+		Endpoint:     endpoint,
+		ETag:         etag,
+		LastModified: lastModified,
+		Token:        tokenHash(a.config.SessionToken),
+		Body:         body,
+	})
+}
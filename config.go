@@ -1,20 +1,112 @@
 package main
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"time"
 
 	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
 )
 
+// Ключ, в котором старые версии хранили токен Telegram бота
+const legacyTelegramTokenKey = "telegram_token"
+
 type Config struct {
-	TelegramToken  string `mapstructure:"telegram_token"`
-	SessionToken   string `mapstructure:"session_token"`
-	UserID         string `mapstructure:"user_id"`
-	APIBaseURL     string `mapstructure:"api_base_url"`
-	Username       string `mapstructure:"username"`
-	CurrentContest string `mapstructure:"current_contest"` // Новое поле
+	SessionToken      string        `mapstructure:"session_token"`
+	UserID            string        `mapstructure:"user_id"`
+	APIBaseURL        string        `mapstructure:"api_base_url"` // Адрес API, например https://api.sort-me.org
+	APIIP             string        `mapstructure:"api_ip"`       // IP сервера API вместо DNS (пусто - обычное разрешение имени)
+	InsecureTLS       bool          `mapstructure:"insecure_tls"` // Разрешить запросы без проверки сертификата, если проверка не прошла
+	Proxy             string        `mapstructure:"proxy"`        // HTTP или SOCKS5 прокси (пусто - из HTTPS_PROXY/ALL_PROXY, direct - без прокси)
+	Username          string        `mapstructure:"username"`
+	CurrentContest    string        `mapstructure:"current_contest"`      // Новое поле
+	Deadlines         []Deadline    `mapstructure:"deadlines"`            // Личные дедлайны для agenda
+	LowBandwidth      bool          `mapstructure:"low_bandwidth"`        // Режим экономии трафика
+	WebhookURL        string        `mapstructure:"webhook_url"`          // Куда отправлять финальные вердикты (без подписи, см. webhooks)
+	NotifyChatID      string        `mapstructure:"notify_chat_id"`       // Telegram чат для уведомлений (токен бота - в secrets.json)
+	DiscordClientID   string        `mapstructure:"discord_client_id"`    // Приложение Discord для Rich Presence
+	Language          string        `mapstructure:"language"`             // Язык вывода: ru или en
+	LimitWarning      float64       `mapstructure:"limit_warning_margin"` // Доля лимита времени/памяти, после которой AC считается рискованным
+	MaxRetries        int           `mapstructure:"max_retries"`          // Повторы запроса при 429, 5xx и таймаутах
+	BackoffBase       time.Duration `mapstructure:"backoff_base"`         // Начальная задержка между повторами
+	Workers           int           `mapstructure:"workers"`              // Сколько запросов отправок выполнять параллельно
+	RequestsPerSecond float64       `mapstructure:"requests_per_second"`  // Общий лимит запросов к API, 0 - без ограничения
+
+	Profiles       map[string]ProfileCredentials `mapstructure:"profiles"`        // Именованные профили (sortme auth --profile)
+	CurrentProfile string                        `mapstructure:"current_profile"` // Профиль по умолчанию (sortme profile use)
+	TokenStorage   string                        `mapstructure:"token_storage"`   // Где хранить session token: auto, keyring или plaintext
+
+	Languages map[string]LanguageConfig `mapstructure:"languages"` // Компиляторы, флаги и команды запуска для test и stress
+	Bundle    bool                      `mapstructure:"bundle"`    // Всегда собирать решение в один файл перед submit
+
+	Webhooks []WebhookConfig `mapstructure:"webhooks"` // Адреса для финальных вердиктов, с secret - с подписью HMAC
+
+	SourceSizeLimit int    `mapstructure:"source_size_limit"` // Ограничение на размер кода в КБ, если сервер его не сообщает (0 - не проверять)
+	GitOnAccept     string `mapstructure:"git_on_accept"`     // После AC: commit - закоммитить принятый код, tag - еще и тег ac/... (пусто - выключено)
+	CopyLink        bool   `mapstructure:"copy_link"`         // Копировать ссылку на отправку в буфер обмена после submit
+
+	// .sortme.yaml текущего каталога и файл, из которого взят контест.
+	// savedContest - current_contest из глобального конфига, он и сохраняется
+	workspace     *WorkspaceBinding
+	workspaceFile string
+	savedContest  string
+
+	// Профиль этого запуска и учетные данные основного профиля, пока активен другой
+	profile            string
+	defaultCredentials ProfileCredentials
+
+	// Конфиг в файле на момент загрузки (или последнего сохранения)
+	// и low_bandwidth без флага --low-bandwidth
+	loaded            map[string]interface{}
+	savedLowBandwidth bool
+}
+
+// Накладывает .sortme.yaml, найденный от текущего каталога вверх
+func (c *Config) applyWorkspace(dir string) error {
+	workspace, files, err := findWorkspace(dir)
+	if err != nil || workspace == nil {
+		return err
+	}
+	c.workspace = workspace
+	if workspace.ContestID != "" {
+		c.savedContest = c.CurrentContest
+		c.CurrentContest = workspace.ContestID
+		c.workspaceFile = files[0]
+	}
+	return nil
+}
+
+// Явная смена текущего контеста (use-contest, start) сохраняется как есть
+func (c *Config) setCurrentContest(contestID string) {
+	c.CurrentContest = contestID
+	c.savedContest = contestID
+}
+
+// Контест для записи в глобальный конфиг: контест проекта туда не попадает
+func (c *Config) persistedContest() string {
+	if c.workspaceFile != "" && c.workspace != nil && c.CurrentContest == c.workspace.ContestID {
+		return c.savedContest
+	}
+	return c.CurrentContest
+}
+
+// Личный дедлайн (лабораторная, домашнее задание и т.п.)
+type Deadline struct {
+	Name string `mapstructure:"name" yaml:"name" json:"name"`
+	Due  string `mapstructure:"due" yaml:"due" json:"due"` // Формат: 2006-01-02 15:04
+	Note string `mapstructure:"note" yaml:"note,omitempty" json:"note,omitempty"`
+}
+
+// Получатель вердиктов. С secret тело запроса подписывается HMAC-SHA256,
+// подпись - в заголовке X-Sortme-Signature-256
+type WebhookConfig struct {
+	URL    string `mapstructure:"url" yaml:"url" json:"url"`
+	Secret string `mapstructure:"secret" yaml:"secret,omitempty" json:"secret,omitempty"`
 }
 
 func getConfigPath() string {
@@ -35,7 +127,16 @@ func LoadConfig() (*Config, error) {
 	}
 
 	// Устанавливаем значения по умолчанию
-	viper.SetDefault("api_base_url", "https://sort-me.org/api")
+	viper.SetDefault("api_base_url", defaultAPIURL)
+	viper.SetDefault("api_ip", defaultAPIIP)
+	viper.SetDefault("insecure_tls", true)
+	viper.SetDefault("limit_warning_margin", 0.9)
+	viper.SetDefault("source_size_limit", defaultSourceSizeLimit)
+	viper.SetDefault("max_retries", defaultMaxRetries)
+	viper.SetDefault("backoff_base", defaultBackoffBase)
+	viper.SetDefault("workers", defaultWorkers)
+	viper.SetDefault("requests_per_second", defaultRequestsPerSecond)
+	viper.SetDefault("token_storage", tokenStorageAuto)
 
 	// Читаем конфиг
 	if err := viper.ReadInConfig(); err != nil {
@@ -53,15 +154,170 @@ func LoadConfig() (*Config, error) {
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	config.savedLowBandwidth = config.LowBandwidth
+	loaded, err := readConfigFile(configFilePath())
+	if err != nil {
+		return nil, err
+	}
+	config.loaded = loaded
+
+	// Старые версии записывали в конфиг адрес сайта вместо адреса API
+	if config.APIBaseURL == "" || config.APIBaseURL == legacyAPIBaseURL {
+		config.APIBaseURL = defaultAPIURL
+	}
+
+	// Старые версии держали токен бота в config.yaml открытым текстом: он
+	// переезжает к основному профилю, ключ из конфига удаляется
+	if token, ok := config.loaded[legacyTelegramTokenKey].(string); ok {
+		var err error
+		if token != "" {
+			err = config.storeNotifyToken(defaultProfileName, token)
+		}
+		if err == nil {
+			err = config.dropFileKey(legacyTelegramTokenKey)
+		}
+		if err != nil {
+			fmt.Fprint(os.Stderr, T("config.telegram_migrate_failed", err))
+		}
+	}
+
+	if config.loadKeyringTokens() {
+		if err := SaveConfig(&config); err != nil {
+			fmt.Fprint(os.Stderr, T("config.keyring_migrate_failed", err))
+		}
+	}
+
+	// Флаг --profile применяется позже и перекрывает этот выбор
+	if err := config.useProfile(cmp.Or(os.Getenv("SORTME_PROFILE"), config.CurrentProfile), false); err != nil {
+		fmt.Fprint(os.Stderr, T("config.profile_fallback", err))
+	}
 
 	return &config, nil
 }
 
+// Сохраняет конфиг целиком. Ключи, которые этот процесс не менял с загрузки,
+// берутся из файла: daemon и команда в соседнем терминале не затирают
+// изменения друг друга. Файл заменяется атомарно под блокировкой
 func SaveConfig(config *Config) error {
-	viper.Set("telegram_token", config.TelegramToken)
-	viper.Set("session_token", config.SessionToken)
-	viper.Set("user_id", config.UserID)
-	viper.Set("api_base_url", config.APIBaseURL)
+	lock, err := acquireLock("config")
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
 
-	return viper.WriteConfig()
+	ours, err := normalizeConfigValues(config.fileValues())
+	if err != nil {
+		return err
+	}
+	path := configFilePath()
+	current, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	for key, value := range ours {
+		loaded, wasLoaded := config.loaded[key]
+		_, onDisk := current[key]
+		if wasLoaded && !reflect.DeepEqual(loaded, value) || !wasLoaded && !onDisk {
+			current[key] = value
+		}
+	}
+
+	data, err := yaml.Marshal(current)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	config.loaded = current
+	// viper читают проверки безопасности и statesync
+	return viper.ReadInConfig()
+}
+
+// Удаляет из файла ключ, который эта версия больше не использует
+func (c *Config) dropFileKey(key string) error {
+	lock, err := acquireLock("config")
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	path := configFilePath()
+	current, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	delete(c.loaded, key)
+	if _, ok := current[key]; !ok {
+		return nil
+	}
+	delete(current, key)
+	data, err := yaml.Marshal(current)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return viper.ReadInConfig()
+}
+
+// Значения для файла по ключам mapstructure: переопределения этого запуска
+// (профиль, контест проекта, --low-bandwidth) заменяются сохраненными
+func (c *Config) fileValues() map[string]interface{} {
+	values := make(map[string]interface{})
+	rv := reflect.ValueOf(*c)
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		key := field.Tag.Get("mapstructure")
+		if key == "" || !field.IsExported() {
+			continue
+		}
+		value := rv.Field(i).Interface()
+		if d, ok := value.(time.Duration); ok {
+			value = d.String() // 500ms, а не наносекунды
+		}
+		values[key] = value
+	}
+
+	creds, profiles := c.stripKeyringTokens(c.persistedCredentials())
+	values["session_token"] = creds.SessionToken
+	values["user_id"] = creds.UserID
+	values["username"] = creds.Username
+	values["profiles"] = profiles
+	values["current_contest"] = c.persistedContest()
+	values["low_bandwidth"] = c.savedLowBandwidth
+	return values
+}
+
+// Значения в том виде, в каком их вернет разбор YAML: так их можно сравнить с файлом
+func normalizeConfigValues(values map[string]interface{}) (map[string]interface{}, error) {
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	normalized := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// Конфиг на диске как есть, включая неизвестные этой версии ключи
+func readConfigFile(path string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+	return values, nil
 }
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Итоги контеста в Markdown: что решено, за сколько, штраф и место, ссылки
+// на нерешенное для дорешивания. Пишется в каталог контеста, когда
+// отслеживаемый контест заканчивается
+const contestReportFile = "report_%s.md"
+
+// После окончания даем таблице и вердиктам последних отправок обновиться
+const contestReportDelay = time.Minute
+
+type ContestReport struct {
+	ContestID    string
+	Info         *ContestInfo
+	Tasks        []ReportTask
+	Place        int // 0 - таблица недоступна
+	Participants int
+	Total        int
+	Penalty      int
+	GeneratedAt  time.Time
+}
+
+type ReportTask struct {
+	Letter   string
+	ID       int
+	Name     string
+	Attempts int
+	Points   int
+	Solved   bool
+	Verdict  string        // вердикт последней отправки, "" - отправок не было
+	FirstAC  time.Duration // от начала контеста, -1 если AC нет или время неизвестно
+}
+
+// Время отправки: RFC3339 или unix-время строкой
+func parseSubmitTime(value string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil && unix > 0 {
+		return time.Unix(unix, 0), true
+	}
+	return time.Time{}, false
+}
+
+func (v *VSCodeExtension) buildContestReport(ctx context.Context, contestID string) (*ContestReport, error) {
+	info, err := v.apiClient.GetContestInfo(ctx, contestID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", T("api.contest_info_failed"), err)
+	}
+	submissions, err := v.apiClient.GetContestSubmissions(ctx, contestID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", T("report.submissions_failed"), err)
+	}
+
+	report := &ContestReport{ContestID: contestID, Info: info, GeneratedAt: time.Now()}
+	byID := make(map[int]*ReportTask)
+	for i, task := range info.Tasks {
+		report.Tasks = append(report.Tasks, ReportTask{Letter: taskLetter(i), ID: task.ID, Name: task.Name, FirstAC: -1})
+	}
+	for i := range report.Tasks {
+		byID[report.Tasks[i].ID] = &report.Tasks[i]
+	}
+
+	// Старые отправки первыми, чтобы найти первый AC
+	sort.Slice(submissions, func(i, j int) bool { return submissions[i].ID < submissions[j].ID })
+	started := time.Unix(info.Starts, 0)
+	for _, sub := range submissions {
+		taskID := sub.TaskID
+		if taskID == 0 {
+			taskID = sub.ProblemID
+		}
+		task, ok := byID[taskID]
+		if !ok {
+			continue
+		}
+		task.Attempts++
+		task.Points = max(task.Points, sub.TotalPoints)
+		task.Verdict = reportVerdict(sub)
+		if (sub.TotalPoints == 100 || sub.ShownVerdict == 1) && !task.Solved {
+			task.Solved = true
+			if at, ok := parseSubmitTime(cmp.Or(sub.SubmitTime, sub.Time)); ok && info.Starts > 0 {
+				task.FirstAC = at.Sub(started)
+			}
+		}
+	}
+
+	// Таблица может быть закрыта: отчет полезен и без места
+	if standings, err := v.apiClient.GetStandings(ctx, contestID); err == nil {
+		report.Participants = len(standings.Rows)
+		if row := v.findMyRow(standings); row != nil {
+			report.Place, report.Total, report.Penalty = row.Place, row.Total, row.Penalty
+		}
+	}
+	if report.Place == 0 {
+		for _, task := range report.Tasks {
+			report.Total += task.Points
+		}
+	}
+	return report, nil
+}
+
+// Короткий вердикт для таблицы отчета: OK, WA, частичное решение баллами
+func reportVerdict(sub Submission) string {
+	switch {
+	case isPendingSubmission(sub):
+		return "⏳"
+	case sub.ShownVerdict >= 1 && sub.ShownVerdict <= 6:
+		return getShortStatusText(sub.ShownVerdict)
+	case sub.TotalPoints > 0:
+		return fmt.Sprintf("PS %d", sub.TotalPoints)
+	}
+	return cmp.Or(sub.ShownVerdictText, "??")
+}
+
+// 1:07 или 27 мин
+func formatContestTime(d time.Duration) string {
+	if d < 0 {
+		return "-"
+	}
+	d = d.Truncate(time.Minute)
+	if d < time.Hour {
+		return T("unit.min", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+func (r *ContestReport) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", cmp.Or(r.Info.Name, T("report.contest_title", r.ContestID)))
+	if r.Info.Starts > 0 {
+		fmt.Fprintf(&b, "%s", time.Unix(r.Info.Starts, 0).Format("02.01.2006 15:04"))
+		if r.Info.Ends > 0 {
+			fmt.Fprintf(&b, " - %s", time.Unix(r.Info.Ends, 0).Format("15:04"))
+		}
+		b.WriteString(" · ")
+	}
+	fmt.Fprintf(&b, "[sort-me.org/contest/%s](https://sort-me.org/contest/%s)\n\n", r.ContestID, r.ContestID)
+
+	solved, attempted := 0, 0
+	for _, task := range r.Tasks {
+		if task.Attempts > 0 {
+			attempted++
+		}
+		if task.Solved {
+			solved++
+		}
+	}
+
+	b.WriteString(T("report.summary"))
+	fmt.Fprint(&b, T("report.solved", solved, len(r.Tasks), attempted))
+	fmt.Fprint(&b, T("report.points", r.Total))
+	if r.Place > 0 {
+		fmt.Fprint(&b, T("report.penalty", r.Penalty))
+		fmt.Fprint(&b, T("report.place", r.Place, r.Participants, r.GeneratedAt.Format("15:04")))
+	}
+
+	b.WriteString(T("report.tasks"))
+	b.WriteString(T("report.table_header"))
+	for _, task := range r.Tasks {
+		mark := "⬜"
+		switch {
+		case task.Solved:
+			mark = "✅"
+		case task.Points > 0:
+			mark = "🟡"
+		case task.Attempts > 0:
+			mark = "❌"
+		}
+		ac := "-"
+		if task.Solved {
+			ac = formatContestTime(task.FirstAC)
+		}
+		fmt.Fprintf(&b, "| %s | %s. %s | %d | %s | %d | %s |\n", mark, task.Letter, task.Name, task.Attempts, cmp.Or(task.Verdict, "-"), task.Points, ac)
+	}
+
+	var unsolved []ReportTask
+	for _, task := range r.Tasks {
+		if !task.Solved {
+			unsolved = append(unsolved, task)
+		}
+	}
+	if len(unsolved) > 0 {
+		b.WriteString(T("report.upsolving"))
+		for _, task := range unsolved {
+			fmt.Fprintf(&b, "- [ ] [%s. %s](https://sort-me.org/contest/%s) - `sortme read %s %d`\n",
+				task.Letter, task.Name, r.ContestID, r.ContestID, task.ID)
+		}
+	}
+
+	fmt.Fprint(&b, T("report.footer", r.GeneratedAt.Format("02.01.2006 15:04")))
+	return b.String()
+}
+
+func (v *VSCodeExtension) createReportCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "report [contest_id]",
+		Short: T("report.short"),
+		Long:  T("report.long"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			contestID := v.config.CurrentContest
+			if len(args) > 0 {
+				contestID = args[0]
+			}
+			if contestID == "" {
+				return errors.New(T("contest.missing_report"))
+			}
+			return v.handleReport(cmd.Context(), contestID, output)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", T("flag.report_output"))
+	return cmd
+}
+
+// Отчет в stdout, чтобы вставить в журнал, или в файл с -o
+func (v *VSCodeExtension) handleReport(ctx context.Context, contestID, output string) error {
+	if !v.apiClient.IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+	// Ход загрузки уходит в stderr, чтобы в stdout остался только Markdown
+	out := os.Stdout
+	if output == "" && !v.jsonMode() {
+		os.Stdout = os.Stderr
+		defer func() { os.Stdout = out }()
+	}
+	report, err := v.buildContestReport(ctx, contestID)
+	if err != nil {
+		return err
+	}
+	markdown := report.Markdown()
+
+	if output == "" {
+		fmt.Fprint(out, markdown)
+	} else {
+		if err := os.WriteFile(output, []byte(markdown), 0644); err != nil {
+			return err
+		}
+		fmt.Print(T("report.saved", output))
+	}
+	v.emitJSON(map[string]interface{}{
+		"contest_id": contestID,
+		"total":      report.Total,
+		"file":       output,
+		"markdown":   markdown,
+	})
+	return nil
+}
+
+// Каталог для отчета рядом с path: каталог контеста, а не каталог задачи из sortme init
+func reportDir(path string) string {
+	binding, files, err := findWorkspace(path)
+	if err != nil || binding == nil {
+		return path
+	}
+	dir := filepath.Dir(files[0])
+	if binding.ProblemID != "" {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
+
+// Формирует и сохраняет отчет, возвращает путь к файлу
+func (v *VSCodeExtension) saveContestReport(ctx context.Context, contestID, dir string) (string, error) {
+	report, err := v.buildContestReport(ctx, contestID)
+	if err != nil {
+		return "", err
+	}
+	filename := filepath.Join(dir, fmt.Sprintf(contestReportFile, contestID))
+	if err := os.WriteFile(filename, []byte(report.Markdown()), 0644); err != nil {
+		return "", err
+	}
+	return filename, nil
+}
+
+// Канал срабатывает, когда отслеживаемый контест закончился (nil - контест
+// уже закончен или время окончания неизвестно)
+func (v *VSCodeExtension) contestEnd(ctx context.Context, contestID string) <-chan time.Time {
+	info, err := v.apiClient.GetContestInfo(ctx, contestID)
+	if err != nil || info.Ends == 0 {
+		return nil
+	}
+	left := time.Until(time.Unix(info.Ends, 0))
+	if left <= 0 {
+		return nil
+	}
+	return time.After(left + contestReportDelay)
+}
+
+func (v *VSCodeExtension) reportContestEnd(ctx context.Context, contestID, near string) {
+	progress(T("report.generating", contestID))
+	filename, err := v.saveContestReport(ctx, contestID, reportDir(near))
+	if err != nil {
+		progress(T("report.failed", err))
+		return
+	}
+	fmt.Print(T("report.summary_file", filename))
+}
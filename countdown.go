@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// Обратный отсчет до начала предстоящего или до конца идущего контеста.
+// С --live строка обновляется каждую секунду и сама переходит от начала
+// к концу, пока контест не закончится
+
+type contestTiming struct {
+	Name   string
+	Starts time.Time
+	Ends   time.Time // нулевое - время окончания неизвестно
+}
+
+func (v *VSCodeExtension) createCountdownCommand() *cobra.Command {
+	var live bool
+
+	cmd := &cobra.Command{
+		Use:   "countdown [contest_id]",
+		Short: T("countdown.short"),
+		Long:  T("countdown.long"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			contestID := v.config.CurrentContest
+			if len(args) > 0 {
+				contestID = args[0]
+			}
+			if contestID == "" {
+				return fmt.Errorf("%s", T("contest.missing"))
+			}
+			if !v.apiClient.IsAuthenticated() {
+				return ErrNotAuthenticated
+			}
+			return v.handleCountdown(cmd.Context(), contestID, live)
+		},
+	}
+
+	cmd.Flags().BoolVar(&live, "live", false, T("flag.countdown_live"))
+	return cmd
+}
+
+// Начало и конец контеста: из списка предстоящих, иначе из информации о контесте
+func (v *VSCodeExtension) contestTiming(ctx context.Context, contestID string) (*contestTiming, error) {
+	if upcoming, err := v.apiClient.getUpcomingContestList(ctx); err == nil {
+		for _, contest := range upcoming {
+			if strconv.Itoa(contest.ID) == contestID && contest.Starts > 0 {
+				return newContestTiming(contest.Name, contest.Starts, contest.Ends), nil
+			}
+		}
+	}
+	info, err := v.apiClient.GetContestInfo(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if info.Starts == 0 {
+		return nil, errors.New(T("countdown.no_time", contestID))
+	}
+	return newContestTiming(info.Name, info.Starts, info.Ends), nil
+}
+
+func newContestTiming(name string, starts, ends int64) *contestTiming {
+	timing := &contestTiming{Name: name, Starts: time.Unix(starts, 0)}
+	if ends > 0 {
+		timing.Ends = time.Unix(ends, 0)
+	}
+	return timing
+}
+
+// Что сейчас отсчитываем: "start", "end" или "finished" и сколько осталось
+func (t *contestTiming) phase(now time.Time) (string, time.Duration) {
+	switch {
+	case now.Before(t.Starts):
+		return "start", t.Starts.Sub(now)
+	case !t.Ends.IsZero() && now.Before(t.Ends):
+		return "end", t.Ends.Sub(now)
+	}
+	return "finished", 0
+}
+
+// 2д 03:04:05 или 1:05:09
+func formatClock(d time.Duration) string {
+	d = d.Round(time.Second)
+	days := int(d.Hours()) / 24
+	clock := fmt.Sprintf("%d:%02d:%02d", int(d.Hours())%24, int(d.Minutes())%60, int(d.Seconds())%60)
+	if days > 0 {
+		return T("countdown.days", days, clock)
+	}
+	return clock
+}
+
+func (t *contestTiming) line(now time.Time) string {
+	phase, left := t.phase(now)
+	switch phase {
+	case "start":
+		return T("countdown.starts_in", t.Name, formatClock(left), t.Starts.Local().Format("02.01 15:04"))
+	case "end":
+		return T("countdown.ends_in", t.Name, formatClock(left), t.Ends.Local().Format("02.01 15:04"))
+	}
+	if t.Ends.IsZero() {
+		return T("countdown.running", t.Name)
+	}
+	return T("countdown.ended", t.Name, t.Ends.Local().Format("02.01 15:04"))
+}
+
+func (v *VSCodeExtension) handleCountdown(ctx context.Context, contestID string, live bool) error {
+	if live && v.jsonMode() {
+		return errors.New(T("countdown.live_with_json"))
+	}
+	timing, err := v.contestTiming(ctx, contestID)
+	if err != nil {
+		return err
+	}
+	// Как и wait, сверяемся с часами сервера, без них - локальные
+	offset, _ := v.apiClient.ServerClockOffset(ctx)
+	now := func() time.Time { return time.Now().Add(offset) }
+
+	phase, left := timing.phase(now())
+	result := map[string]interface{}{
+		"contest_id":        contestID,
+		"name":              timing.Name,
+		"phase":             phase,
+		"starts":            timing.Starts.Unix(),
+		"remaining_seconds": int(left.Seconds()),
+	}
+	if !timing.Ends.IsZero() {
+		result["ends"] = timing.Ends.Unix()
+	}
+	v.emitJSON(result)
+
+	if !live {
+		fmt.Println(timing.line(now()))
+		return nil
+	}
+
+	// В терминале одна строка перерисовывается, в файл или pipe - строка в минуту
+	tty := term.IsTerminal(int(os.Stdout.Fd()))
+	tick := time.Second
+	if !tty {
+		tick = time.Minute
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		line := timing.line(now())
+		if tty {
+			fmt.Printf("\r\x1b[K%s", line)
+		} else {
+			fmt.Println(line)
+		}
+		if phase, _ := timing.phase(now()); phase == "finished" {
+			if tty {
+				fmt.Println()
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			if tty {
+				fmt.Println()
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
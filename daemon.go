@@ -0,0 +1,535 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// Постоянный процесс для расширения редактора: JSON-RPC 2.0 через stdin/stdout.
+// Конфиг читается один раз, кэш и соединения живут между запросами.
+// Сообщения - JSON по одному в строке или с заголовком Content-Length, как в
+// LSP; ответы пишутся в том же виде, что и первый запрос. Весь человекочитаемый
+// вывод уходит в stderr
+//
+// Методы:
+//
+//	initialize                      пользователь, профиль и список методов
+//	contests                        список контестов
+//	problems {contest_id}           задачи контеста
+//	submit {file|code, contest_id, problem_id, language, watch}
+//	status {submission_id}          текущий статус отправки
+//	watch {submission_id}           уведомления verdict до финального вердикта
+//	shutdown                        завершить процесс
+//
+// Уведомление verdict: {submission_id, status, final, source, error}.
+// $/cancelRequest {id} отменяет запрос, как в LSP
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+
+	rpcNotAuthenticated = -32001
+	rpcRateLimited      = -32002
+	rpcNotFound         = -32004
+	rpcRequestCancelled = -32800
+)
+
+var daemonMethods = []string{"initialize", "contests", "problems", "submit", "status", "watch", "shutdown"}
+
+type rpcMessage struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *rpcError        `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+func invalidParams(message string) *rpcError {
+	return &rpcError{Code: rpcInvalidParams, Message: message}
+}
+
+// Ошибка APIClient в коде JSON-RPC: вид ошибки тот же, что у --json
+func toRPCError(err error) *rpcError {
+	var rpcErr *rpcError
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	result := &rpcError{Code: rpcInternalError, Message: err.Error()}
+	kind := apiErrorKind(err)
+	switch kind {
+	case "not_authenticated":
+		result.Code = rpcNotAuthenticated
+	case "rate_limited":
+		result.Code = rpcRateLimited
+		var rateLimited *ErrRateLimited
+		if errors.As(err, &rateLimited) && rateLimited.RetryAfter > 0 {
+			result.Data = map[string]interface{}{"kind": kind, "retry_after": rateLimited.RetryAfter.Seconds()}
+			return result
+		}
+	case "not_found":
+		result.Code = rpcNotFound
+	}
+	if errors.Is(err, context.Canceled) {
+		result.Code = rpcRequestCancelled
+	}
+	if kind != "" {
+		result.Data = map[string]string{"kind": kind}
+	}
+	return result
+}
+
+// Чтение и запись сообщений в одном из двух форматов
+type rpcConn struct {
+	reader *bufio.Reader
+
+	mu     sync.Mutex
+	out    io.Writer
+	framed bool // Content-Length заголовки вместо строк
+	known  bool // формат уже определен по первому сообщению
+}
+
+func (c *rpcConn) read() ([]byte, error) {
+	for {
+		line, err := c.reader.ReadString('\n')
+		trimmed := strings.TrimSpace(line)
+		if err != nil && trimmed == "" {
+			return nil, err
+		}
+		if trimmed == "" {
+			continue
+		}
+
+		header, value, ok := strings.Cut(trimmed, ":")
+		if !ok || !strings.EqualFold(header, "Content-Length") {
+			c.detect(false)
+			return []byte(trimmed), nil
+		}
+
+		length, convErr := strconv.Atoi(strings.TrimSpace(value))
+		if convErr != nil || length < 0 {
+			return nil, errors.New(T("daemon.bad_content_length", value))
+		}
+		// Остальные заголовки (Content-Type) до пустой строки пропускаем
+		for {
+			line, err := c.reader.ReadString('\n')
+			if err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(line) == "" {
+				break
+			}
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(c.reader, body); err != nil {
+			return nil, err
+		}
+		c.detect(true)
+		return body, nil
+	}
+}
+
+func (c *rpcConn) detect(framed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.known {
+		c.framed, c.known = framed, true
+	}
+}
+
+func (c *rpcConn) write(message rpcMessage) {
+	message.JSONRPC = "2.0"
+	data, err := json.Marshal(message)
+	if err != nil {
+		data, _ = json.Marshal(rpcMessage{JSONRPC: "2.0", ID: message.ID, Error: &rpcError{Code: rpcInternalError, Message: err.Error()}})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.framed {
+		fmt.Fprintf(c.out, "Content-Length: %d\r\n\r\n%s", len(data), data)
+		return
+	}
+	c.out.Write(append(data, '\n'))
+}
+
+func (c *rpcConn) notify(method string, params interface{}) {
+	raw, _ := json.Marshal(params)
+	c.write(rpcMessage{Method: method, Params: raw})
+}
+
+type daemon struct {
+	v    *VSCodeExtension
+	conn *rpcConn
+	ctx  context.Context // живет до shutdown, в отличие от контекста запроса
+
+	mu       sync.Mutex
+	requests map[string]context.CancelFunc // идущие запросы по id для $/cancelRequest
+	notified map[string]bool               // отправки, о вердикте которых уже уведомили
+	wg       sync.WaitGroup
+}
+
+func (v *VSCodeExtension) createDaemonCommand() *cobra.Command {
+	var stdio bool
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: T("daemon.short"),
+		Long:  T("daemon.long"),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !stdio {
+				return errors.New(T("daemon.transport_missing"))
+			}
+			cmd.SilenceUsage = true
+			return v.runDaemon(cmd.Context(), os.Stdin, os.Stdout)
+		},
+	}
+	cmd.Flags().BoolVar(&stdio, "stdio", false, T("flag.daemon_stdio"))
+	return cmd
+}
+
+func (v *VSCodeExtension) runDaemon(ctx context.Context, in io.Reader, out *os.File) error {
+	// stdout занят протоколом: все fmt.Print* дальше пишут в stderr
+	if out == os.Stdout {
+		os.Stdout = os.Stderr
+		defer func() { os.Stdout = out }()
+	}
+	ctx, shutdown := context.WithCancel(ctx)
+	defer shutdown()
+
+	d := &daemon{
+		v:        v,
+		ctx:      ctx,
+		conn:     &rpcConn{reader: bufio.NewReader(in), out: out},
+		requests: make(map[string]context.CancelFunc),
+		notified: make(map[string]bool),
+	}
+	fmt.Fprintln(os.Stderr, T("daemon.started"))
+
+	messages := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			data, err := d.conn.read()
+			if err != nil {
+				readErr <- err
+				return
+			}
+			messages <- data
+		}
+	}()
+
+	defer d.wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-readErr:
+			// Редактор закрыл stdin - обычное завершение
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case data := <-messages:
+			if d.dispatch(ctx, data) {
+				shutdown()
+			}
+		}
+	}
+}
+
+// Разбирает сообщение и запускает обработчик. true - пришел shutdown
+func (d *daemon) dispatch(ctx context.Context, data []byte) bool {
+	var request rpcMessage
+	if err := json.Unmarshal(data, &request); err != nil {
+		d.conn.write(rpcMessage{Error: &rpcError{Code: rpcParseError, Message: err.Error()}})
+		return false
+	}
+	if request.Method == "" {
+		// Ответы клиента на наши запросы не ожидаются
+		if request.ID != nil && request.Result == nil && request.Error == nil {
+			d.conn.write(rpcMessage{ID: request.ID, Error: &rpcError{Code: rpcInvalidRequest, Message: T("daemon.no_method")}})
+		}
+		return false
+	}
+
+	switch request.Method {
+	case "$/cancelRequest":
+		var params struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if json.Unmarshal(request.Params, &params) == nil {
+			d.cancel(string(params.ID))
+		}
+		return false
+	case "shutdown", "exit":
+		if request.ID != nil {
+			d.conn.write(rpcMessage{ID: request.ID, Result: true})
+		}
+		return true
+	}
+
+	reqCtx, cancel := context.WithCancel(ctx)
+	key := ""
+	if request.ID != nil {
+		key = string(*request.ID)
+		d.mu.Lock()
+		d.requests[key] = cancel
+		d.mu.Unlock()
+	}
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer cancel()
+		result, err := d.call(reqCtx, request.Method, request.Params)
+		if key != "" {
+			d.mu.Lock()
+			delete(d.requests, key)
+			d.mu.Unlock()
+		}
+		if request.ID == nil {
+			return
+		}
+		if err != nil {
+			d.conn.write(rpcMessage{ID: request.ID, Error: toRPCError(err)})
+			return
+		}
+		if result == nil {
+			result = struct{}{}
+		}
+		d.conn.write(rpcMessage{ID: request.ID, Result: result})
+	}()
+	return false
+}
+
+func (d *daemon) cancel(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if cancel, ok := d.requests[id]; ok {
+		cancel()
+	}
+}
+
+// true при первом финальном вердикте отправки: submit и watch по той же
+// отправке не должны уведомлять дважды
+func (d *daemon) firstVerdict(submissionID string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.notified[submissionID] {
+		return false
+	}
+	d.notified[submissionID] = true
+	return true
+}
+
+func (d *daemon) call(ctx context.Context, method string, params json.RawMessage) (interface{}, error) {
+	decode := func(target interface{}) error {
+		if len(params) == 0 || string(params) == "null" {
+			return nil
+		}
+		if err := json.Unmarshal(params, target); err != nil {
+			return invalidParams(T("daemon.bad_params", method, err))
+		}
+		return nil
+	}
+	api := d.v.apiClient
+
+	switch method {
+	case "initialize":
+		return map[string]interface{}{
+			"username":      d.v.config.Username,
+			"user_id":       d.v.config.UserID,
+			"profile":       d.v.config.profileName(),
+			"authenticated": api.IsAuthenticated(),
+			"contest_id":    d.v.config.CurrentContest,
+			"methods":       daemonMethods,
+		}, nil
+
+	case "contests":
+		return api.GetContests(ctx)
+
+	case "problems":
+		var p struct {
+			ContestID string `json:"contest_id"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		contestID := cmp.Or(p.ContestID, d.v.config.CurrentContest)
+		if contestID == "" {
+			return nil, invalidParams(T("daemon.contest_missing"))
+		}
+		return api.GetContestInfo(ctx, contestID)
+
+	case "status":
+		var p struct {
+			SubmissionID string `json:"submission_id"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if p.SubmissionID == "" {
+			return nil, invalidParams(T("daemon.submission_missing"))
+		}
+		return api.GetSubmissionStatus(ctx, cleanSubmissionID(p.SubmissionID))
+
+	case "watch":
+		var p struct {
+			SubmissionID string `json:"submission_id"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if p.SubmissionID == "" {
+			return nil, invalidParams(T("daemon.submission_missing"))
+		}
+		return d.streamVerdicts(ctx, cleanSubmissionID(p.SubmissionID), "", "")
+
+	case "submit":
+		var p daemonSubmitParams
+		p.Watch = true
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		return d.submit(ctx, p)
+	}
+	return nil, &rpcError{Code: rpcMethodNotFound, Message: T("daemon.unknown_method", method)}
+}
+
+type daemonSubmitParams struct {
+	File      string `json:"file"`
+	Code      string `json:"code"` // несохраненный буфер редактора вместо файла
+	ContestID string `json:"contest_id"`
+	ProblemID string `json:"problem_id"`
+	Language  string `json:"language"`
+	Watch     bool   `json:"watch"` // присылать verdict до финального вердикта (по умолчанию да)
+}
+
+func (d *daemon) submit(ctx context.Context, p daemonSubmitParams) (interface{}, error) {
+	v := d.v
+	if !v.apiClient.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	opts := SubmitOptions{ContestID: p.ContestID, ProblemID: p.ProblemID, Language: p.Language}
+	code := p.Code
+	if p.File != "" {
+		filename, err := filepath.Abs(p.File)
+		if err != nil {
+			return nil, invalidParams(T("daemon.bad_path", p.File, err))
+		}
+		v.applyWorkspaceBinding(filename, &opts)
+		if opts.Language == "" {
+			opts.Language = v.apiClient.DetectLanguage(filename)
+		}
+		if code == "" {
+			if code, err = ReadSourceCode(filename); err != nil {
+				return nil, invalidParams(T("daemon.read_failed", p.File, err))
+			}
+		}
+	}
+	opts.ContestID = cmp.Or(opts.ContestID, v.config.CurrentContest)
+
+	switch {
+	case code == "":
+		return nil, invalidParams(T("daemon.code_missing"))
+	case opts.ContestID == "":
+		return nil, invalidParams(T("daemon.contest_id_missing"))
+	case opts.ProblemID == "":
+		return nil, invalidParams(T("daemon.problem_id_missing"))
+	case opts.Language == "" || opts.Language == "unknown":
+		return nil, invalidParams(T("daemon.language_unknown"))
+	}
+	language, err := d.v.judgeLanguage(ctx, opts.Language)
+	if err != nil {
+		return nil, invalidParams(err.Error())
+	}
+	opts.Language = language
+
+	response, err := v.apiClient.SubmitSolution(ctx, opts.ContestID, opts.ProblemID, opts.Language, code)
+	if err != nil {
+		return nil, err
+	}
+	submissionID := cleanSubmissionID(response.ID)
+	if id, err := strconv.Atoi(submissionID); err == nil {
+		if taskID, err := strconv.Atoi(opts.ProblemID); err == nil {
+			if db, err := OpenSubmissionDB(); err == nil {
+				db.RecordSubmit(id, opts.ContestID, taskID, opts.Language, code)
+				db.Close()
+			}
+		}
+	}
+
+	// Ответ на submit не ждет вердикта: он приходит уведомлениями verdict
+	if p.Watch {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.streamVerdicts(d.ctx, submissionID, opts.ContestID, opts.ProblemID)
+		}()
+	}
+	return map[string]interface{}{
+		"submission_id": submissionID,
+		"contest_id":    opts.ContestID,
+		"problem_id":    opts.ProblemID,
+		"language":      opts.Language,
+	}, nil
+}
+
+// Пересылает состояния отправки уведомлениями verdict и возвращает последнее.
+// О финальном вердикте сообщает в webhooks и Telegram, как submit --watch
+func (d *daemon) streamVerdicts(ctx context.Context, submissionID, contestID, problemID string) (*SubmissionStatus, error) {
+	events, err := d.v.apiClient.WatchSubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	var last *SubmissionStatus
+	for event := range events {
+		notification := map[string]interface{}{
+			"submission_id": submissionID,
+			"status":        event.Status,
+			"final":         event.Final,
+			"source":        event.Source,
+		}
+		if event.Err != nil {
+			notification["error"] = event.Err.Error()
+			err = event.Err
+		}
+		if event.Status != nil {
+			last = event.Status
+		}
+		d.conn.notify("verdict", notification)
+		if event.Final && event.Status != nil && d.firstVerdict(submissionID) {
+			d.v.notifyVerdict(event.Status, contestID, problemID, "")
+		}
+	}
+	if err == nil && last == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return last, err
+}
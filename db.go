@@ -0,0 +1,317 @@
+package main
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const submissionsDBFile = "submissions.db"
+
+const submissionsSchema = `
+CREATE TABLE IF NOT EXISTS submissions (
+	id           INTEGER PRIMARY KEY,
+	contest_id   TEXT    NOT NULL DEFAULT '',
+	contest_name TEXT    NOT NULL DEFAULT '',
+	task_id      INTEGER NOT NULL DEFAULT 0,
+	task_name    TEXT    NOT NULL DEFAULT '',
+	verdict      INTEGER NOT NULL DEFAULT 0,
+	verdict_text TEXT    NOT NULL DEFAULT '',
+	points       INTEGER NOT NULL DEFAULT 0,
+	language     TEXT    NOT NULL DEFAULT '',
+	submit_time  TEXT    NOT NULL DEFAULT '',
+	code_hash    TEXT    NOT NULL DEFAULT '',
+	synced_at    INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS submissions_task ON submissions (contest_id, task_id);
+
+CREATE TABLE IF NOT EXISTS contest_sync (
+	contest_id TEXT PRIMARY KEY,
+	name       TEXT    NOT NULL DEFAULT '',
+	status     TEXT    NOT NULL DEFAULT '',
+	ends       INTEGER NOT NULL DEFAULT 0,
+	synced_at  INTEGER NOT NULL DEFAULT 0
+);
+`
+
+// Локальная база отправок пользователя (заполняется командой sync)
+type SubmissionDB struct {
+	db *sql.DB
+}
+
+func getSubmissionDBPath() string {
+	return filepath.Join(profileDataDir(getConfigPath()), submissionsDBFile)
+}
+
+func OpenSubmissionDB() (*SubmissionDB, error) {
+	if err := os.MkdirAll(filepath.Dir(getSubmissionDBPath()), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	// busy_timeout: параллельный sortme подождет, а не упадет с "database is locked"
+	db, err := sql.Open("sqlite", getSubmissionDBPath()+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if _, err := db.Exec(submissionsSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to init database: %w", err)
+	}
+	return &SubmissionDB{db: db}, nil
+}
+
+func (d *SubmissionDB) Close() error {
+	return d.db.Close()
+}
+
+// Хэш исходного кода, чтобы находить повторные отправки одного и того же решения
+func codeHash(sourceCode string) string {
+	sum := sha256.Sum256([]byte(sourceCode))
+	return hex.EncodeToString(sum[:])
+}
+
+// Сохраняет или обновляет отправку. Хэш кода при синхронизации не затирается.
+// Возвращает, была ли отправка новой и изменился ли у нее вердикт или балл
+func (d *SubmissionDB) Upsert(sub Submission) (created, changed bool, err error) {
+	var verdict, points int
+	err = d.db.QueryRow(`SELECT verdict, points FROM submissions WHERE id = ?`, sub.ID).Scan(&verdict, &points)
+	switch {
+	case err == sql.ErrNoRows:
+		created = true
+	case err != nil:
+		return false, false, err
+	default:
+		changed = verdict != sub.ShownVerdict || points != sub.TotalPoints
+	}
+
+	submitTime := sub.SubmitTime
+	if submitTime == "" {
+		submitTime = sub.Time
+	}
+	_, err = d.db.Exec(`
+		INSERT INTO submissions (id, contest_id, contest_name, task_id, task_name, verdict, verdict_text, points, language, submit_time, synced_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			contest_id   = CASE WHEN excluded.contest_id != '' THEN excluded.contest_id ELSE contest_id END,
+			contest_name = CASE WHEN excluded.contest_name != '' THEN excluded.contest_name ELSE contest_name END,
+			task_id      = CASE WHEN excluded.task_id != 0 THEN excluded.task_id ELSE task_id END,
+			task_name    = CASE WHEN excluded.task_name != '' THEN excluded.task_name ELSE task_name END,
+			verdict      = excluded.verdict,
+			verdict_text = excluded.verdict_text,
+			points       = excluded.points,
+			language     = CASE WHEN excluded.language != '' THEN excluded.language ELSE language END,
+			submit_time  = CASE WHEN excluded.submit_time != '' THEN excluded.submit_time ELSE submit_time END,
+			synced_at    = excluded.synced_at`,
+		sub.ID, sub.ContestID, sub.ContestName, sub.ProblemID, sub.ProblemName,
+		sub.ShownVerdict, sub.ShownVerdictText, sub.TotalPoints, sub.Language, submitTime,
+		time.Now().Unix())
+	return created, changed, err
+}
+
+// Запоминает только что отправленное решение вместе с хэшем кода
+func (d *SubmissionDB) RecordSubmit(submissionID int, contestID string, taskID int, language, sourceCode string) error {
+	_, err := d.db.Exec(`
+		INSERT INTO submissions (id, contest_id, task_id, language, submit_time, code_hash)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET code_hash = excluded.code_hash`,
+		submissionID, contestID, taskID, language, time.Now().Format(time.RFC3339), codeHash(sourceCode))
+	return err
+}
+
+// Контест, задача и язык отправки из локальной базы. false - отправки в базе нет
+func (d *SubmissionDB) Submission(id int) (Submission, bool, error) {
+	sub := Submission{ID: id}
+	err := d.db.QueryRow(`SELECT contest_id, task_id, language FROM submissions WHERE id = ?`, id).
+		Scan(&sub.ContestID, &sub.ProblemID, &sub.Language)
+	if err == sql.ErrNoRows {
+		return sub, false, nil
+	}
+	return sub, err == nil, err
+}
+
+func (d *SubmissionDB) MarkContestSynced(contestID string, info *ContestInfo) error {
+	_, err := d.db.Exec(`
+		INSERT INTO contest_sync (contest_id, name, status, ends, synced_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(contest_id) DO UPDATE SET
+			name = excluded.name, status = excluded.status, ends = excluded.ends, synced_at = excluded.synced_at`,
+		contestID, info.Name, info.Status, info.Ends, time.Now().Unix())
+	return err
+}
+
+// Отправки завершенного контеста больше не меняются: если он синхронизирован
+// после окончания (или уже был архивным), повторно его не загружаем
+func (d *SubmissionDB) ContestUpToDate(contestID string) (bool, error) {
+	var status string
+	var ends, syncedAt int64
+	err := d.db.QueryRow(`SELECT status, ends, synced_at FROM contest_sync WHERE contest_id = ?`, contestID).
+		Scan(&status, &ends, &syncedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return status == "archive" || (ends > 0 && syncedAt > ends), nil
+}
+
+// Заставляет sync заново загрузить контест, который считался окончательно
+// синхронизированным. Отправки и время синхронизации остаются для работы без сети
+func (d *SubmissionDB) InvalidateContest(contestID string) (bool, error) {
+	result, err := d.db.Exec(`UPDATE contest_sync SET status = '', ends = 0 WHERE contest_id = ?`, contestID)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// Время последней синхронизации контеста (нулевое, если его нет в базе)
+func (d *SubmissionDB) ContestSyncedAt(contestID string) (time.Time, error) {
+	var syncedAt int64
+	err := d.db.QueryRow(`SELECT synced_at FROM contest_sync WHERE contest_id = ?`, contestID).Scan(&syncedAt)
+	if err == sql.ErrNoRows || (err == nil && syncedAt == 0) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(syncedAt, 0), nil
+}
+
+// Контесты, которые уже есть в базе
+func (d *SubmissionDB) SyncedContests() ([]string, error) {
+	rows, err := d.db.Query(`SELECT contest_id FROM contest_sync ORDER BY contest_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contests []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		contests = append(contests, id)
+	}
+	return contests, rows.Err()
+}
+
+// Отправки контеста из базы, новые первыми
+func (d *SubmissionDB) ContestSubmissions(contestID string, page Page) ([]Submission, error) {
+	query := `SELECT id, contest_id, contest_name, task_id, task_name, verdict, verdict_text, points, language, submit_time
+		FROM submissions WHERE contest_id = ? ORDER BY id DESC`
+	args := []interface{}{contestID}
+	if page.Limit > 0 || page.Offset > 0 {
+		// В SQLite OFFSET без LIMIT не бывает, -1 - без ограничения
+		limit := -1
+		if page.Limit > 0 {
+			limit = page.Limit
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, max(page.Offset, 0))
+	}
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var submissions []Submission
+	for rows.Next() {
+		var sub Submission
+		if err := rows.Scan(&sub.ID, &sub.ContestID, &sub.ContestName, &sub.ProblemID, &sub.ProblemName,
+			&sub.ShownVerdict, &sub.ShownVerdictText, &sub.TotalPoints, &sub.Language, &sub.SubmitTime); err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, sub)
+	}
+	return submissions, rows.Err()
+}
+
+// Все отправки из базы в порядке отправки, contestID - только одного контеста
+func (d *SubmissionDB) AllSubmissions(contestID string) ([]Submission, error) {
+	query := `SELECT id, contest_id, contest_name, task_id, task_name, verdict, verdict_text, points, language, submit_time
+		FROM submissions`
+	var args []interface{}
+	if contestID != "" {
+		query += " WHERE contest_id = ?"
+		args = append(args, contestID)
+	}
+	rows, err := d.db.Query(query+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var submissions []Submission
+	for rows.Next() {
+		var sub Submission
+		if err := rows.Scan(&sub.ID, &sub.ContestID, &sub.ContestName, &sub.ProblemID, &sub.ProblemName,
+			&sub.ShownVerdict, &sub.ShownVerdictText, &sub.TotalPoints, &sub.Language, &sub.SubmitTime); err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, sub)
+	}
+	return submissions, rows.Err()
+}
+
+// Сводка по задаче из базы: решена ли, лучший балл и число попыток
+type TaskSummary struct {
+	Solved   bool
+	Points   int
+	Attempts int
+}
+
+func (d *SubmissionDB) TaskSummary(contestID string, taskID int) (TaskSummary, error) {
+	var summary TaskSummary
+	var solved int
+	query := `SELECT COUNT(*), COALESCE(MAX(points), 0), COALESCE(MAX(verdict = 1 OR points = 100), 0)
+		FROM submissions WHERE task_id = ?`
+	args := []interface{}{taskID}
+	if contestID != "" {
+		query += " AND contest_id = ?"
+		args = append(args, contestID)
+	}
+	err := d.db.QueryRow(query, args...).Scan(&summary.Attempts, &summary.Points, &solved)
+	summary.Solved = solved == 1
+	return summary, err
+}
+
+// Когда API недоступен, list показывает отправки из локальной базы,
+// если контест хоть раз синхронизировался
+func (a *APIClient) fallbackLocalSubmissions(contestID string, page Page, cause error) ([]Submission, bool) {
+	db, err := OpenSubmissionDB()
+	if err != nil {
+		return nil, false
+	}
+	defer db.Close()
+
+	syncedAt, err := db.ContestSyncedAt(contestID)
+	if err != nil || syncedAt.IsZero() {
+		return nil, false
+	}
+	submissions, err := db.ContestSubmissions(contestID, page)
+	if err != nil {
+		return nil, false
+	}
+	a.offline.note(syncedAt, cause)
+	return submissions, true
+}
+
+// Отправки контеста из локальной базы (list --local)
+func loadLocalSubmissions(contestID string, page Page) ([]Submission, error) {
+	db, err := OpenSubmissionDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return db.ContestSubmissions(contestID, page)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Переименованные команды и флаги. Старые имена продолжают работать
+// еще несколько релизов: они скрыты из справки и при вызове печатают
+// предупреждение в stderr, чтобы не ломать скрипты и расширение VSCode.
+// Предупреждения можно отключить переменной SORTME_NO_DEPRECATION_WARNINGS=1
+
+// Старое имя команды верхнего уровня и путь новой ("auth", "devtools quarantine")
+type renamedCommand struct {
+	Old string
+	New string
+}
+
+// Флаг команды Command (путь, как у renamedCommand), переименованный из Old в New
+type renamedFlag struct {
+	Command string
+	Old     string
+	New     string
+}
+
+var renamedCommands = []renamedCommand{
+	// Упоминались в подсказках, вход давно выполняется одной командой auth
+	{Old: "webauth", New: "auth"},
+	{Old: "manualauth", New: "auth"},
+}
+
+// Пример: {Command: "list", Old: "count", New: "limit"}
+var renamedFlags = []renamedFlag{}
+
+func deprecationWarningsEnabled() bool {
+	return os.Getenv("SORTME_NO_DEPRECATION_WARNINGS") == ""
+}
+
+// Регистрирует старые имена. Вызывается после добавления всех команд
+func applyRenames(root *cobra.Command) {
+	for _, rename := range renamedCommands {
+		target, _, err := root.Find(strings.Fields(rename.New))
+		if err != nil || target == root {
+			continue
+		}
+		root.AddCommand(deprecatedAlias(rename, target))
+	}
+
+	for _, rename := range renamedFlags {
+		cmd := root
+		if rename.Command != "" {
+			found, _, err := root.Find(strings.Fields(rename.Command))
+			if err != nil || found == root {
+				continue
+			}
+			cmd = found
+		}
+		addDeprecatedFlag(cmd, rename)
+	}
+}
+
+// Скрытая команда со старым именем, которая выполняет новую с теми же флагами
+func deprecatedAlias(rename renamedCommand, target *cobra.Command) *cobra.Command {
+	alias := &cobra.Command{
+		Use:    rename.Old,
+		Short:  target.Short,
+		Long:   target.Long,
+		Args:   target.Args,
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if deprecationWarningsEnabled() {
+				fmt.Fprint(os.Stderr, T("deprecated.command", rename.Old, target.CommandPath()))
+			}
+			if target.RunE != nil {
+				return target.RunE(cmd, args)
+			}
+			if target.Run != nil {
+				target.Run(cmd, args)
+				return nil
+			}
+			return target.Help()
+		},
+	}
+	// Флаги общие: значения записываются в те же переменные, что и у новой команды
+	alias.Flags().AddFlagSet(target.Flags())
+	return alias
+}
+
+// Старый флаг разделяет значение с новым и скрыт из справки
+func addDeprecatedFlag(cmd *cobra.Command, rename renamedFlag) {
+	flag := cmd.Flags().Lookup(rename.New)
+	if flag == nil || cmd.Flags().Lookup(rename.Old) != nil {
+		return
+	}
+	cmd.Flags().AddFlag(&pflag.Flag{
+		Name:        rename.Old,
+		Usage:       flag.Usage,
+		Value:       flag.Value,
+		DefValue:    flag.DefValue,
+		NoOptDefVal: flag.NoOptDefVal,
+	})
+	if deprecationWarningsEnabled() {
+		// pflag сам печатает сообщение в stderr при использовании флага
+		cmd.Flags().MarkDeprecated(rename.Old, T("deprecated.flag", rename.New))
+	} else {
+		cmd.Flags().MarkHidden(rename.Old)
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// Строк неизмененного кода вокруг каждого изменения, как у diff -u
+const diffContextLines = 3
+
+const (
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorCyan  = "\033[36m"
+	colorBold  = "\033[1m"
+	colorReset = "\033[0m"
+)
+
+func (v *VSCodeExtension) createDiffCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <id1> <id2>",
+		Short: T("diff.short"),
+		Long:  T("diff.long"),
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return v.handleDiff(cmd.Context(), args[0], args[1])
+		},
+	}
+}
+
+func (v *VSCodeExtension) handleDiff(ctx context.Context, fromID, toID string) error {
+	from, err := v.apiClient.GetSubmissionSource(ctx, fromID)
+	if err != nil {
+		return err
+	}
+	to, err := v.apiClient.GetSubmissionSource(ctx, toID)
+	if err != nil {
+		return err
+	}
+
+	diff := unifiedDiff(submissionLabel(from), submissionLabel(to), from.Code, to.Code)
+	v.emitJSON(map[string]interface{}{
+		"from":      from.ID,
+		"to":        to.ID,
+		"identical": diff == "",
+		"diff":      diff,
+	})
+	if diff == "" {
+		fmt.Print(T("diff.same", from.ID, to.ID))
+		return nil
+	}
+
+	colored := term.IsTerminal(int(os.Stdout.Fd())) && os.Getenv("NO_COLOR") == ""
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if !colored || line == "" {
+			fmt.Print(line)
+			continue
+		}
+		color := ""
+		switch {
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			color = colorBold
+		case strings.HasPrefix(line, "@@"):
+			color = colorCyan
+		case strings.HasPrefix(line, "-"):
+			color = colorRed
+		case strings.HasPrefix(line, "+"):
+			color = colorGreen
+		}
+		if color == "" {
+			fmt.Print(line)
+			continue
+		}
+		fmt.Print(color + strings.TrimSuffix(line, "\n") + colorReset + "\n")
+	}
+	return nil
+}
+
+func submissionLabel(source *SubmissionSource) string {
+	label := T("submission.label", source.ID)
+	if source.Language != "" {
+		label += " (" + source.Language + ")"
+	}
+	return label
+}
+
+// Unified diff по строкам. Пустая строка - текст совпадает
+func unifiedDiff(fromName, toName, from, to string) string {
+	a := splitLines(from)
+	b := splitLines(to)
+	ops := diffLines(a, b)
+
+	var out strings.Builder
+	for start := 0; start < len(ops); {
+		// Ищем начало следующего изменения
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		// Hunk тянется, пока между изменениями не больше 2*context общих строк
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+				continue
+			}
+			if i-end >= 2*diffContextLines {
+				break
+			}
+		}
+		first := max(0, start-diffContextLines)
+		last := min(len(ops), end+diffContextLines)
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		fromStart, toStart := ops[first].fromLine, ops[first].toLine
+		fromCount, toCount := 0, 0
+		var body strings.Builder
+		for _, op := range ops[first:last] {
+			if op.kind != '+' {
+				fromCount++
+			}
+			if op.kind != '-' {
+				toCount++
+			}
+			body.WriteString(string(op.kind) + op.text + "\n")
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n%s", hunkRange(fromStart, fromCount), hunkRange(toStart, toCount), body.String())
+		start = last
+	}
+	return out.String()
+}
+
+// Диапазон строк hunk в формате diff -u: "12,5", "12" для одной строки, "11,0" для пустого
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+type diffOp struct {
+	kind     byte // ' ', '-' или '+'
+	text     string
+	fromLine int // номер строки (с 0) в старом тексте перед этой операцией
+	toLine   int
+}
+
+// Наибольшая общая подпоследовательность строк. Решения короткие, O(n*m) хватает
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i], i, j})
+			i++
+			j++
+		case i < n && (j == m || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i], i, j})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j], i, j})
+			j++
+		}
+	}
+	return ops
+}
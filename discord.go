@@ -0,0 +1,314 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Коды операций Discord IPC
+const (
+	discordOpHandshake = 0
+	discordOpFrame     = 1
+	discordOpClose     = 2
+)
+
+// Клиент Discord Rich Presence через локальный IPC сокет
+type DiscordPresence struct {
+	conn     io.ReadWriteCloser
+	clientID string
+}
+
+type DiscordActivity struct {
+	Details    string                 `json:"details,omitempty"`
+	State      string                 `json:"state,omitempty"`
+	Timestamps *DiscordTimestamps     `json:"timestamps,omitempty"`
+	Assets     map[string]string      `json:"assets,omitempty"`
+	Buttons    []DiscordActivityLabel `json:"buttons,omitempty"`
+}
+
+type DiscordTimestamps struct {
+	Start int64 `json:"start,omitempty"`
+	End   int64 `json:"end,omitempty"`
+}
+
+type DiscordActivityLabel struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// Кандидаты на путь к IPC сокету Discord (обычный, snap, flatpak)
+func discordIPCPaths() []string {
+	var paths []string
+	if runtime.GOOS == "windows" {
+		for i := 0; i < 10; i++ {
+			paths = append(paths, fmt.Sprintf(`\\.\pipe\discord-ipc-%d`, i))
+		}
+		return paths
+	}
+
+	var dirs []string
+	for _, env := range []string{"XDG_RUNTIME_DIR", "TMPDIR", "TMP", "TEMP"} {
+		if dir := os.Getenv(env); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	dirs = append(dirs, "/tmp")
+
+	for _, dir := range dirs {
+		for _, sub := range []string{"", "snap.discord", "app/com.discordapp.Discord"} {
+			for i := 0; i < 10; i++ {
+				paths = append(paths, filepath.Join(dir, sub, fmt.Sprintf("discord-ipc-%d", i)))
+			}
+		}
+	}
+	return paths
+}
+
+func dialDiscordIPC(path string) (io.ReadWriteCloser, error) {
+	if runtime.GOOS == "windows" {
+		// Именованный канал открывается как обычный файл
+		return os.OpenFile(path, os.O_RDWR, 0)
+	}
+	return net.DialTimeout("unix", path, 2*time.Second)
+}
+
+func ConnectDiscordPresence(clientID string) (*DiscordPresence, error) {
+	var lastErr error
+	for _, path := range discordIPCPaths() {
+		conn, err := dialDiscordIPC(path)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		presence := &DiscordPresence{conn: conn, clientID: clientID}
+		if err := presence.handshake(); err != nil {
+			conn.Close()
+			lastErr = err
+			continue
+		}
+		return presence, nil
+	}
+
+	if lastErr == nil {
+		lastErr = errors.New(T("discord.no_socket"))
+	}
+	return nil, fmt.Errorf("%s: %w", T("discord.connect_failed"), lastErr)
+}
+
+func (d *DiscordPresence) send(opcode uint32, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, 8)
+	binary.LittleEndian.PutUint32(header[0:4], opcode)
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(data)))
+
+	if _, err := d.conn.Write(append(header, data...)); err != nil {
+		return fmt.Errorf("discord IPC write failed: %w", err)
+	}
+	return nil
+}
+
+func (d *DiscordPresence) receive() (uint32, []byte, error) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(d.conn, header); err != nil {
+		return 0, nil, fmt.Errorf("discord IPC read failed: %w", err)
+	}
+
+	opcode := binary.LittleEndian.Uint32(header[0:4])
+	length := binary.LittleEndian.Uint32(header[4:8])
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(d.conn, data); err != nil {
+		return 0, nil, fmt.Errorf("discord IPC read failed: %w", err)
+	}
+	return opcode, data, nil
+}
+
+func (d *DiscordPresence) handshake() error {
+	if err := d.send(discordOpHandshake, map[string]interface{}{
+		"v":         1,
+		"client_id": d.clientID,
+	}); err != nil {
+		return err
+	}
+
+	opcode, data, err := d.receive()
+	if err != nil {
+		return err
+	}
+	if opcode == discordOpClose {
+		return errors.New(T("discord.rejected", string(data)))
+	}
+	return nil
+}
+
+// Обновляет активность; nil очищает статус
+func (d *DiscordPresence) SetActivity(activity *DiscordActivity) error {
+	err := d.send(discordOpFrame, map[string]interface{}{
+		"cmd": "SET_ACTIVITY",
+		"args": map[string]interface{}{
+			"pid":      os.Getpid(),
+			"activity": activity,
+		},
+		"nonce": strconv.FormatInt(time.Now().UnixNano(), 10),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Discord отвечает на каждую команду, ответ нужно вычитать
+	opcode, data, err := d.receive()
+	if err != nil {
+		return err
+	}
+	if opcode == discordOpClose {
+		return errors.New(T("discord.closed", string(data)))
+	}
+	return nil
+}
+
+func (d *DiscordPresence) Close() error {
+	return d.conn.Close()
+}
+
+func (v *VSCodeExtension) createPresenceCommand() *cobra.Command {
+	var taskID int
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "presence [contest_id]",
+		Short: T("presence.short"),
+		Long:  T("presence.long"),
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			contestID := v.config.CurrentContest
+			if len(args) > 0 {
+				contestID = args[0]
+			}
+			v.handlePresence(cmd.Context(), contestID, taskID, interval)
+		},
+	}
+
+	cmd.Flags().IntVarP(&taskID, "task", "t", 0, T("flag.presence_task"))
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Minute, T("flag.presence_interval"))
+
+	return cmd
+}
+
+func (v *VSCodeExtension) handlePresence(ctx context.Context, contestID string, taskID int, interval time.Duration) {
+	if !v.apiClient.IsAuthenticated() {
+		fmt.Println("❌ " + T("auth.required"))
+		return
+	}
+	if v.config.DiscordClientID == "" {
+		fmt.Println(T("presence.no_client_id"))
+		return
+	}
+	if contestID == "" {
+		fmt.Println("❌ " + T("contest.missing"))
+		progressln(T("presence.contest_hint"))
+		return
+	}
+	if interval < 30*time.Second {
+		interval = 30 * time.Second
+	}
+
+	presence, err := ConnectDiscordPresence(v.config.DiscordClientID)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	defer presence.Close()
+
+	fmt.Println(T("presence.started"))
+
+	startedAt := time.Now().Unix()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		activity, err := v.buildPresenceActivity(ctx, contestID, taskID)
+		if err != nil {
+			progress(T("presence.update_failed", err))
+		} else {
+			activity.Timestamps = &DiscordTimestamps{Start: startedAt}
+			if err := presence.SetActivity(activity); err != nil {
+				progress(T("presence.discord_error", err))
+				return
+			}
+			fmt.Printf("🔄 %s | %s\n", activity.Details, activity.State)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			presence.SetActivity(nil)
+			fmt.Println(T("presence.cleared"))
+			return
+		}
+	}
+}
+
+// Формирует текст активности: задача и прогресс по контесту
+func (v *VSCodeExtension) buildPresenceActivity(ctx context.Context, contestID string, taskID int) (*DiscordActivity, error) {
+	contestInfo, err := v.apiClient.GetContestInfo(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := v.apiClient.GetContestProgress(ctx, contestID, contestInfo.Tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	if taskID == 0 {
+		taskID = progress.LastTaskID
+	}
+
+	details := T("presence.choosing")
+	for i, task := range contestInfo.Tasks {
+		if task.ID == taskID {
+			details = T("presence.solving", taskLetter(i), task.Name)
+			break
+		}
+	}
+
+	state := T("presence.state", contestInfo.Name, progress.SolvedCount(), len(contestInfo.Tasks))
+
+	return &DiscordActivity{
+		Details: truncateDiscordText(details),
+		State:   truncateDiscordText(state),
+		Assets: map[string]string{
+			"large_image": "sortme",
+			"large_text":  "sort-me.org",
+		},
+		Buttons: []DiscordActivityLabel{
+			{Label: T("presence.contest_button"), URL: contestPageURL(contestID)},
+		},
+	}, nil
+}
+
+// Discord ограничивает поля активности 128 символами
+func truncateDiscordText(s string) string {
+	runes := []rune(s)
+	if len(runes) > 128 {
+		return string(runes[:125]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Условие задачи
+type TaskStatement struct {
+	ID          int           `json:"id"`
+	Name        string        `json:"name"`
+	Legend      string        `json:"legend"`
+	Input       string        `json:"input"`
+	Output      string        `json:"output"`
+	Scoring     string        `json:"scoring"`
+	Comment     string        `json:"comment"`
+	Samples     []TaskSample  `json:"samples"`
+	TimeLimit   int           `json:"time_limit"`   // мс
+	MemoryLimit int           `json:"memory_limit"` // МБ
+	SourceLimit int           `json:"source_limit"` // КБ, если сервер его сообщает
+	Subtasks    []TaskSubtask `json:"subtasks"`
+}
+
+type TaskSample struct {
+	In  string `json:"in"`
+	Out string `json:"out"`
+}
+
+type TaskSubtask struct {
+	Points      int    `json:"points"`
+	Description string `json:"description"`
+}
+
+func (a *APIClient) GetTaskStatement(ctx context.Context, contestID, taskID string) (*TaskStatement, error) {
+	if !a.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	// Условие задачи кэшируется: оно не меняется после начала контеста
+	cacheContest := contestID
+	if cacheContest == "" {
+		cacheContest = "_"
+	}
+
+	return cached(a, "statements/"+cacheContest+"/"+taskID, ttlTaskStatement, func() (*TaskStatement, error) {
+		endpoint := fmt.Sprintf("/getTaskById?id=%s", taskID)
+		if contestID != "" {
+			endpoint += "&contestid=" + contestID
+		}
+
+		body, status, err := a.get(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, newAPIError(status, body)
+		}
+
+		var statement TaskStatement
+		if err := json.Unmarshal(body, &statement); err != nil {
+			a.quarantine("statement", endpoint, body, err)
+			return nil, fmt.Errorf("%s: %w", T("statement.parse_error"), err)
+		}
+		return &statement, nil
+	})
+}
+
+// Собирает Markdown документ из частей условия
+func (s *TaskStatement) Markdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", s.Name)
+
+	var limits []string
+	if s.TimeLimit > 0 {
+		limits = append(limits, T("statement.time_limit", float64(s.TimeLimit)/1000))
+	}
+	if s.MemoryLimit > 0 {
+		limits = append(limits, T("statement.memory_limit", s.MemoryLimit))
+	}
+	if len(limits) > 0 {
+		b.WriteString(strings.Join(limits, "  \n") + "\n\n")
+	}
+
+	sections := []struct {
+		title string
+		text  string
+	}{
+		{"", s.Legend},
+		{T("statement.input"), s.Input},
+		{T("statement.output"), s.Output},
+		{T("statement.scoring"), s.Scoring},
+	}
+	for _, section := range sections {
+		text := htmlToMarkdown(section.text)
+		if text == "" {
+			continue
+		}
+		if section.title != "" {
+			fmt.Fprintf(&b, "## %s\n\n", section.title)
+		}
+		b.WriteString(text + "\n\n")
+	}
+
+	if len(s.Subtasks) > 0 {
+		b.WriteString(T("statement.subtasks"))
+		for i, subtask := range s.Subtasks {
+			desc := strings.ReplaceAll(htmlToMarkdown(subtask.Description), "\n", " ")
+			fmt.Fprintf(&b, "| %d | %d | %s |\n", i+1, subtask.Points, desc)
+		}
+		b.WriteString("\n")
+	}
+
+	for i, sample := range s.Samples {
+		b.WriteString(T("statement.sample", i+1))
+		b.WriteString(T("statement.sample_in", strings.TrimRight(sample.In, "\n")))
+		b.WriteString(T("statement.sample_out", strings.TrimRight(sample.Out, "\n")))
+	}
+
+	if comment := htmlToMarkdown(s.Comment); comment != "" {
+		b.WriteString(T("statement.note", comment))
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// Сохраняет примеры как tests/sampleN.in и tests/sampleN.out
+func writeSampleTests(dir string, samples []TaskSample) (int, error) {
+	if len(samples) == 0 {
+		return 0, nil
+	}
+
+	testsDir := filepath.Join(dir, "tests")
+	if err := os.MkdirAll(testsDir, 0755); err != nil {
+		return 0, err
+	}
+
+	for i, sample := range samples {
+		base := filepath.Join(testsDir, fmt.Sprintf("sample%d", i+1))
+		if err := os.WriteFile(base+".in", []byte(ensureTrailingNewline(sample.In)), 0644); err != nil {
+			return i, err
+		}
+		if err := os.WriteFile(base+".out", []byte(ensureTrailingNewline(sample.Out)), 0644); err != nil {
+			return i, err
+		}
+	}
+	return len(samples), nil
+}
+
+var reFencedBlock = regexp.MustCompile("(?s)```[^\n]*\n(.*?)\n?```")
+
+// Примеры из текста условия, когда сервер не прислал их отдельным полем:
+// блоки кода (<pre> или ```) по порядку. Подпись перед блоком ("Входные
+// данные", "Sample output") определяет, вход это или ответ; без подписей
+// блоки идут парами вход-ответ
+func extractStatementSamples(s *TaskStatement) []TaskSample {
+	var parts []string
+	for _, text := range []string{s.Legend, s.Input, s.Output, s.Scoring, s.Comment} {
+		if markdown := htmlToMarkdown(text); markdown != "" {
+			parts = append(parts, markdown)
+		}
+	}
+	text := strings.Join(parts, "\n\n")
+
+	var samples []TaskSample
+	expectInput := true
+	prevEnd := 0
+	for _, loc := range reFencedBlock.FindAllStringSubmatchIndex(text, -1) {
+		block := text[loc[2]:loc[3]]
+		label := sampleCaption(text[prevEnd:loc[0]])
+		prevEnd = loc[1]
+
+		isInput := expectInput
+		switch {
+		case containsAny(label, "выход", "вывод", "ответ", "output", "answer"):
+			isInput = false
+		case containsAny(label, "вход", "ввод", "input"):
+			isInput = true
+		}
+
+		if isInput || len(samples) == 0 || samples[len(samples)-1].Out != "" {
+			if !isInput {
+				// Ответ без входа - не пример
+				continue
+			}
+			samples = append(samples, TaskSample{In: block})
+			expectInput = false
+			continue
+		}
+		samples[len(samples)-1].Out = block
+		expectInput = true
+	}
+
+	// Вход без ответа не даст проверить решение
+	complete := samples[:0]
+	for _, sample := range samples {
+		if sample.Out != "" {
+			complete = append(complete, sample)
+		}
+	}
+	return complete
+}
+
+// Подпись блока - последняя непустая строка перед ним, если она короткая
+func sampleCaption(gap string) string {
+	lines := strings.Split(strings.TrimSpace(gap), "\n")
+	caption := strings.ToLower(strings.TrimSpace(lines[len(lines)-1]))
+	if utf8.RuneCountInString(caption) > 60 {
+		return ""
+	}
+	return caption
+}
+
+func containsAny(s string, substrings ...string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func ensureTrailingNewline(s string) string {
+	if s == "" || strings.HasSuffix(s, "\n") {
+		return s
+	}
+	return s + "\n"
+}
+
+// Скачивает картинки условия в images/ и переписывает ссылки на локальные
+func downloadStatementImages(client *http.Client, markdown, dir string) (string, int) {
+	downloaded := 0
+
+	result := reMarkdownImage.ReplaceAllStringFunc(markdown, func(m string) string {
+		parts := reMarkdownImage.FindStringSubmatch(m)
+		alt, src := parts[1], parts[2]
+
+		imageURL := src
+		if strings.HasPrefix(src, "/") {
+			imageURL = "https://sort-me.org" + src
+		}
+		if !strings.HasPrefix(imageURL, "http") {
+			return m
+		}
+
+		parsed, err := url.Parse(imageURL)
+		if err != nil {
+			return m
+		}
+		name := path.Base(parsed.Path)
+		if name == "" || name == "/" || name == "." {
+			name = fmt.Sprintf("image%d.png", downloaded+1)
+		}
+
+		resp, err := client.Get(imageURL)
+		if err != nil {
+			return m
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return m
+		}
+
+		imagesDir := filepath.Join(dir, "images")
+		if err := os.MkdirAll(imagesDir, 0755); err != nil {
+			return m
+		}
+		file, err := os.Create(filepath.Join(imagesDir, name))
+		if err != nil {
+			return m
+		}
+		defer file.Close()
+		if _, err := io.Copy(file, resp.Body); err != nil {
+			return m
+		}
+
+		downloaded++
+		return fmt.Sprintf("![%s](images/%s)", alt, name)
+	})
+
+	return result, downloaded
+}
+
+type DownloadOptions struct {
+	ScaffoldIO bool   // Сгенерировать заготовку решения с чтением ввода
+	Language   string // Язык заготовки
+}
+
+// Что сохранено при скачивании задачи
+type downloadedTask struct {
+	Statement     *TaskStatement
+	StatementFile string
+	Images        int
+	Samples       int
+	SamplesErr    error
+	ScaffoldFile  string
+	ScaffoldErr   error
+}
+
+// Скачивает условие, примеры и (с --scaffold-io) заготовку решения в outputDir.
+// Ошибки примеров и заготовки не фатальны и возвращаются в результате
+func (v *VSCodeExtension) downloadTask(ctx context.Context, contestID, problemID, outputDir string, opts DownloadOptions) (*downloadedTask, error) {
+	statement, err := v.apiClient.GetTaskStatement(ctx, contestID, problemID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", T("download.statement_failed"), err)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("%s: %w", T("download.mkdir_failed"), err)
+	}
+
+	result := &downloadedTask{Statement: statement}
+
+	markdown := statement.Markdown()
+	if !v.config.LowBandwidth {
+		markdown, result.Images = downloadStatementImages(v.apiClient.transport.externalClient(20*time.Second), markdown, outputDir)
+	}
+
+	result.StatementFile = filepath.Join(outputDir, fmt.Sprintf("problem_%s.md", problemID))
+	if err := os.WriteFile(result.StatementFile, []byte(markdown), 0644); err != nil {
+		return nil, fmt.Errorf("%s: %w", T("download.write_failed"), err)
+	}
+
+	samples := statement.Samples
+	if len(samples) == 0 {
+		samples = extractStatementSamples(statement)
+	}
+	result.Samples, result.SamplesErr = writeSampleTests(outputDir, samples)
+
+	if opts.ScaffoldIO {
+		result.ScaffoldFile, result.ScaffoldErr = writeScaffold(statement, problemID, outputDir, opts.Language)
+	}
+
+	return result, nil
+}
+
+func (v *VSCodeExtension) handleDownload(ctx context.Context, contestID, problemID, outputDir string, opts DownloadOptions) {
+	if !v.apiClient.IsAuthenticated() {
+		fmt.Println("❌ " + T("auth.required"))
+		return
+	}
+
+	progress(T("download.start", problemID, contestID))
+
+	result, err := v.downloadTask(ctx, contestID, problemID, outputDir, opts)
+	if err != nil {
+		fmt.Println("❌ " + T("error.generic", err))
+		return
+	}
+	statement := result.Statement
+
+	if v.config.LowBandwidth {
+		progressln(T("download.low_bandwidth"))
+	} else if result.Images > 0 {
+		progress(T("download.images", result.Images))
+	}
+	if result.SamplesErr != nil {
+		progress(T("download.samples_failed", result.SamplesErr))
+	}
+	if result.ScaffoldErr != nil {
+		progress(T("download.scaffold_failed", result.ScaffoldErr))
+	}
+
+	fmt.Print(T("download.saved", statement.Name))
+	fmt.Print(T("download.statement_file", result.StatementFile))
+	if result.Samples > 0 {
+		fmt.Print(T("download.samples", result.Samples, filepath.Join(outputDir, "tests")))
+	}
+	if result.ScaffoldFile != "" {
+		fmt.Print(T("download.scaffold_file", result.ScaffoldFile))
+	}
+	if statement.TimeLimit > 0 || statement.MemoryLimit > 0 {
+		fmt.Print(T("download.limits", statement.TimeLimit, statement.MemoryLimit))
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+const endpointsStateFile = "endpoints.json"
+
+// Версия API, для которой запомнены рабочие endpoints.
+// При смене версии старые записи просто перестают использоваться
+const apiVersion = "v1"
+
+// Запомненные рабочие endpoints: версия API -> назначение -> шаблон
+type EndpointMemory map[string]map[string]string
+
+func loadEndpointMemory() EndpointMemory {
+	memory := make(EndpointMemory)
+	if err := loadState(endpointsStateFile, &memory); err != nil || memory == nil {
+		return make(EndpointMemory)
+	}
+	return memory
+}
+
+func rememberedEndpoint(purpose string) string {
+	return loadEndpointMemory()[apiVersion][purpose]
+}
+
+func rememberEndpoint(purpose, template string) {
+	if rememberedEndpoint(purpose) == template {
+		return
+	}
+	memory := make(EndpointMemory)
+	updateState(endpointsStateFile, &memory, func() error {
+		if memory[apiVersion] == nil {
+			memory[apiVersion] = make(map[string]string)
+		}
+		memory[apiVersion][purpose] = template
+		return nil
+	})
+}
+
+// Ставит запомненный endpoint первым, остальные кандидаты сохраняют порядок
+func orderEndpoints(purpose string, candidates []string) []string {
+	remembered := rememberedEndpoint(purpose)
+	if remembered == "" {
+		return candidates
+	}
+
+	ordered := []string{remembered}
+	for _, candidate := range candidates {
+		if candidate != remembered {
+			ordered = append(ordered, candidate)
+		}
+	}
+	return ordered
+}
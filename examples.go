@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const recentContestStateFile = "recent_contest.json"
+
+// Последний просмотренный контест - источник реальных ID для примеров в справке
+type RecentContest struct {
+	ContestID string    `json:"contest_id"`
+	Name      string    `json:"name"`
+	Tasks     []Task    `json:"tasks"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func rememberContest(contestID string, info *ContestInfo) {
+	if info == nil || len(info.Tasks) == 0 {
+		return
+	}
+	saveState(recentContestStateFile, RecentContest{
+		ContestID: contestID,
+		Name:      info.Name,
+		Tasks:     info.Tasks,
+		UpdatedAt: time.Now(),
+	})
+}
+
+// Шаблоны примеров: {contest} и {task} заменяются реальными ID пользователя
+var exampleTemplates = map[string][]string{
+	"submit": {
+		"sortme submit b.cpp -c {contest} -p {task}",
+	},
+	"problems": {
+		"sortme problems {contest}",
+	},
+	"list": {
+		"sortme list {contest}",
+		"sortme list {contest} --limit 5",
+	},
+	"download": {
+		"sortme download {contest} {task}",
+	},
+	"standings": {
+		"sortme standings {contest}",
+	},
+	"tag": {
+		"sortme tag {task} dp,graphs",
+	},
+	"presence": {
+		"sortme presence {contest} --task {task}",
+	},
+	"wait": {
+		"sortme wait {contest} && sortme problems {contest}",
+	},
+}
+
+// Значения по умолчанию, если пользователь еще ничего не открывал
+const (
+	exampleContestID = "456"
+	exampleTaskID    = "2472"
+)
+
+// Подставляет текущий контест и реальную задачу из последнего просмотренного контеста
+func (v *VSCodeExtension) applyDynamicExamples(root *cobra.Command) {
+	contestID := v.config.CurrentContest
+	taskID := exampleTaskID
+
+	var recent RecentContest
+	if err := loadState(recentContestStateFile, &recent); err == nil && recent.ContestID != "" {
+		if contestID == "" {
+			contestID = recent.ContestID
+		}
+		if contestID == recent.ContestID && len(recent.Tasks) > 0 {
+			taskID = strconv.Itoa(recent.Tasks[0].ID)
+		}
+	}
+	if contestID == "" {
+		contestID = exampleContestID
+	}
+
+	replacer := strings.NewReplacer("{contest}", contestID, "{task}", taskID)
+	for _, cmd := range root.Commands() {
+		templates, ok := exampleTemplates[cmd.Name()]
+		if !ok {
+			continue
+		}
+		var lines []string
+		for _, template := range templates {
+			lines = append(lines, "  "+replacer.Replace(template))
+		}
+		cmd.Example = strings.Join(lines, "\n")
+	}
+}
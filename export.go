@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// Выгрузка отправок для таблиц (list --format csv|tsv): журнал отправок
+// для отчетов по курсу. Колонки постоянные, чтобы таблицы можно было склеивать
+const (
+	listFormatTable = "table"
+	listFormatCSV   = "csv"
+	listFormatTSV   = "tsv"
+)
+
+var submissionExportColumns = []string{
+	"id", "contest_id", "contest_name", "problem_id", "problem_name",
+	"language", "verdict", "verdict_code", "points", "test", "submit_time",
+}
+
+func validListFormat(format string) error {
+	switch format {
+	case listFormatTable, listFormatCSV, listFormatTSV:
+		return nil
+	}
+	return errors.New(T("export.unknown_format", format))
+}
+
+func writeSubmissionsExport(w io.Writer, submissions []Submission, format string) error {
+	writer := csv.NewWriter(w)
+	if format == listFormatTSV {
+		writer.Comma = '\t'
+	}
+	if err := writer.Write(submissionExportColumns); err != nil {
+		return err
+	}
+	for _, sub := range submissions {
+		problemID := sub.ProblemID
+		if problemID == 0 {
+			problemID = sub.TaskID
+		}
+		problemName := sub.ProblemName
+		if problemName == "" {
+			problemName = sub.TaskName
+		}
+		verdict := sub.ShownVerdictText
+		if verdict == "" {
+			verdict = getShortStatusText(sub.ShownVerdict)
+		}
+		record := []string{
+			strconv.Itoa(sub.ID),
+			sub.ContestID,
+			sub.ContestName,
+			strconv.Itoa(problemID),
+			problemName,
+			sub.Language,
+			verdict,
+			strconv.Itoa(sub.ShownVerdict),
+			strconv.Itoa(sub.TotalPoints),
+			strconv.Itoa(sub.ShownTest),
+			sub.SubmitTime,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
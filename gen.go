@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Входные данные от генератора: genN.in, где N - seed. Тот же seed дает
+// тот же тест, поэтому повторный запуск просто перезаписывает файлы
+type GenOptions struct {
+	Seed     int
+	Count    int
+	TestsDir string
+	Timeout  time.Duration
+}
+
+func (v *VSCodeExtension) createGenCommand() *cobra.Command {
+	var opts GenOptions
+
+	cmd := &cobra.Command{
+		Use:   "gen [generator]",
+		Short: T("gen.short"),
+		Long:  T("gen.long"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			explicit := ""
+			if len(args) > 0 {
+				explicit = args[0]
+			}
+			generator, problemDir, err := resolveGenerator(explicit, ".")
+			if err != nil {
+				return err
+			}
+			if opts.TestsDir == "" {
+				opts.TestsDir = filepath.Join(problemDir, "tests")
+			}
+			return v.handleGen(cmd.Context(), generator, opts)
+		},
+	}
+
+	cmd.Flags().IntVar(&opts.Seed, "seed", 1, T("flag.gen_seed"))
+	cmd.Flags().IntVarP(&opts.Count, "count", "n", 10, T("flag.gen_count"))
+	cmd.Flags().StringVarP(&opts.TestsDir, "tests", "t", "", T("flag.gen_tests"))
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 5*time.Second, T("flag.stress_timeout"))
+	return cmd
+}
+
+// Генератор из аргумента или из generator в .sortme.yaml каталога задачи.
+// Возвращает путь к генератору и каталог задачи
+func resolveGenerator(explicit, dir string) (string, string, error) {
+	if explicit != "" {
+		return explicit, dir, nil
+	}
+	binding, _, err := findWorkspace(dir)
+	if err != nil {
+		return "", "", err
+	}
+	if binding == nil || binding.Generator == "" {
+		return "", "", errors.New(T("gen.generator_missing", workspaceFileName))
+	}
+	generator := binding.Generator
+	if !filepath.IsAbs(generator) {
+		generator = filepath.Join(binding.dir, generator)
+	}
+	return generator, binding.dir, nil
+}
+
+func (v *VSCodeExtension) handleGen(ctx context.Context, generatorFile string, opts GenOptions) error {
+	if opts.Count <= 0 {
+		return errors.New(T("gen.bad_count"))
+	}
+	generator, err := v.prepareStressProgram(T("stress.role_generator"), generatorFile, "")
+	if err != nil {
+		return err
+	}
+	defer generator.Cleanup()
+
+	if err := os.MkdirAll(opts.TestsDir, 0755); err != nil {
+		return err
+	}
+
+	for i := 0; i < opts.Count; i++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		seed := strconv.Itoa(opts.Seed + i)
+		result := generator.Run(nil, opts.Timeout, seed)
+		if problem := runProblem(result, opts.Timeout); problem != "" {
+			printStderrTail(result.Stderr)
+			return errors.New(T("stress.generator_failed", seed, problem))
+		}
+		filename := filepath.Join(opts.TestsDir, "gen"+seed+".in")
+		if err := os.WriteFile(filename, result.Output, 0644); err != nil {
+			return err
+		}
+	}
+
+	last := opts.Seed + opts.Count - 1
+	fmt.Print(T("gen.created", opts.Count, opts.TestsDir, opts.Seed, last))
+	progressln(T("gen.hint"))
+	return nil
+}
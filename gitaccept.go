@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Принятые решения в git (git_on_accept в конфиге): commit - коммит с кодом,
+// который получил AC, tag - еще и тег ac/<контест>-<задача>-<отправка>.
+// Коммитится код на момент отправки, даже если файл с тех пор изменился:
+// коммит собирается во временном индексе, а рабочий каталог и то, что
+// пользователь уже добавил в индекс, не трогаются
+
+const (
+	gitAcceptCommit = "commit"
+	gitAcceptTag    = "tag"
+)
+
+// Файл решения и его содержимое на момент отправки
+type acceptedSnapshot struct {
+	dir  string
+	name string
+	code []byte
+}
+
+// Запоминает файл перед отправкой, если git_on_accept включен
+func (v *VSCodeExtension) snapshotForGit(filename string) *acceptedSnapshot {
+	switch v.config.GitOnAccept {
+	case "":
+		return nil
+	case gitAcceptCommit, gitAcceptTag:
+	default:
+		progress(T("git.unknown_mode", v.config.GitOnAccept))
+		return nil
+	}
+	if filename == stdinSource {
+		return nil
+	}
+	code, err := os.ReadFile(filename)
+	if err != nil {
+		return nil
+	}
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return nil
+	}
+	return &acceptedSnapshot{dir: dir, name: filepath.Base(filename), code: code}
+}
+
+// Коммит (и тег) принятого решения. Ошибки git только печатаются:
+// вердикт уже получен, а репозиторий можно поправить руками
+func (v *VSCodeExtension) commitAccepted(ctx context.Context, snapshot *acceptedSnapshot, contestID, problemID, submissionID string) {
+	if snapshot == nil {
+		return
+	}
+	message := fmt.Sprintf("Accepted: contest %s, problem %s, submission %s", contestID, problemID, submissionID)
+	commit, err := snapshot.commit(ctx, message)
+	if err != nil {
+		progress(T("git.commit_failed", err))
+		return
+	}
+	if commit == "" {
+		progressln(T("git.already_committed"))
+	} else {
+		progress(T("git.committed", shortHash(commit)))
+	}
+	if v.config.GitOnAccept != gitAcceptTag {
+		return
+	}
+
+	tag := fmt.Sprintf("ac/%s-%s-%s", contestID, problemID, submissionID)
+	if _, err := snapshot.git(ctx, nil, "tag", "-a", tag, "-m", message, "HEAD"); err != nil {
+		progress(T("git.tag_failed", err))
+		return
+	}
+	progress(T("git.tagged", tag))
+}
+
+// Коммитит код снимка поверх HEAD. Пустой результат - такой код уже в HEAD
+func (s *acceptedSnapshot) commit(ctx context.Context, message string) (string, error) {
+	prefix, err := s.git(ctx, nil, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", errors.New(T("git.not_repo", s.dir))
+	}
+	path := prefix + s.name
+	head, _ := s.git(ctx, nil, "rev-parse", "--verify", "--quiet", "HEAD")
+
+	blob, err := s.gitInput(ctx, s.code, "hash-object", "-w", "--path="+path, "--stdin")
+	if err != nil {
+		return "", err
+	}
+	mode := "100644"
+	if entry, _ := s.git(ctx, nil, "ls-files", "--stage", "--", s.name); strings.HasPrefix(entry, "100755") {
+		mode = "100755"
+	}
+
+	// Временный индекс из HEAD с новой версией одного файла
+	tmp, err := os.MkdirTemp("", "sortme-git-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(tmp, "index")}
+	if head != "" {
+		_, err = s.git(ctx, env, "read-tree", head)
+	} else {
+		_, err = s.git(ctx, env, "read-tree", "--empty")
+	}
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.git(ctx, env, "update-index", "--add", "--cacheinfo", mode+","+blob+","+path); err != nil {
+		return "", err
+	}
+	tree, err := s.git(ctx, env, "write-tree")
+	if err != nil {
+		return "", err
+	}
+	if head != "" {
+		if headTree, _ := s.git(ctx, nil, "rev-parse", head+"^{tree}"); headTree == tree {
+			return "", nil
+		}
+	}
+
+	args := []string{"commit-tree", tree, "-m", message}
+	if head != "" {
+		args = append(args, "-p", head)
+	}
+	commit, err := s.git(ctx, nil, args...)
+	if err != nil {
+		return "", err
+	}
+	// Файл не был добавлен в индекс отдельно: после коммита индекс должен
+	// совпадать с ним, иначе git status покажет обратное изменение
+	staged := head != "" && s.gitRun(ctx, "diff", "--cached", "--quiet", head, "--", s.name) != nil
+	if _, err := s.git(ctx, nil, "update-ref", "-m", "sortme: "+message, "HEAD", commit, head); err != nil {
+		return "", err
+	}
+	if !staged {
+		s.git(ctx, nil, "update-index", "--add", "--cacheinfo", mode+","+blob+","+path)
+	}
+	return commit, nil
+}
+
+func (s *acceptedSnapshot) command(ctx context.Context, env []string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", s.dir}, args...)...)
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd
+}
+
+func (s *acceptedSnapshot) git(ctx context.Context, env []string, args ...string) (string, error) {
+	output, err := s.command(ctx, env, args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (s *acceptedSnapshot) gitInput(ctx context.Context, input []byte, args ...string) (string, error) {
+	cmd := s.command(ctx, nil, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v", args[0], err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Только код завершения: diff --quiet отвечает им, а не выводом
+func (s *acceptedSnapshot) gitRun(ctx context.Context, args ...string) error {
+	return s.command(ctx, nil, args...).Run()
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
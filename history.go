@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Все попытки по одной задаче по порядку: вердикты, рост баллов и паузы
+// между отправками
+type HistoryAttempt struct {
+	Number     int        `json:"number"`
+	Submission Submission `json:"submission"`
+	SubmitTime *time.Time `json:"submit_time,omitempty"`
+	SincePrev  string     `json:"since_prev,omitempty"`
+	Points     int        `json:"points"`
+	Best       int        `json:"best"` // лучший балл на момент этой попытки
+	Improved   bool       `json:"improved"`
+}
+
+func (v *VSCodeExtension) createHistoryCommand() *cobra.Command {
+	var contestID, problemID string
+	var local bool
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: T("history.short"),
+		Long:  T("history.long"),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if contestID == "" {
+				contestID = v.config.CurrentContest
+			}
+			if contestID == "" {
+				return errors.New(T("history.contest_missing"))
+			}
+			taskID, err := strconv.Atoi(problemID)
+			if err != nil {
+				return errors.New(T("history.problem_missing"))
+			}
+			if !local && !v.apiClient.IsAuthenticated() {
+				return ErrNotAuthenticated
+			}
+			return v.handleHistory(cmd.Context(), contestID, taskID, local)
+		},
+	}
+
+	cmd.Flags().StringVarP(&contestID, "contest", "c", "", T("flag.contest_default"))
+	cmd.Flags().StringVarP(&problemID, "problem", "p", "", T("flag.problem_required"))
+	cmd.Flags().BoolVar(&local, "local", false, T("flag.history_local"))
+	return cmd
+}
+
+func (v *VSCodeExtension) taskSubmissions(ctx context.Context, contestID string, taskID int, local bool) ([]Submission, error) {
+	loadLocal := func() ([]Submission, error) {
+		all, err := loadLocalSubmissions(contestID, Page{})
+		if err != nil {
+			return nil, err
+		}
+		var submissions []Submission
+		for _, sub := range all {
+			if sub.ProblemID == taskID {
+				submissions = append(submissions, sub)
+			}
+		}
+		return submissions, nil
+	}
+	if local {
+		return loadLocal()
+	}
+
+	endpoint := fmt.Sprintf("/getMySubmissionsByTask?id=%d&contestid=%s", taskID, contestID)
+	submissions, err := v.apiClient.tryGetSubmissions(ctx, endpoint, 0)
+	if err != nil {
+		if isFatalAPIError(err) {
+			return nil, err
+		}
+		// Без сети показываем то, что есть в локальной базе
+		if cached, localErr := loadLocal(); localErr == nil && len(cached) > 0 {
+			progress(T("history.offline", err))
+			return cached, nil
+		}
+		return nil, err
+	}
+	return submissions, nil
+}
+
+func (v *VSCodeExtension) handleHistory(ctx context.Context, contestID string, taskID int, local bool) error {
+	submissions, err := v.taskSubmissions(ctx, contestID, taskID, local)
+	if err != nil {
+		return fmt.Errorf("%s: %w", T("report.submissions_failed"), err)
+	}
+
+	attempts := buildHistory(submissions)
+	if v.jsonMode() {
+		if attempts == nil {
+			attempts = []HistoryAttempt{}
+		}
+		v.emitJSON(map[string]interface{}{
+			"contest_id": contestID,
+			"problem_id": taskID,
+			"attempts":   attempts,
+		})
+	}
+
+	if len(attempts) == 0 {
+		fmt.Print(T("history.empty", taskID, contestID))
+		return nil
+	}
+
+	fmt.Print(T("history.header", getTaskDisplayName(attempts[0].Submission), contestID, len(attempts)))
+	fmt.Println("┌─────┬──────────┬──────────────────┬──────────┬──────────┬────────────┐")
+	fmt.Printf("│ %3s │ %-8s │ %-16s │ %-8s │ %-8s │ %-10s │\n", "#", "ID", T("history.col_time"), T("history.col_gap"), T("history.col_verdict"), T("history.col_points"))
+	fmt.Println("├─────┼──────────┼──────────────────┼──────────┼──────────┼────────────┤")
+	for _, attempt := range attempts {
+		sub := attempt.Submission
+		submitted := "—"
+		if attempt.SubmitTime != nil {
+			submitted = attempt.SubmitTime.Local().Format("02.01 15:04:05")
+		}
+		since := attempt.SincePrev
+		if since == "" {
+			since = "—"
+		}
+		points := fmt.Sprintf("%d", attempt.Points)
+		if attempt.Improved && attempt.Number > 1 {
+			points += " ↑"
+		}
+		fmt.Printf("│ %3d │ %-8d │ %-16s │ %s │ %s %-6s │ %s │\n",
+			attempt.Number, sub.ID, submitted, padRunes(since, 8),
+			getShortStatusEmoji(sub.ShownVerdict), getShortStatusText(sub.ShownVerdict), padRunes(points, 10))
+	}
+	fmt.Println("└─────┴──────────┴──────────────────┴──────────┴──────────┴────────────┘")
+
+	last := attempts[len(attempts)-1]
+	fmt.Print(T("history.best", last.Best))
+	for _, attempt := range attempts {
+		if attempt.Submission.ShownVerdict != 1 {
+			continue
+		}
+		fmt.Print(T("history.first_ac", attempt.Number))
+		if first := attempts[0].SubmitTime; first != nil && attempt.SubmitTime != nil && attempt.Number > 1 {
+			fmt.Print(T("history.first_ac_after", formatWaitRemaining(attempt.SubmitTime.Sub(*first))))
+		}
+		fmt.Println()
+		break
+	}
+	return nil
+}
+
+// Сортирует попытки по времени отправки (без времени - по ID) и считает прогресс
+func buildHistory(submissions []Submission) []HistoryAttempt {
+	type timed struct {
+		sub Submission
+		at  time.Time
+		ok  bool
+	}
+	items := make([]timed, len(submissions))
+	for i, sub := range submissions {
+		value := sub.SubmitTime
+		if value == "" {
+			value = sub.Time
+		}
+		at, ok := parseSubmitTime(value)
+		items[i] = timed{sub, at, ok}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].ok && items[j].ok && !items[i].at.Equal(items[j].at) {
+			return items[i].at.Before(items[j].at)
+		}
+		return items[i].sub.ID < items[j].sub.ID
+	})
+
+	var attempts []HistoryAttempt
+	best := 0
+	var prev *time.Time
+	for i, item := range items {
+		points := item.sub.TotalPoints
+		if points == 0 && item.sub.ShownVerdict == 1 {
+			points = 100
+		}
+		attempt := HistoryAttempt{
+			Number:     i + 1,
+			Submission: item.sub,
+			Points:     points,
+			Improved:   points > best,
+		}
+		if points > best {
+			best = points
+		}
+		attempt.Best = best
+		if item.ok {
+			at := item.at
+			attempt.SubmitTime = &at
+			if prev != nil {
+				attempt.SincePrev = "+" + formatWaitRemaining(at.Sub(*prev))
+			}
+			prev = &at
+		}
+		attempts = append(attempts, attempt)
+	}
+	return attempts
+}
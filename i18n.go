@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	langRU = "ru"
+	langEN = "en"
+)
+
+// Язык вывода: задается в конфиге (language: en) или флагом --lang
+var currentLang = langRU
+
+type message struct {
+	ru string
+	en string
+}
+
+func (m message) text(lang string) string {
+	if lang == langEN && m.en != "" {
+		return m.en
+	}
+	return m.ru
+}
+
+func setLanguage(lang string) error {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	switch lang {
+	case "":
+		return nil
+	case langRU, langEN:
+		currentLang = lang
+		return nil
+	default:
+		return errors.New(T("lang.unsupported", lang))
+	}
+}
+
+// Возвращает сообщение по ID на текущем языке. Если переданы аргументы,
+// текст используется как форматная строка
+func T(id string, args ...interface{}) string {
+	msg, ok := messages[id]
+	if !ok {
+		return id
+	}
+	text := msg.text(currentLang)
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// Справка команд собирается до разбора флагов, поэтому --lang ищем в аргументах заранее
+func langFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if value, ok := strings.CutPrefix(arg, "--lang="); ok {
+			return value
+		}
+		if arg == "--lang" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// Ошибка с текстом из каталога для переменных уровня пакета: они создаются
+// до разбора --lang, поэтому текст берется при выводе. errors.Is работает,
+// так как значения сравнимы
+type localizedError string
+
+func (e localizedError) Error() string {
+	return T(string(e))
+}
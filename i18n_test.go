@@ -0,0 +1,48 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+)
+
+var formatVerb = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z%]`)
+
+// Каждое сообщение должно быть на всех поддерживаемых языках и принимать
+// столько же аргументов: иначе перевод выводит %!v(MISSING) или лишнее
+func TestMessagesHaveAllLanguages(t *testing.T) {
+	for id, msg := range messages {
+		texts := map[string]string{langRU: msg.ru, langEN: msg.en}
+		for lang, text := range texts {
+			if text == "" {
+				t.Errorf("%s: нет текста для %s", id, lang)
+			}
+		}
+		if ru, en := countVerbs(msg.ru), countVerbs(msg.en); ru != en {
+			t.Errorf("%s: аргументов в ru %d, в en %d", id, ru, en)
+		}
+	}
+}
+
+func countVerbs(text string) int {
+	n := 0
+	for _, verb := range formatVerb.FindAllString(text, -1) {
+		if verb != "%%" {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLocalizedErrorFollowsLanguage(t *testing.T) {
+	defer setLanguage(currentLang)
+	if err := setLanguage(langEN); err != nil {
+		t.Fatal(err)
+	}
+	if got := ErrNotFound.Error(); got != "not found" {
+		t.Errorf("ErrNotFound = %q", got)
+	}
+	setLanguage(langRU)
+	if got := ErrNotFound.Error(); got != "не найдено" {
+		t.Errorf("ErrNotFound = %q", got)
+	}
+}
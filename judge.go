@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// Виртуальный судья: сводит результаты локальных тестов в вердикт в том же
+// виде, что печатает sortme status. Итог - вердикт первого непройденного теста.
+// Если тест называется <N>-имя (2-big.in), он относится к подзадаче N, и при
+// известных из условия баллах подзадача засчитывается, только если пройдены
+// все ее тесты
+var reSubtaskTest = regexp.MustCompile(`^(\d+)-`)
+
+// Результат одного теста в терминах проверяющей системы
+type localVerdict struct {
+	Test   string
+	Status string // accepted, wrong_answer, time_limit_exceeded, runtime_error или "" (не проверен)
+}
+
+type localSubtask struct {
+	Number      int
+	Points      int
+	Description string
+	Tests       int
+	Failed      *localVerdict // первый непройденный тест подзадачи
+}
+
+func subtaskOfTest(name string) int {
+	match := reSubtaskTest.FindStringSubmatch(name)
+	if match == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(match[1])
+	return n
+}
+
+// Условие задачи, к которой привязан файл через .sortme.yaml, или nil
+func (v *VSCodeExtension) workspaceStatement(ctx context.Context, filename string) *TaskStatement {
+	binding, _, err := findWorkspace(filepath.Dir(filename))
+	if err != nil || binding == nil || binding.ContestID == "" {
+		return nil
+	}
+	problemID := binding.ProblemFor(filename)
+	if problemID == "" {
+		return nil
+	}
+	statement, err := v.apiClient.GetTaskStatement(ctx, binding.ContestID, problemID)
+	if err != nil {
+		return nil
+	}
+	return statement
+}
+
+// Баллы подзадач из условия задачи, к которой привязан файл
+func (v *VSCodeExtension) statementSubtasks(ctx context.Context, filename string) []TaskSubtask {
+	if statement := v.workspaceStatement(ctx, filename); statement != nil {
+		return statement.Subtasks
+	}
+	return nil
+}
+
+// Печатает итоговый вердикт локального прогона
+func (v *VSCodeExtension) printLocalJudgement(ctx context.Context, filename string, verdicts []localVerdict) {
+	var first *localVerdict
+	for i := range verdicts {
+		if verdicts[i].Status != "accepted" && verdicts[i].Status != "" {
+			first = &verdicts[i]
+			break
+		}
+	}
+
+	// Подзадачи: только если тесты размечены и условие знает их баллы
+	var subtasks []*localSubtask
+	byNumber := make(map[int]*localSubtask)
+	for i := range verdicts {
+		n := subtaskOfTest(verdicts[i].Test)
+		if n == 0 {
+			continue
+		}
+		if subtasks == nil {
+			for j, st := range v.statementSubtasks(ctx, filename) {
+				subtask := &localSubtask{Number: j + 1, Points: st.Points, Description: st.Description}
+				subtasks = append(subtasks, subtask)
+				byNumber[subtask.Number] = subtask
+			}
+			if subtasks == nil {
+				break
+			}
+		}
+		subtask, ok := byNumber[n]
+		if !ok {
+			continue
+		}
+		subtask.Tests++
+		if subtask.Failed == nil && verdicts[i].Status != "accepted" {
+			subtask.Failed = &verdicts[i]
+		}
+	}
+
+	status := "accepted"
+	if first != nil {
+		status = first.Status
+	}
+	score, checked := 0, 0
+	for _, subtask := range subtasks {
+		if subtask.Tests == 0 {
+			continue
+		}
+		checked++
+		if subtask.Failed == nil {
+			score += subtask.Points
+		}
+	}
+	if first != nil && score > 0 {
+		status = "partial"
+	}
+
+	fmt.Print(T("judge.verdict", getStatusEmoji(status)))
+	if first != nil {
+		fmt.Print(T("status.result", T("judge.test", first.Test)))
+	}
+	if checked == 0 {
+		return
+	}
+
+	fmt.Print(T("status.score", score))
+	fmt.Println(T("judge.subtasks"))
+	for _, subtask := range subtasks {
+		line := fmt.Sprintf("      %d. ", subtask.Number)
+		switch {
+		case subtask.Tests == 0:
+			line += T("judge.subtask_no_tests", subtask.Points)
+		case subtask.Failed != nil:
+			line += T("judge.subtask_failed", getStatusEmoji(subtask.Failed.Status), subtask.Points, subtask.Failed.Test)
+		default:
+			line += fmt.Sprintf("%s %d/%d", getStatusEmoji("accepted"), subtask.Points, subtask.Points)
+		}
+		if subtask.Description != "" {
+			line += " - " + subtask.Description
+		}
+		fmt.Println(line)
+	}
+	if checked < len(subtasks) {
+		fmt.Println(T("judge.partial_hint"))
+	}
+}
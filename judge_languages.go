@@ -0,0 +1,252 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Язык, который принимает тестирующая система: ID уходит в submit как есть,
+// Language - имя из нашей таблицы (c++, python...) для сопоставления с файлом
+type JudgeLanguage struct {
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+const (
+	judgeLanguagesPurpose = "judge_languages"
+	// Запоминается вместо шаблона, если сервер не отдает список языков
+	judgeLanguagesUnsupported = "-"
+)
+
+var judgeLanguagesEndpoints = []string{
+	"/getLanguages",
+	"/getCompilers",
+	"/languages",
+	"/compilers",
+}
+
+// Список языков сервера. ErrNotFound - сервер такого списка не отдает
+func (a *APIClient) GetJudgeLanguages(ctx context.Context) ([]JudgeLanguage, error) {
+	if rememberedEndpoint(judgeLanguagesPurpose) == judgeLanguagesUnsupported {
+		return nil, ErrNotFound
+	}
+
+	return cached(a, "languages", ttlJudgeLanguages, func() ([]JudgeLanguage, error) {
+		var lastErr error
+		missing := 0
+		for _, endpoint := range orderEndpoints(judgeLanguagesPurpose, judgeLanguagesEndpoints) {
+			body, status, err := a.get(ctx, endpoint)
+			if isFatalAPIError(err) {
+				return nil, err
+			}
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if status == http.StatusNotFound {
+				missing++
+				continue
+			}
+			if status != http.StatusOK {
+				lastErr = newAPIError(status, body)
+				continue
+			}
+			languages, err := parseJudgeLanguages(body)
+			if err != nil {
+				a.quarantine(judgeLanguagesPurpose, endpoint, body, err)
+				lastErr = err
+				continue
+			}
+			rememberEndpoint(judgeLanguagesPurpose, endpoint)
+			return languages, nil
+		}
+		// Все варианты ответили 404 - больше не спрашиваем
+		if missing == len(judgeLanguagesEndpoints) {
+			rememberEndpoint(judgeLanguagesPurpose, judgeLanguagesUnsupported)
+		}
+		return nil, cmp.Or(lastErr, ErrNotFound)
+	})
+}
+
+// Поддерживаются массив объектов или строк, обертки languages/compilers/data
+// и объект вида {"c++20": "GNU C++ 20"}
+func parseJudgeLanguages(body []byte) ([]JudgeLanguage, error) {
+	var wrapper map[string]json.RawMessage
+	if json.Unmarshal(body, &wrapper) == nil {
+		for _, key := range []string{"languages", "compilers", "langs", "data"} {
+			if inner, ok := wrapper[key]; ok {
+				body = inner
+				break
+			}
+		}
+	}
+
+	var languages []JudgeLanguage
+	var items []json.RawMessage
+	if err := json.Unmarshal(body, &items); err != nil {
+		var byID map[string]string
+		if json.Unmarshal(body, &byID) != nil || len(byID) == 0 {
+			return nil, errors.New(T("languages.unknown_format"))
+		}
+		for id, name := range byID {
+			languages = append(languages, JudgeLanguage{ID: id, Name: name})
+		}
+		// У объекта нет порядка, у массива оставляем порядок сервера
+		sort.Slice(languages, func(i, j int) bool {
+			return naturalLess(languages[i].ID, languages[j].ID)
+		})
+	}
+
+	for _, item := range items {
+		var language JudgeLanguage
+		if json.Unmarshal(item, &language.ID) != nil {
+			var raw map[string]json.RawMessage
+			if json.Unmarshal(item, &raw) != nil {
+				continue
+			}
+			for _, key := range []string{"id", "lang", "key", "code"} {
+				if json.Unmarshal(raw[key], &language.ID) == nil && language.ID != "" {
+					break
+				}
+				if n, ok := jsonInt(raw[key]); ok {
+					language.ID = strconv.Itoa(n)
+					break
+				}
+			}
+			for _, key := range []string{"name", "title", "compiler", "description"} {
+				if json.Unmarshal(raw[key], &language.Name) == nil && language.Name != "" {
+					break
+				}
+			}
+		}
+		if language.ID != "" {
+			languages = append(languages, language)
+		}
+	}
+
+	for i := range languages {
+		// Числовой ID ничего не говорит о языке, тогда смотрим на название
+		if spec, ok := languageFromServerName(languages[i].ID); ok {
+			languages[i].Language = spec.Name
+		} else if spec, ok := languageFromServerName(languages[i].Name); ok {
+			languages[i].Language = spec.Name
+		}
+	}
+	if len(languages) == 0 {
+		return nil, errors.New(T("languages.empty"))
+	}
+	return languages, nil
+}
+
+// ID языка для submit. Принимается точный ID сервера ("c++20") или имя из
+// нашей таблицы ("cpp", "c++"): тогда берется languages.<язык>.judge из
+// конфига или первый подходящий язык сервера. Если сервер не отдает список,
+// язык проверяется только по нашей таблице
+func (v *VSCodeExtension) judgeLanguage(ctx context.Context, language string) (string, error) {
+	canonical, known := canonicalLanguage(language, v.config.Languages)
+	judge, err := v.apiClient.GetJudgeLanguages(ctx)
+	if err != nil || len(judge) == 0 {
+		if !known {
+			return "", fmt.Errorf("%s", T("submit.language_unsupported", language))
+		}
+		if id := v.config.Languages[canonical].Judge; id != "" {
+			return id, nil
+		}
+		return canonical, nil
+	}
+
+	for _, j := range judge {
+		if strings.EqualFold(j.ID, language) {
+			return j.ID, nil
+		}
+	}
+	if known {
+		if id := v.config.Languages[canonical].Judge; id != "" {
+			for _, j := range judge {
+				if strings.EqualFold(j.ID, id) {
+					return j.ID, nil
+				}
+			}
+			return "", errors.New(T("languages.unknown_judge", canonical, id))
+		}
+		for _, j := range judge {
+			if j.Language == canonical {
+				return j.ID, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%s", T("submit.language_unsupported", language))
+}
+
+// Подсказка со списком языков: серверный, если он есть
+func (v *VSCodeExtension) availableLanguages(ctx context.Context) string {
+	judge, err := v.apiClient.GetJudgeLanguages(ctx)
+	if err != nil || len(judge) == 0 {
+		return languageNames(v.config.Languages)
+	}
+	ids := make([]string, len(judge))
+	for i, j := range judge {
+		ids[i] = j.ID
+	}
+	return strings.Join(ids, ", ")
+}
+
+func (v *VSCodeExtension) createLanguagesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "languages",
+		Short: T("languages.short"),
+		Long:  T("languages.long"),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return v.handleLanguages(cmd.Context())
+		},
+	}
+}
+
+func (v *VSCodeExtension) handleLanguages(ctx context.Context) error {
+	judge, err := v.apiClient.GetJudgeLanguages(ctx)
+	if isFatalAPIError(err) {
+		return err
+	}
+
+	if v.jsonMode() {
+		if judge == nil {
+			judge = []JudgeLanguage{}
+		}
+		v.emitJSON(map[string]interface{}{"from_server": err == nil, "languages": judge})
+	}
+
+	if err != nil {
+		progress(T("languages.fallback", err))
+		for _, spec := range languageTable(v.config.Languages) {
+			fmt.Printf("  %-12s %s\n", spec.Name, strings.Join(spec.Extensions, " "))
+		}
+		return nil
+	}
+
+	fmt.Print(T("languages.header", len(judge)))
+	fmt.Println("┌──────────────────┬──────────────────────────────┬────────────┐")
+	fmt.Printf("│ %s │ %s │ %s │\n", padRunes("ID (--language)", 16), padRunes(T("languages.col_name"), 28), padRunes(T("languages.col_language"), 10))
+	fmt.Println("├──────────────────┼──────────────────────────────┼────────────┤")
+	for _, j := range judge {
+		files := "—"
+		if j.Language != "" {
+			files = j.Language
+		}
+		fmt.Printf("│ %s │ %s │ %s │\n", padRunes(j.ID, 16), padRunes(j.Name, 28), padRunes(files, 10))
+	}
+	fmt.Println("└──────────────────┴──────────────────────────────┴────────────┘")
+	progressln(T("languages.default_hint"))
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Keepalive для WebSocket отправки. Пока решение стоит в длинной очереди,
+// сервер может молчать дольше срока чтения, хотя связь в порядке. Ping раз
+// в wsPingInterval: каждый ответный pong продлевает срок чтения, так что
+// таймаут означает потерю связи, а не просто тишину. Без сообщений о
+// проверке ждем не дольше queueMaxWait
+
+const (
+	wsPingInterval = 15 * time.Second
+	// Два пропущенных pong подряд - связи нет
+	wsPongWait  = 2 * wsPingInterval
+	wsWriteWait = 10 * time.Second
+)
+
+type wsKeepAlive struct {
+	conn *websocket.Conn
+	// Меняются только в читающей горутине: обработчик pong вызывается из ReadMessage
+	lastMessage     time.Time
+	messageDeadline time.Time
+	stop            chan struct{}
+}
+
+// Включает ping и ставит срок чтения первого сообщения
+func startKeepAlive(conn *websocket.Conn, firstTimeout time.Duration) *wsKeepAlive {
+	k := &wsKeepAlive{conn: conn, stop: make(chan struct{})}
+	k.message(firstTimeout)
+	conn.SetPongHandler(func(string) error {
+		k.pong()
+		return nil
+	})
+	go k.ping()
+	return k
+}
+
+// Пришло сообщение: следующее ждем не дольше timeout
+func (k *wsKeepAlive) message(timeout time.Duration) {
+	k.lastMessage = time.Now()
+	k.messageDeadline = k.lastMessage.Add(timeout)
+	k.conn.SetReadDeadline(k.messageDeadline)
+}
+
+// Связь жива: срок чтения не раньше следующего pong
+func (k *wsKeepAlive) pong() {
+	deadline := time.Now().Add(wsPongWait)
+	if limit := k.lastMessage.Add(queueMaxWait); deadline.After(limit) {
+		deadline = limit
+	}
+	if deadline.After(k.messageDeadline) {
+		k.conn.SetReadDeadline(deadline)
+	}
+}
+
+func (k *wsKeepAlive) ping() {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-k.stop:
+			return
+		case <-ticker.C:
+			// WriteControl можно вызывать параллельно с чтением
+			if err := k.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (k *wsKeepAlive) Stop() {
+	close(k.stop)
+}
+
+// Сервер закрыл соединение из-за перезапуска или перегрузки: это не ошибка
+// отправки, можно переподключиться
+func isServerRestartClose(err error) bool {
+	return websocket.IsCloseError(err, websocket.CloseGoingAway, websocket.CloseServiceRestart, websocket.CloseTryAgainLater)
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Session token хранится в системном хранилище паролей (Keychain, Credential
+// Manager, Secret Service), в config.yaml остается пустое поле. Если хранилища
+// нет (сервер без графической сессии), токен пишется в конфиг как раньше.
+// token_storage в конфиге: auto (по умолчанию), keyring или plaintext
+const (
+	tokenStorageAuto      = "auto"
+	tokenStorageKeyring   = "keyring"
+	tokenStoragePlaintext = "plaintext"
+
+	keyringService = "sortme_plugin"
+)
+
+// Токены, которые сейчас лежат в хранилище, по имени профиля. Повторно
+// записываем только изменившиеся, чтобы не дергать хранилище на каждый SaveConfig
+var keyringTokens = struct {
+	sync.Mutex
+	values      map[string]string
+	unavailable bool
+}{values: make(map[string]string)}
+
+func (c *Config) useKeyring() bool {
+	return c.TokenStorage != tokenStoragePlaintext
+}
+
+// Дописывает в учетные данные токены из хранилища. true - конфиг надо
+// перезаписать: токены открытым текстом переносятся в хранилище, а при
+// token_storage: plaintext - обратно из хранилища в конфиг
+func (c *Config) loadKeyringTokens() bool {
+	migrate := false
+	load := func(profile string, creds *ProfileCredentials) {
+		if creds.SessionToken != "" {
+			// Доступность хранилища проверяем до того, как переписывать конфиг
+			if c.useKeyring() {
+				keyringGet(profile)
+				migrate = migrate || keyringAvailable()
+			}
+			return
+		}
+		if token, ok := keyringGet(profile); ok && token != "" {
+			creds.SessionToken = token
+			migrate = migrate || !c.useKeyring()
+		}
+	}
+
+	creds := c.credentials()
+	load(defaultProfileName, &creds)
+	c.setCredentials(creds)
+	for name, creds := range c.Profiles {
+		load(name, &creds)
+		c.Profiles[name] = creds
+	}
+	return migrate
+}
+
+// Учетные данные для записи в config.yaml: токены, сохраненные в хранилище, убираются
+func (c *Config) stripKeyringTokens(creds ProfileCredentials, profiles map[string]ProfileCredentials) (ProfileCredentials, map[string]ProfileCredentials) {
+	store := func(profile string, creds ProfileCredentials) ProfileCredentials {
+		if !c.useKeyring() {
+			keyringForget(profile)
+			return creds
+		}
+		if err := keyringPut(profile, creds.SessionToken); err != nil {
+			if c.TokenStorage == tokenStorageKeyring {
+				fmt.Fprint(os.Stderr, T("keyring.fallback", err))
+			}
+			return creds
+		}
+		creds.SessionToken = ""
+		return creds
+	}
+
+	creds = store(defaultProfileName, creds)
+	stripped := make(map[string]ProfileCredentials, len(profiles))
+	for name, profile := range profiles {
+		stripped[name] = store(name, profile)
+	}
+	return creds, stripped
+}
+
+func keyringAvailable() bool {
+	keyringTokens.Lock()
+	defer keyringTokens.Unlock()
+	return !keyringTokens.unavailable
+}
+
+func keyringGet(profile string) (string, bool) {
+	keyringTokens.Lock()
+	defer keyringTokens.Unlock()
+	if keyringTokens.unavailable {
+		return "", false
+	}
+
+	token, err := keyring.Get(keyringService, profile)
+	if errors.Is(err, keyring.ErrNotFound) {
+		keyringTokens.values[profile] = ""
+		return "", false
+	}
+	if err != nil {
+		keyringTokens.unavailable = true
+		return "", false
+	}
+	keyringTokens.values[profile] = token
+	return token, true
+}
+
+// Сохраняет токен профиля, пустой удаляет
+func keyringPut(profile, token string) error {
+	keyringTokens.Lock()
+	defer keyringTokens.Unlock()
+	if keyringTokens.unavailable {
+		return errors.New(T("keyring.unavailable"))
+	}
+	if stored, ok := keyringTokens.values[profile]; ok && stored == token {
+		return nil
+	}
+
+	var err error
+	if token == "" {
+		err = keyring.Delete(keyringService, profile)
+		if errors.Is(err, keyring.ErrNotFound) {
+			err = nil
+		}
+	} else {
+		err = keyring.Set(keyringService, profile, token)
+	}
+	if err != nil {
+		keyringTokens.unavailable = true
+		return err
+	}
+	keyringTokens.values[profile] = token
+	return nil
+}
+
+// Удаляет токен профиля из хранилища (sortme profile rm)
+func keyringForget(profile string) {
+	keyringTokens.Lock()
+	defer keyringTokens.Unlock()
+	if keyringTokens.unavailable || keyringTokens.values[profile] == "" {
+		return
+	}
+	keyring.Delete(keyringService, profile)
+	keyringTokens.values[profile] = ""
+}
+
+// Токен бота уведомлений у каждого профиля свой и хранится так же, как
+// session token, но под отдельным именем: его можно сменить, не трогая вход
+// на sort-me.org. Без хранилища (или при token_storage: plaintext) он лежит
+// в secrets.json
+func notifyTokenAccount(profile string) string {
+	return "telegram-bot:" + profile
+}
+
+func notifyTokenSecret(profile string) string {
+	if profile == defaultProfileName {
+		return secretNotifyTelegramToken
+	}
+	return secretNotifyTelegramToken + "." + profile
+}
+
+// Токен бота активного профиля. Токен из secrets.json, сохраненный без
+// хранилища или старой версией, переносится в хранилище, как только оно доступно
+func (c *Config) notifyToken() (string, error) {
+	profile := c.profileName()
+	if c.useKeyring() {
+		if token, ok := keyringGet(notifyTokenAccount(profile)); ok && token != "" {
+			return token, nil
+		}
+	}
+	token, err := getSecret(notifyTokenSecret(profile))
+	if err != nil || token == "" {
+		return "", err
+	}
+	if c.useKeyring() && keyringAvailable() {
+		c.storeNotifyToken(profile, token)
+	}
+	return token, nil
+}
+
+// Сохраняет токен бота активного профиля, пустой удаляет
+func (c *Config) setNotifyToken(token string) error {
+	return c.storeNotifyToken(c.profileName(), token)
+}
+
+func (c *Config) storeNotifyToken(profile, token string) error {
+	account := notifyTokenAccount(profile)
+	if c.useKeyring() {
+		err := keyringPut(account, token)
+		if err == nil {
+			return setSecret(notifyTokenSecret(profile), "")
+		}
+		if c.TokenStorage == tokenStorageKeyring {
+			fmt.Fprint(os.Stderr, T("notify.keyring_unavailable", err))
+		}
+	} else {
+		keyringForget(account)
+	}
+	return setSecret(notifyTokenSecret(profile), token)
+}
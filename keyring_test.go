@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Хранилище в памяти и пустой каталог конфига: секреты не трогают настоящие
+func useTestKeyring(t *testing.T, err error) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	if err != nil {
+		keyring.MockInitWithError(err)
+	} else {
+		keyring.MockInit()
+	}
+	keyringTokens.Lock()
+	keyringTokens.values = make(map[string]string)
+	keyringTokens.unavailable = false
+	keyringTokens.Unlock()
+}
+
+func TestNotifyTokenPerProfile(t *testing.T) {
+	useTestKeyring(t, nil)
+	config := &Config{TokenStorage: tokenStorageAuto}
+
+	if err := config.setNotifyToken("1:default"); err != nil {
+		t.Fatal(err)
+	}
+	config.profile = "work"
+	if err := config.setNotifyToken("2:work"); err != nil {
+		t.Fatal(err)
+	}
+
+	if token, _ := config.notifyToken(); token != "2:work" {
+		t.Errorf("work: %q", token)
+	}
+	config.profile = ""
+	if token, _ := config.notifyToken(); token != "1:default" {
+		t.Errorf("default: %q", token)
+	}
+	if secret, _ := getSecret(secretNotifyTelegramToken); secret != "" {
+		t.Errorf("токен попал в secrets.json: %q", secret)
+	}
+}
+
+func TestNotifyTokenMovesFromFileToKeyring(t *testing.T) {
+	useTestKeyring(t, nil)
+	if err := setSecret(secretNotifyTelegramToken, "1:old"); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{TokenStorage: tokenStorageAuto}
+	if token, _ := config.notifyToken(); token != "1:old" {
+		t.Fatalf("token = %q", token)
+	}
+	if secret, _ := getSecret(secretNotifyTelegramToken); secret != "" {
+		t.Errorf("токен остался в secrets.json: %q", secret)
+	}
+	if stored, err := keyring.Get(keyringService, notifyTokenAccount(defaultProfileName)); err != nil || stored != "1:old" {
+		t.Errorf("в хранилище %q, %v", stored, err)
+	}
+}
+
+func TestNotifyTokenFallsBackToFile(t *testing.T) {
+	useTestKeyring(t, errors.New("no secret service"))
+	config := &Config{TokenStorage: tokenStorageAuto}
+
+	if err := config.setNotifyToken("1:file"); err != nil {
+		t.Fatal(err)
+	}
+	if secret, _ := getSecret(secretNotifyTelegramToken); secret != "1:file" {
+		t.Errorf("secrets.json: %q", secret)
+	}
+	if token, _ := config.notifyToken(); token != "1:file" {
+		t.Errorf("token = %q", token)
+	}
+}
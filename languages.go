@@ -0,0 +1,126 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Язык в терминах submit: имя, расширения файлов (первое - основное,
+// с ним сохраняется скачанный код) и другие написания, в том числе
+// названия компиляторов в ответах сервера
+type languageSpec struct {
+	Name       string
+	Extensions []string
+	Aliases    []string
+}
+
+// Общая таблица для определения языка по файлу и проверки --language.
+// Расширения и новые языки добавляются в конфиге: languages.<язык>.extensions
+var knownLanguages = []languageSpec{
+	{Name: "c++", Extensions: []string{".cpp", ".cc", ".cxx", ".c++"}, Aliases: []string{"cpp", "g++", "gnu c++", "clang++"}},
+	{Name: "c", Extensions: []string{".c"}, Aliases: []string{"gcc", "gnu c"}},
+	{Name: "python", Extensions: []string{".py"}, Aliases: []string{"python3", "pypy", "pypy3"}},
+	{Name: "java", Extensions: []string{".java"}},
+	{Name: "kotlin", Extensions: []string{".kt"}},
+	{Name: "scala", Extensions: []string{".scala"}},
+	{Name: "haskell", Extensions: []string{".hs"}, Aliases: []string{"ghc"}},
+	{Name: "pascal", Extensions: []string{".pas", ".pp", ".dpr"}, Aliases: []string{"free pascal", "freepascal", "fpc", "delphi"}},
+	{Name: "csharp", Extensions: []string{".cs"}, Aliases: []string{"c#", "mono", "dotnet"}},
+	{Name: "go", Extensions: []string{".go"}, Aliases: []string{"golang"}},
+	{Name: "rust", Extensions: []string{".rs"}},
+	{Name: "javascript", Extensions: []string{".js", ".mjs"}, Aliases: []string{"js", "node", "nodejs"}},
+	{Name: "typescript", Extensions: []string{".ts"}, Aliases: []string{"ts"}},
+	{Name: "php", Extensions: []string{".php"}},
+	{Name: "ruby", Extensions: []string{".rb"}},
+}
+
+// Таблица с поправками из конфига: расширения из languages.<язык>.extensions
+// переходят к этому языку, языки не из списка добавляются в конец
+func languageTable(languages map[string]LanguageConfig) []languageSpec {
+	table := slices.Clone(knownLanguages)
+	names := make([]string, 0, len(languages))
+	for name := range languages {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		var extensions []string
+		for _, ext := range languages[name].Extensions {
+			ext = strings.ToLower(strings.TrimSpace(ext))
+			if ext == "" {
+				continue
+			}
+			if !strings.HasPrefix(ext, ".") {
+				ext = "." + ext
+			}
+			extensions = append(extensions, ext)
+		}
+		// Расширение принадлежит одному языку: убираем его у остальных
+		for i := range table {
+			if table[i].Name != name && len(extensions) > 0 {
+				table[i].Extensions = slices.DeleteFunc(slices.Clone(table[i].Extensions), func(ext string) bool {
+					return slices.Contains(extensions, ext)
+				})
+			}
+		}
+		i := slices.IndexFunc(table, func(spec languageSpec) bool { return spec.Name == name })
+		if i < 0 {
+			table = append(table, languageSpec{Name: name, Extensions: extensions})
+			continue
+		}
+		table[i].Extensions = append(slices.Clone(table[i].Extensions), extensions...)
+	}
+	return table
+}
+
+// Язык по расширению файла или "unknown"
+func detectLanguage(filename string, languages map[string]LanguageConfig) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == "" {
+		return "unknown"
+	}
+	for _, spec := range languageTable(languages) {
+		if slices.Contains(spec.Extensions, ext) {
+			return spec.Name
+		}
+	}
+	return "unknown"
+}
+
+// Имя языка для submit по имени или другому написанию: "cpp" -> "c++"
+func canonicalLanguage(name string, languages map[string]LanguageConfig) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, spec := range languageTable(languages) {
+		if spec.Name == name || slices.Contains(spec.Aliases, name) {
+			return spec.Name, true
+		}
+	}
+	return "", false
+}
+
+// Язык по строке сервера вроде "GNU C++ 17" или "Python 3.11": самое
+// длинное совпавшее начало, чтобы "javascript" не стал "java"
+func languageFromServerName(name string) (languageSpec, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	var best languageSpec
+	bestLen := 0
+	for _, spec := range knownLanguages {
+		for _, candidate := range append([]string{spec.Name}, spec.Aliases...) {
+			if len(candidate) > bestLen && strings.HasPrefix(name, candidate) {
+				best, bestLen = spec, len(candidate)
+			}
+		}
+	}
+	return best, bestLen > 0
+}
+
+// Имена языков через запятую для подсказок
+func languageNames(languages map[string]LanguageConfig) string {
+	var names []string
+	for _, spec := range languageTable(languages) {
+		names = append(names, spec.Name)
+	}
+	return strings.Join(names, ", ")
+}
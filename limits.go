@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var reValueWithUnit = regexp.MustCompile(`([0-9]+(?:[.,][0-9]+)?)\s*([a-zA-Zа-яА-Я]*)`)
+
+func parseValueWithUnit(s string) (float64, string, bool) {
+	match := reValueWithUnit.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return 0, "", false
+	}
+	value, err := strconv.ParseFloat(strings.Replace(match[1], ",", ".", 1), 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return value, strings.ToLower(match[2]), true
+}
+
+// Время работы в миллисекундах: "1940 ms", "1.94s", "1,94 с", "1940"
+func parseTimeMillis(s string) (float64, bool) {
+	value, unit, ok := parseValueWithUnit(s)
+	if !ok {
+		return 0, false
+	}
+	switch unit {
+	case "ms", "мс":
+		return value, true
+	case "s", "sec", "с", "сек":
+		return value * 1000, true
+	case "":
+		// Без единиц: дробное число - секунды, целое - миллисекунды
+		if strings.ContainsAny(s, ".,") {
+			return value * 1000, true
+		}
+		return value, true
+	}
+	return 0, false
+}
+
+// Память в мегабайтах: "256 MB", "12345 KB", "12.5 МБ"
+func parseMemoryMB(s string) (float64, bool) {
+	value, unit, ok := parseValueWithUnit(s)
+	if !ok {
+		return 0, false
+	}
+	switch unit {
+	case "kb", "kib", "кб", "k":
+		return value / 1024, true
+	case "mb", "mib", "мб", "m":
+		return value, true
+	case "gb", "gib", "гб":
+		return value * 1024, true
+	case "b", "б":
+		return value / 1024 / 1024, true
+	case "":
+		// Без единиц тестирующие системы обычно пишут килобайты, мегабайты - только маленькие числа
+		if value > 4096 {
+			return value / 1024, true
+		}
+		return value, true
+	}
+	return 0, false
+}
+
+func isAcceptedStatus(status string) bool {
+	switch strings.ToLower(status) {
+	case "accepted", "ac", "ok":
+		return true
+	}
+	return false
+}
+
+// Предупреждения для принятого решения, которое почти упирается в ограничения задачи
+func limitWarnings(status *SubmissionStatus, statement *TaskStatement, margin float64) []string {
+	if status == nil || statement == nil || !isAcceptedStatus(status.Status) {
+		return nil
+	}
+	if margin <= 0 || margin >= 1 {
+		margin = 0.9
+	}
+
+	var warnings []string
+	if timeMs, ok := parseTimeMillis(status.Time); ok && statement.TimeLimit > 0 {
+		limit := float64(statement.TimeLimit)
+		if timeMs >= limit*margin {
+			warnings = append(warnings, T("limits.time_close",
+				timeMs/1000, limit/1000, int(timeMs*100/limit)))
+		}
+	}
+	if memoryMB, ok := parseMemoryMB(status.Memory); ok && statement.MemoryLimit > 0 {
+		limit := float64(statement.MemoryLimit)
+		if memoryMB >= limit*margin {
+			warnings = append(warnings, T("limits.memory_close",
+				memoryMB, statement.MemoryLimit, int(memoryMB*100/limit)))
+		}
+	}
+	return warnings
+}
+
+// Сравнивает время и память принятой отправки с ограничениями задачи
+func (v *VSCodeExtension) warnNearLimits(ctx context.Context, status *SubmissionStatus, contestID, problemID string) {
+	if problemID == "" || !isAcceptedStatus(status.Status) {
+		return
+	}
+	if status.Time == "" && status.Memory == "" {
+		return
+	}
+
+	statement, err := v.apiClient.GetTaskStatement(ctx, contestID, problemID)
+	if err != nil {
+		return
+	}
+
+	for _, warning := range limitWarnings(status, statement, v.config.LimitWarning) {
+		progressln(warning)
+	}
+}
+
+// Предупреждения для локального запуска: время и пиковая память против
+// ограничений задачи. Локальная машина обычно быстрее тестирующей, поэтому
+// предупреждаем заранее, с той же долей лимита, что и для AC
+func localLimitWarnings(result *RunResult, statement *TaskStatement, margin float64) []string {
+	if result == nil || statement == nil {
+		return nil
+	}
+	if margin <= 0 || margin >= 1 {
+		margin = 0.9
+	}
+
+	var warnings []string
+	if statement.TimeLimit > 0 && !result.TimedOut {
+		timeMs := float64(result.Duration.Milliseconds())
+		limit := float64(statement.TimeLimit)
+		switch {
+		case timeMs > limit:
+			warnings = append(warnings, T("limits.local_time_over", int(timeMs), statement.TimeLimit))
+		case timeMs >= limit*margin:
+			warnings = append(warnings, T("limits.local_time_close", int(timeMs), statement.TimeLimit, int(timeMs*100/limit)))
+		}
+	}
+	if statement.MemoryLimit > 0 && result.PeakRSS > 0 {
+		memoryMB := float64(result.PeakRSS) / (1 << 20)
+		limit := float64(statement.MemoryLimit)
+		switch {
+		case memoryMB > limit:
+			warnings = append(warnings, T("limits.local_memory_over", memoryMB, statement.MemoryLimit))
+		case memoryMB >= limit*margin:
+			warnings = append(warnings, T("limits.local_memory_close", memoryMB, statement.MemoryLimit, int(memoryMB*100/limit)))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Блокировка занята другим процессом
+var errLockBusy = errors.New("lock is held by another process")
+
+// Рекомендательная (advisory) файловая блокировка между процессами sortme
+type fileLock struct {
+	file *os.File
+	path string
+}
+
+// Сколько ждем освобождения блокировки по умолчанию
+const defaultLockTimeout = 30 * time.Second
+
+// Что делает процесс, держащий блокировку, для сообщения об ожидании
+var lockActivities = map[string]string{
+	"config": "lock.activity.config",
+	"state":  "lock.activity.state",
+	"sync":   "lock.activity.sync",
+}
+
+// Берет блокировку в каталоге конфига (config.lock, sync.lock и т.п.)
+func acquireLock(name string) (*fileLock, error) {
+	activity := T("lock.activity.other")
+	if id, ok := lockActivities[name]; ok {
+		activity = T(id)
+	}
+	return acquireLockAt(filepath.Join(getConfigPath(), name+".lock"), activity, defaultLockTimeout)
+}
+
+func acquireLockAt(path, activity string, timeout time.Duration) (*fileLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	announced := false
+
+	for {
+		err := tryLockFile(file)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errLockBusy) {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+
+		if !announced {
+			fmt.Fprint(os.Stderr, T("lock.waiting", describeLockOwner(path), activity))
+			announced = true
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, errors.New(T("lock.busy", describeLockOwner(path), activity))
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	// Записываем владельца, чтобы другие процессы могли показать понятное сообщение
+	file.Truncate(0)
+	file.WriteAt([]byte(fmt.Sprintf("%d %s", os.Getpid(), strings.Join(os.Args[1:], " "))), 0)
+
+	return &fileLock{file: file, path: path}, nil
+}
+
+func (l *fileLock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	l.file.Truncate(0)
+	err := unlockFile(l.file)
+	l.file.Close()
+	l.file = nil
+	return err
+}
+
+// Описание процесса, держащего блокировку: " (PID 1234: sync)"
+func describeLockOwner(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) == 0 {
+		return ""
+	}
+
+	parts := strings.SplitN(strings.TrimSpace(string(data)), " ", 2)
+	pid, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return ""
+	}
+	if len(parts) > 1 && parts[1] != "" {
+		return fmt.Sprintf(" (PID %d: %s)", pid, parts[1])
+	}
+	return fmt.Sprintf(" (PID %d)", pid)
+}
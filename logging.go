@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Отладочный журнал в stderr. По умолчанию молчит, -v - запросы к API
+// с кодами ответов и подключения WebSocket, -vv - еще тела ответов и
+// кадры WebSocket. --log-format json - по записи JSON на строку
+var logger = slog.New(slog.DiscardHandler)
+
+// Длиннее обрезаем: ответ со списком отправок бывает на мегабайты
+const logBodyLimit = 2000
+
+func setupLogging(verbosity int, format string) error {
+	if format != "" && format != "text" && format != "json" {
+		return errors.New(T("logging.bad_format", format))
+	}
+	if verbosity <= 0 {
+		logger = slog.New(slog.DiscardHandler)
+		return nil
+	}
+	options := &slog.HandlerOptions{Level: slog.LevelInfo}
+	if verbosity > 1 {
+		options.Level = slog.LevelDebug
+	}
+
+	if format == "json" {
+		logger = slog.New(slog.NewJSONHandler(os.Stderr, options))
+		return nil
+	}
+	// Человеку хватит времени без даты
+	options.ReplaceAttr = func(groups []string, attr slog.Attr) slog.Attr {
+		if attr.Key == slog.TimeKey && len(groups) == 0 {
+			return slog.String(slog.TimeKey, attr.Value.Time().Format("15:04:05.000"))
+		}
+		return attr
+	}
+	logger = slog.New(slog.NewTextHandler(os.Stderr, options))
+	return nil
+}
+
+// Тело ответа для журнала, без гигантских массивов целиком
+func logBody(body []byte) string {
+	if len(body) > logBodyLimit {
+		return fmt.Sprintf("%s... (%d байт)", body[:logBodyLimit], len(body))
+	}
+	return string(body)
+}
+
+// Токен в адресе WebSocket в журнал не попадает
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	if token := query.Get("token"); token != "" {
+		query.Set("token", maskToken(token))
+		redacted := *u
+		redacted.RawQuery = query.Encode()
+		return redacted.String()
+	}
+	return u.String()
+}
+
+func logRequest(method string, u *url.URL, status int, started time.Time, err error) {
+	attrs := []any{"method", method, "url", redactURL(u), "duration", time.Since(started).Round(time.Millisecond).String()}
+	if err != nil {
+		logger.Info("запрос не удался", append(attrs, "error", err)...)
+		return
+	}
+	logger.Info("запрос", append(attrs, "status", status)...)
+}
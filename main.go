@@ -1,16 +1,44 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
+// Код выхода после Ctrl+C, как у shell
+const exitInterrupted = 130
+
 func main() {
 	extension := NewVSCodeExtension()
 	rootCmd := extension.CreateRootCommand()
 
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Printf("Ошибка: %v\n", err)
-		os.Exit(1)
+	// Ctrl+C отменяет контекст команды: запросы и WebSocket закрываются,
+	// циклы ожидания завершаются. Повторный Ctrl+C завершает процесс сразу
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
+	extension.apiClient.FlushUsage()
+	if ctx.Err() != nil {
+		// Команды с долгим ожиданием (watch, presence) сами сообщают об остановке
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, T("main.interrupted"))
+		}
+		os.Exit(exitInterrupted)
+	}
+	if err != nil && !isExitCodeError(err) {
+		fmt.Fprint(os.Stderr, T("error.prefix", err))
+	}
+	extension.offerReauth()
+	if extension.finishOutput(err) {
+		os.Exit(exitCodeOf(err))
 	}
 }
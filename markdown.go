@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Простейший конвертер HTML условий в Markdown.
+// Условия на sort-me.org приходят либо уже в Markdown, либо в несложном HTML,
+// поэтому полноценный парсер не нужен.
+
+var (
+	reHTMLTag     = regexp.MustCompile(`(?s)<[^>]+>`)
+	reHTMLPre     = regexp.MustCompile(`(?is)<pre[^>]*>(.*?)</pre>`)
+	reHTMLCode    = regexp.MustCompile(`(?is)<code[^>]*>(.*?)</code>`)
+	reHTMLBold    = regexp.MustCompile(`(?is)<(b|strong)[^>]*>(.*?)</(b|strong)>`)
+	reHTMLItalic  = regexp.MustCompile(`(?is)<(i|em)[^>]*>(.*?)</(i|em)>`)
+	reHTMLHeading = regexp.MustCompile(`(?is)<h([1-6])[^>]*>(.*?)</h[1-6]>`)
+	reHTMLLink    = regexp.MustCompile(`(?is)<a[^>]*href="([^"]*)"[^>]*>(.*?)</a>`)
+	reHTMLImage   = regexp.MustCompile(`(?is)<img[^>]*src="([^"]*)"[^>]*>`)
+	reHTMLItem    = regexp.MustCompile(`(?is)<li[^>]*>(.*?)</li>`)
+	reHTMLBreak   = regexp.MustCompile(`(?i)<br\s*/?>`)
+	reHTMLPara    = regexp.MustCompile(`(?i)</?(p|div|ul|ol|table|tr)[^>]*>`)
+	reHTMLCell    = regexp.MustCompile(`(?i)</?(td|th)[^>]*>`)
+	reManyNewline = regexp.MustCompile(`\n{3,}`)
+
+	// Ссылки на картинки в Markdown: ![alt](url)
+	reMarkdownImage = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+)
+
+func looksLikeHTML(s string) bool {
+	return reHTMLTag.MatchString(s) && (strings.Contains(s, "</") || strings.Contains(s, "<br") || strings.Contains(s, "<img"))
+}
+
+func htmlToMarkdown(s string) string {
+	if !looksLikeHTML(s) {
+		return strings.TrimSpace(s)
+	}
+
+	// Блоки кода сохраняем до удаления остальных тегов
+	var blocks []string
+	s = reHTMLPre.ReplaceAllStringFunc(s, func(m string) string {
+		inner := reHTMLPre.FindStringSubmatch(m)[1]
+		inner = html.UnescapeString(reHTMLTag.ReplaceAllString(inner, ""))
+		blocks = append(blocks, "\n```\n"+strings.Trim(inner, "\n")+"\n```\n")
+		return fmt.Sprintf("\x00PRE%d\x00", len(blocks)-1)
+	})
+
+	s = reHTMLHeading.ReplaceAllStringFunc(s, func(m string) string {
+		parts := reHTMLHeading.FindStringSubmatch(m)
+		level := int(parts[1][0] - '0')
+		return "\n" + strings.Repeat("#", level) + " " + strings.TrimSpace(parts[2]) + "\n"
+	})
+	s = reHTMLImage.ReplaceAllString(s, "![]($1)")
+	s = reHTMLLink.ReplaceAllString(s, "[$2]($1)")
+	s = reHTMLBold.ReplaceAllString(s, "**$2**")
+	s = reHTMLItalic.ReplaceAllString(s, "*$2*")
+	s = reHTMLCode.ReplaceAllString(s, "`$1`")
+	s = reHTMLItem.ReplaceAllString(s, "\n- $1")
+	s = reHTMLBreak.ReplaceAllString(s, "\n")
+	s = reHTMLPara.ReplaceAllString(s, "\n\n")
+	s = reHTMLCell.ReplaceAllString(s, " | ")
+	s = reHTMLTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	for i, block := range blocks {
+		s = strings.Replace(s, fmt.Sprintf("\x00PRE%d\x00", i), block, 1)
+	}
+
+	// Убираем лишние пробелы в конце строк и пустые строки подряд
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	s = strings.Join(lines, "\n")
+	s = reManyNewline.ReplaceAllString(s, "\n\n")
+
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Размер решения: проверка до отправки и удаление комментариев (--strip-comments),
+// когда большие шаблоны не влезают в ограничение сервера
+
+// КБ кода, если ни сервер, ни конфиг не говорят иного
+const defaultSourceSizeLimit = 256
+
+// Ограничение на размер кода в байтах: из условия задачи, если сервер его
+// сообщает, иначе source_size_limit из конфига (КБ, 0 - не проверять)
+func (v *VSCodeExtension) sourceSizeLimit(ctx context.Context, contestID, problemID string) int {
+	if statement, err := v.apiClient.GetTaskStatement(ctx, contestID, problemID); err == nil && statement.SourceLimit > 0 {
+		return statement.SourceLimit * 1024
+	}
+	return v.config.SourceSizeLimit * 1024
+}
+
+// Ошибка, если код больше ограничения, и предупреждение, если он близко к нему
+func (v *VSCodeExtension) checkSourceSize(ctx context.Context, sourceCode, contestID, problemID string) error {
+	limit := v.sourceSizeLimit(ctx, contestID, problemID)
+	if limit <= 0 {
+		return nil
+	}
+	size := len(sourceCode)
+	if size > limit {
+		return fmt.Errorf("%s", T("submit.size_over", formatTestSize(int64(size)), formatTestSize(int64(limit))))
+	}
+	margin := v.config.LimitWarning
+	if margin <= 0 || margin >= 1 {
+		margin = 0.9
+	}
+	if float64(size) >= float64(limit)*margin {
+		fmt.Print(T("submit.size_close", formatTestSize(int64(size)), formatTestSize(int64(limit)), size*100/limit))
+	}
+	return nil
+}
+
+// Синтаксис комментариев языка
+type commentSyntax struct {
+	line        []string // начало однострочного комментария
+	blockStart  string
+	blockEnd    string
+	nested      bool   // вложенные блочные комментарии (Rust, Kotlin, Scala, Haskell)
+	keepBlock   string // блоки, которые выглядят как комментарий, но нужны компилятору: {$mode} в Pascal
+	rawQuote    byte   // строки без экранирования: ` в Go и JS
+	tripleQuote bool   // """...""" и '''...''' в Python
+	charQuote   bool   // одиночная кавычка - символ, а не строка
+}
+
+var commentSyntaxes = map[string]commentSyntax{
+	"c++":        {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", charQuote: true},
+	"c":          {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", charQuote: true},
+	"java":       {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", charQuote: true},
+	"csharp":     {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", charQuote: true},
+	"go":         {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", charQuote: true, rawQuote: '`'},
+	"rust":       {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", nested: true, charQuote: true},
+	"kotlin":     {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", nested: true, charQuote: true},
+	"scala":      {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", nested: true, charQuote: true},
+	"javascript": {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", rawQuote: '`'},
+	"typescript": {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", rawQuote: '`'},
+	"php":        {line: []string{"//", "#"}, blockStart: "/*", blockEnd: "*/"},
+	"python":     {line: []string{"#"}, tripleQuote: true},
+	"ruby":       {line: []string{"#"}},
+	"haskell":    {line: []string{"--"}, blockStart: "{-", blockEnd: "-}", nested: true},
+	"pascal":     {line: []string{"//"}, blockStart: "{", blockEnd: "}", keepBlock: "{$"},
+}
+
+// Многострочный литерал в результате: его строки не трогаем
+type literalRange struct{ start, end int }
+
+// Удаляет комментарии, пробелы в конце строк и пустые строки. Строковые
+// литералы не трогает. false - язык не поддерживается, код возвращается как есть
+func stripComments(code, language string) (string, bool) {
+	syntax, ok := commentSyntaxes[language]
+	if !ok {
+		return code, false
+	}
+
+	var out strings.Builder
+	var literals []literalRange
+	n := len(code)
+	for i := 0; i < n; {
+		c := code[i]
+
+		// Строки и символы копируем целиком
+		literalEnd := -1
+		switch {
+		case syntax.tripleQuote && (strings.HasPrefix(code[i:], `"""`) || strings.HasPrefix(code[i:], `'''`)):
+			literalEnd = n
+			if end := strings.Index(code[i+3:], code[i:i+3]); end >= 0 {
+				literalEnd = i + 3 + end + 3
+			}
+		case c == '"' || (c == '\'' && isQuotedChar(code[i:], syntax)):
+			literalEnd = skipQuoted(code, i, c)
+		case syntax.rawQuote != 0 && c == syntax.rawQuote:
+			literalEnd = n
+			if end := strings.IndexByte(code[i+1:], c); end >= 0 {
+				literalEnd = i + 1 + end + 1
+			}
+		}
+		if literalEnd >= 0 {
+			if strings.Contains(code[i:literalEnd], "\n") {
+				literals = append(literals, literalRange{out.Len(), out.Len() + literalEnd - i})
+			}
+			out.WriteString(code[i:literalEnd])
+			i = literalEnd
+			continue
+		}
+
+		// Однострочный комментарий до конца строки (перевод строки остается)
+		if isLineComment(code[i:], syntax) {
+			end := strings.IndexByte(code[i:], '\n')
+			if end < 0 {
+				break
+			}
+			i += end
+			continue
+		}
+
+		// Блочный комментарий заменяем пробелом, чтобы не склеить токены
+		if syntax.blockStart != "" && strings.HasPrefix(code[i:], syntax.blockStart) &&
+			(syntax.keepBlock == "" || !strings.HasPrefix(code[i:], syntax.keepBlock)) {
+			depth := 1
+			j := i + len(syntax.blockStart)
+			for j < n && depth > 0 {
+				switch {
+				case strings.HasPrefix(code[j:], syntax.blockEnd):
+					depth--
+					j += len(syntax.blockEnd)
+				case syntax.nested && strings.HasPrefix(code[j:], syntax.blockStart):
+					depth++
+					j += len(syntax.blockStart)
+				default:
+					j++
+				}
+			}
+			// Перевод строки сохраняем: после комментария может идти директива препроцессора
+			if strings.Contains(code[i:j], "\n") {
+				out.WriteByte('\n')
+			} else {
+				out.WriteByte(' ')
+			}
+			i = j
+			continue
+		}
+
+		out.WriteByte(c)
+		i++
+	}
+	return finishStrip(out.String(), literals), true
+}
+
+func isLineComment(s string, syntax commentSyntax) bool {
+	for _, prefix := range syntax.line {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// В Rust и Scala одиночная кавычка бывает и у времени жизни ('a), поэтому
+// символом считаем только 'x' и '\...'
+func isQuotedChar(s string, syntax commentSyntax) bool {
+	if !syntax.charQuote {
+		return true
+	}
+	if len(s) >= 2 && s[1] == '\\' {
+		return true
+	}
+	return len(s) >= 3 && s[2] == '\''
+}
+
+// Индекс сразу за закрывающей кавычкой с учетом экранирования
+func skipQuoted(code string, start int, quote byte) int {
+	for j := start + 1; j < len(code); j++ {
+		switch code[j] {
+		case '\\':
+			j++
+		case quote:
+			return j + 1
+		case '\n':
+			// Незакрытая строка - дальше не ищем
+			return j
+		}
+	}
+	return len(code)
+}
+
+// Убирает пробелы в конце строк и пустые строки, кроме строк внутри
+// многострочных литералов: там это часть значения
+func finishStrip(code string, literals []literalRange) string {
+	var out strings.Builder
+	for start := 0; start < len(code); {
+		end := strings.IndexByte(code[start:], '\n')
+		if end < 0 {
+			end = len(code)
+		} else {
+			end += start
+		}
+		line := code[start:end]
+		inLiteral := false
+		for _, lit := range literals {
+			if lit.start < end && lit.end > start {
+				inLiteral = true
+				break
+			}
+		}
+		if !inLiteral {
+			line = strings.TrimRight(line, " \t\r")
+		}
+		if line != "" || inLiteral {
+			out.WriteString(line + "\n")
+		}
+		start = end + 1
+	}
+	return out.String()
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// Живая таблица всех своих отправок контеста, которые еще проверяются.
+// Каждая отправка слушается через WatchSubmission, список периодически
+// перечитывается, чтобы подхватить решения, отправленные уже после запуска
+const (
+	monitorDefaultRescan = 15 * time.Second
+	monitorRecentLimit   = 20 // сколько последних отправок контеста просматривать
+)
+
+type monitorRow struct {
+	Submission Submission
+	Status     *SubmissionStatus
+	Final      bool
+	Err        error
+}
+
+type monitorUpdate struct {
+	ID     int
+	Event  VerdictEvent
+	Closed bool // поток отправки закрыт
+}
+
+func (v *VSCodeExtension) createMonitorCommand() *cobra.Command {
+	var rescan time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "monitor [contest_id]",
+		Short: T("monitor.short"),
+		Long:  T("monitor.long"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			contestID := v.config.CurrentContest
+			if len(args) > 0 {
+				contestID = args[0]
+			}
+			if contestID == "" {
+				return fmt.Errorf("%s", T("contest.missing"))
+			}
+			if !v.apiClient.IsAuthenticated() {
+				return ErrNotAuthenticated
+			}
+			return v.handleMonitor(cmd.Context(), contestID, rescan)
+		},
+	}
+
+	cmd.Flags().DurationVar(&rescan, "rescan", monitorDefaultRescan, T("flag.monitor_rescan"))
+	return cmd
+}
+
+// Отправка еще не получила вердикт
+func isPendingSubmission(sub Submission) bool {
+	return sub.ShownVerdict == 0 && sub.TotalPoints == 0
+}
+
+func (v *VSCodeExtension) pendingSubmissions(ctx context.Context, contestID string) ([]Submission, error) {
+	submissions, err := v.apiClient.GetContestSubmissions(ctx, contestID, monitorRecentLimit)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Submission
+	for _, sub := range submissions {
+		if isPendingSubmission(sub) {
+			pending = append(pending, sub)
+		}
+	}
+	return pending, nil
+}
+
+func (v *VSCodeExtension) handleMonitor(ctx context.Context, contestID string, rescan time.Duration) error {
+	progress(T("monitor.searching", contestID))
+	pending, err := v.pendingSubmissions(ctx, contestID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", T("report.submissions_failed"), err)
+	}
+	if len(pending) == 0 {
+		fmt.Println(T("monitor.none"))
+		return nil
+	}
+
+	rows := make(map[int]*monitorRow)
+	updates := make(chan monitorUpdate)
+	var notifications sync.WaitGroup
+	defer notifications.Wait()
+	active := 0
+	interactive := term.IsTerminal(int(os.Stdout.Fd()))
+
+	track := func(sub Submission) {
+		if _, ok := rows[sub.ID]; ok {
+			return
+		}
+		row := &monitorRow{Submission: sub}
+		rows[sub.ID] = row
+		if !interactive {
+			fmt.Println(formatMonitorRow(row))
+		}
+
+		events, err := v.apiClient.WatchSubmission(ctx, strconv.Itoa(sub.ID))
+		if err != nil {
+			row.Err, row.Final = err, true
+			return
+		}
+		active++
+		go func() {
+			for event := range events {
+				select {
+				case updates <- monitorUpdate{ID: sub.ID, Event: event}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case updates <- monitorUpdate{ID: sub.ID, Closed: true}:
+			case <-ctx.Done():
+			}
+		}()
+	}
+	for _, sub := range pending {
+		track(sub)
+	}
+
+	v.renderMonitor(contestID, rows, interactive, nil)
+
+	var rescanTick <-chan time.Time
+	if rescan > 0 {
+		ticker := time.NewTicker(rescan)
+		defer ticker.Stop()
+		rescanTick = ticker.C
+	}
+
+	for active > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case update := <-updates:
+			row := rows[update.ID]
+			event := update.Event
+			if update.Closed {
+				active--
+				row.Final = true
+				continue
+			}
+			if event.Status != nil {
+				row.Status = event.Status
+			}
+			if event.Err != nil {
+				row.Err = event.Err
+			}
+			row.Final = row.Final || event.Final || event.Err != nil
+			v.renderMonitor(contestID, rows, interactive, row)
+			if event.Final && event.Status != nil {
+				// Webhook и Telegram не должны задерживать обновление таблицы
+				notifications.Add(1)
+				go func(sub Submission, status *SubmissionStatus) {
+					defer notifications.Done()
+					v.notifySubmissionVerdict(sub, status, contestID)
+				}(row.Submission, event.Status)
+			}
+
+		case <-rescanTick:
+			fresh, err := v.pendingSubmissions(ctx, contestID)
+			if err != nil {
+				// Разовая ошибка не повод прерывать уже идущие подписки
+				continue
+			}
+			before := len(rows)
+			for _, sub := range fresh {
+				track(sub)
+			}
+			if len(rows) > before {
+				v.renderMonitor(contestID, rows, interactive, nil)
+			}
+		}
+	}
+
+	if interactive {
+		v.renderMonitor(contestID, rows, interactive, nil)
+	}
+	fmt.Print(T("monitor.done", len(rows)))
+	return nil
+}
+
+// В терминале перерисовывает таблицу целиком, иначе печатает только изменившуюся строку
+func (v *VSCodeExtension) renderMonitor(contestID string, rows map[int]*monitorRow, interactive bool, changed *monitorRow) {
+	if !interactive {
+		if changed != nil {
+			fmt.Println(formatMonitorRow(changed))
+		}
+		return
+	}
+
+	ids := make([]int, 0, len(rows))
+	for id := range rows {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	var b strings.Builder
+	b.WriteString("\033[H\033[2J")
+	fmt.Fprint(&b, T("monitor.header", contestID, time.Now().Format("15:04:05")))
+	judged := 0
+	for _, id := range ids {
+		if rows[id].Final {
+			judged++
+		}
+		b.WriteString(formatMonitorRow(rows[id]) + "\n")
+	}
+	fmt.Fprint(&b, T("monitor.footer", judged, len(rows)))
+	fmt.Print(b.String())
+}
+
+func formatMonitorRow(row *monitorRow) string {
+	state := T("verdict.pending")
+	details := ""
+	if status := row.Status; status != nil {
+		state = getStatusEmoji(status.Status)
+		switch {
+		case row.Final && status.Score > 0:
+			details = T("monitor.points", status.Score)
+		case status.Test > 0:
+			details = T("monitor.test", status.Test)
+		case status.QueuePosition > 0:
+			details = T("monitor.queue", status.QueuePosition)
+		}
+		if row.Final && status.Time != "" {
+			details = strings.TrimSpace(details + " " + status.Time)
+		}
+	}
+	if row.Err != nil {
+		state, details = T("monitor.no_status"), row.Err.Error()
+	}
+	return fmt.Sprintf("🆔 %-8d %s %s %s",
+		row.Submission.ID, padRunes(getTaskDisplayName(row.Submission), 24), padRunes(state, 26), details)
+}
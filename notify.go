@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const telegramAPIURL = "https://api.telegram.org"
+
+// Уведомления о вердиктах через собственного Telegram бота пользователя.
+// Токен бота у каждого профиля свой, хранится в системном хранилище паролей
+// (без него - в secrets.json) и не связан с session token sort-me.org
+type telegramNotifier struct {
+	token  string
+	chatID string
+	client *http.Client
+}
+
+func (v *VSCodeExtension) telegramNotifier() (*telegramNotifier, error) {
+	token, err := v.config.notifyToken()
+	if err != nil {
+		return nil, err
+	}
+	if token == "" || v.config.NotifyChatID == "" {
+		return nil, nil
+	}
+	return &telegramNotifier{
+		token:  token,
+		chatID: v.config.NotifyChatID,
+		client: v.apiClient.transport.externalClient(10 * time.Second),
+	}, nil
+}
+
+func (n *telegramNotifier) Send(text string) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"chat_id":                  n.chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(telegramAPIURL+"/bot"+n.token+"/sendMessage", "application/json", bytes.NewReader(data))
+	if err != nil {
+		// В тексте ошибки net/http есть URL, а в нем токен
+		return errors.New(T("notify.request_failed", strings.ReplaceAll(err.Error(), n.token, maskToken(n.token))))
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err := json.Unmarshal(body, &result); err != nil {
+		return errors.New(T("notify.http_status", resp.StatusCode))
+	}
+	if !result.OK {
+		return fmt.Errorf("Telegram: %s", result.Description)
+	}
+	return nil
+}
+
+func (v *VSCodeExtension) createNotifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: T("notify.short"),
+		Long:  T("notify.long"),
+		Run: func(cmd *cobra.Command, args []string) {
+			v.handleNotifyStatus()
+		},
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "set-token [token]",
+			Short: T("notify.set_token.short"),
+			Args:  cobra.MaximumNArgs(1),
+			Run: func(cmd *cobra.Command, args []string) {
+				token := ""
+				if len(args) > 0 {
+					token = args[0]
+				} else {
+					fmt.Print(T("notify.token_prompt"))
+					line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+					token = line
+				}
+				token = strings.TrimSpace(token)
+				if token == "" {
+					fmt.Println(T("notify.token_missing"))
+					return
+				}
+				if err := v.config.setNotifyToken(token); err != nil {
+					fmt.Print(T("notify.token_save_error", err))
+					return
+				}
+				fmt.Print(T("notify.token_saved", maskToken(token)))
+				if v.config.NotifyChatID == "" {
+					progressln(T("notify.chat_hint"))
+				}
+			},
+		},
+		&cobra.Command{
+			Use:   "set-chat <chat_id>",
+			Short: T("notify.set_chat.short"),
+			Args:  cobra.ExactArgs(1),
+			Run: func(cmd *cobra.Command, args []string) {
+				v.config.NotifyChatID = strings.TrimSpace(args[0])
+				if err := SaveConfig(v.config); err != nil {
+					fmt.Print(T("notify.config_save_error", err))
+					return
+				}
+				fmt.Print(T("notify.chat_set", v.config.NotifyChatID))
+			},
+		},
+		&cobra.Command{
+			Use:   "test",
+			Short: T("notify.test.short"),
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cmd.SilenceUsage = true
+				return v.handleNotifyTest()
+			},
+		},
+		&cobra.Command{
+			Use:   "clear",
+			Short: T("notify.clear.short"),
+			Args:  cobra.NoArgs,
+			Run: func(cmd *cobra.Command, args []string) {
+				if err := v.config.setNotifyToken(""); err != nil {
+					fmt.Print(T("notify.token_delete_error", err))
+					return
+				}
+				v.config.NotifyChatID = ""
+				if err := SaveConfig(v.config); err != nil {
+					fmt.Print(T("notify.config_save_error", err))
+					return
+				}
+				fmt.Println(T("notify.disabled"))
+			},
+		},
+	)
+
+	return cmd
+}
+
+func (v *VSCodeExtension) handleNotifyStatus() {
+	token, err := v.config.notifyToken()
+	if err != nil {
+		fmt.Print(T("notify.secrets_read_error", err))
+		return
+	}
+
+	fmt.Println(T("notify.status_header"))
+	if token != "" {
+		fmt.Print(T("notify.status_bot", maskToken(token)))
+	} else {
+		fmt.Println(T("notify.status_bot_none"))
+	}
+	if v.config.NotifyChatID != "" {
+		fmt.Print(T("notify.status_chat", v.config.NotifyChatID))
+	} else {
+		fmt.Println(T("notify.status_chat_none"))
+	}
+	for _, hook := range v.config.verdictWebhooks() {
+		signed := ""
+		if hook.Secret != "" {
+			signed = T("notify.webhook_signed")
+		}
+		fmt.Printf("  Webhook: %s%s\n", webhookHost(hook.URL), signed)
+	}
+}
+
+func (v *VSCodeExtension) handleNotifyTest() error {
+	notifier, err := v.telegramNotifier()
+	if err != nil {
+		return err
+	}
+	if notifier == nil {
+		progressln(T("notify.setup_hint"))
+		return errors.New(T("notify.not_configured"))
+	}
+
+	text := T("notify.test_message")
+	if v.config.Username != "" {
+		text = T("notify.test_message_for", v.config.Username)
+	}
+	if err := notifier.Send(text); err != nil {
+		return err
+	}
+	fmt.Print(T("notify.test_delivered", notifier.chatID))
+	return nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// Страницы sort-me.org для контеста, задачи и отправки. Задача в контесте
+// открывается по номеру (1 - задача A), поэтому ID задачи переводится
+// в номер по списку задач контеста
+
+const siteBaseURL = "https://sort-me.org"
+
+func contestPageURL(contestID string) string {
+	return siteBaseURL + "/contest/" + url.PathEscape(contestID)
+}
+
+func problemPageURL(contestID string, number int) string {
+	return fmt.Sprintf("%s/%d", contestPageURL(contestID), number)
+}
+
+func submissionPageURL(submissionID string) string {
+	return siteBaseURL + "/submission/" + url.PathEscape(submissionID)
+}
+
+func (v *VSCodeExtension) createOpenCommand() *cobra.Command {
+	var printOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "open",
+		Short: T("open.short"),
+		Long:  T("open.long"),
+	}
+	cmd.PersistentFlags().BoolVar(&printOnly, "print", false, T("flag.open_print"))
+
+	open := func(target string) error {
+		// Адрес в stdout нужен и без браузера: по SSH его копируют руками
+		fmt.Println(target)
+		if printOnly {
+			return nil
+		}
+		if err := openBrowser(target); err != nil {
+			return fmt.Errorf("%s: %w", T("open.browser_failed"), err)
+		}
+		return nil
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "contest [contest_id]",
+			Short: T("open.contest.short"),
+			Args:  cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cmd.SilenceUsage = true
+				contestID := v.config.CurrentContest
+				if len(args) > 0 {
+					contestID = args[0]
+				}
+				if contestID == "" {
+					return fmt.Errorf("%s", T("contest.missing"))
+				}
+				return open(contestPageURL(contestID))
+			},
+		},
+		&cobra.Command{
+			Use:   "problem [contest_id] <problem>",
+			Short: T("open.problem.short"),
+			Args:  cobra.RangeArgs(1, 2),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cmd.SilenceUsage = true
+				contestID, problem := v.config.CurrentContest, args[0]
+				if len(args) == 2 {
+					contestID, problem = args[0], args[1]
+				}
+				if contestID == "" {
+					return fmt.Errorf("%s", T("contest.missing"))
+				}
+				number, err := v.problemNumber(cmd.Context(), contestID, problem)
+				if err != nil {
+					return err
+				}
+				return open(problemPageURL(contestID, number))
+			},
+		},
+		&cobra.Command{
+			Use:   "submission <submission_id>",
+			Short: T("open.submission.short"),
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cmd.SilenceUsage = true
+				if _, err := strconv.Atoi(args[0]); err != nil {
+					return errors.New(T("open.bad_submission_id", args[0]))
+				}
+				return open(submissionPageURL(args[0]))
+			},
+		},
+	)
+	return cmd
+}
+
+// Номер задачи в контесте по букве (A, b, кириллическая С) или ID задачи.
+// Букве список задач не нужен, ID ищется в нем
+func (v *VSCodeExtension) problemNumber(ctx context.Context, contestID, problem string) (int, error) {
+	if letter := normalizeTaskLetter(problem); len(letter) == 1 && letter[0] >= 'A' && letter[0] <= 'Z' {
+		return int(letter[0]-'A') + 1, nil
+	}
+	taskID, err := strconv.Atoi(problem)
+	if err != nil {
+		return 0, errors.New(T("open.bad_problem", problem))
+	}
+	if !v.apiClient.IsAuthenticated() {
+		return 0, fmt.Errorf("%s", T("auth.required"))
+	}
+	info, err := v.apiClient.GetContestInfo(ctx, contestID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", T("open.tasks_failed"), err)
+	}
+	i := slices.IndexFunc(info.Tasks, func(task Task) bool { return task.ID == taskID })
+	if i < 0 {
+		return 0, errors.New(T("open.no_task", contestID, taskID))
+	}
+	return i + 1, nil
+}
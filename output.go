@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Режим --json: весь человекочитаемый вывод уходит в stderr,
+// а в stdout печатается только результат команды в виде JSON
+type jsonOutput struct {
+	enabled bool
+	stdout  *os.File
+	emitted bool
+	failed  bool
+}
+
+func (v *VSCodeExtension) enableJSONOutput() {
+	if v.output.enabled {
+		return
+	}
+	v.output.enabled = true
+	v.output.stdout = os.Stdout
+	// Все fmt.Print* дальше пишут в stderr
+	os.Stdout = os.Stderr
+}
+
+// --quiet: ход выполнения, подсказки и предупреждения не печатаются
+var quietMode bool
+
+// Ход выполнения и диагностика идут в stderr, чтобы в stdout оставался только
+// результат команды и его можно было передать другой программе.
+// os.Stderr берется при каждом вызове: TUI на время работы его подменяет
+func progress(args ...interface{}) {
+	if !quietMode {
+		fmt.Fprint(os.Stderr, args...)
+	}
+}
+
+func progressf(format string, args ...interface{}) {
+	if !quietMode {
+		fmt.Fprintf(os.Stderr, format, args...)
+	}
+}
+
+func progressln(args ...interface{}) {
+	if !quietMode {
+		fmt.Fprintln(os.Stderr, args...)
+	}
+}
+
+func (v *VSCodeExtension) jsonMode() bool {
+	return v.output.enabled
+}
+
+// Печатает результат команды в stdout (только в режиме --json)
+func (v *VSCodeExtension) emitJSON(value interface{}) {
+	if !v.output.enabled || v.output.emitted {
+		return
+	}
+	encoder := json.NewEncoder(v.output.stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+	v.output.emitted = true
+}
+
+// Сообщает об ошибке команды: человеку - строкой с ❌ в stderr (и с --quiet),
+// в режиме --json - объектом {"error": ...}
+func (v *VSCodeExtension) fail(message string) {
+	fmt.Fprintf(os.Stderr, "❌ %s\n", message)
+	if v.output.enabled {
+		v.emitJSON(map[string]string{"error": message})
+		v.output.failed = true
+	}
+}
+
+// Вызывается после выполнения команды. Возвращает true, если процесс должен завершиться с ошибкой
+func (v *VSCodeExtension) finishOutput(err error) bool {
+	if !v.output.enabled {
+		return err != nil
+	}
+	if err != nil {
+		result := map[string]string{"error": err.Error()}
+		if kind := apiErrorKind(err); kind != "" {
+			result["kind"] = kind
+		}
+		v.emitJSON(result)
+		return true
+	}
+	return v.output.failed
+}
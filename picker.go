@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+// Выбор контеста и задачи нечетким поиском, когда submit, problems или list
+// запущены в терминале без ID. Выбор запоминается в .sortme.yaml, чтобы
+// в следующий раз не спрашивать
+const pickerVisible = 10 // сколько вариантов видно одновременно
+
+var errPickerCancelled error = localizedError("picker.cancelled")
+
+type pickItem struct {
+	ID    string
+	Label string
+}
+
+// Выбирать можно только в терминале и не в режиме --json
+func (v *VSCodeExtension) canPick() bool {
+	return !v.jsonMode() && term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// Очки нечеткого совпадения: буквы каждого слова запроса идут в тексте
+// по порядку. Подряд идущие буквы и начала слов ценятся выше. false - не совпало
+func fuzzyScore(query, text string) (int, bool) {
+	target := []rune(strings.ToLower(text))
+	total := 0
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		pattern := []rune(word)
+		score, qi, prev := 0, 0, -2
+		for ti := 0; ti < len(target) && qi < len(pattern); ti++ {
+			if target[ti] != pattern[qi] {
+				continue
+			}
+			score++
+			if ti == prev+1 {
+				score += 3
+			}
+			if ti == 0 || !unicode.IsLetter(target[ti-1]) && !unicode.IsDigit(target[ti-1]) {
+				score += 2
+			}
+			prev = ti
+			qi++
+		}
+		if qi < len(pattern) {
+			return 0, false
+		}
+		total += score
+	}
+	return total, true
+}
+
+type pickerModel struct {
+	title    string
+	items    []pickItem
+	query    []rune
+	matches  []int // индексы items, лучшие первыми
+	cursor   int
+	chosen   int
+	finished bool
+}
+
+func (m *pickerModel) filter() {
+	type scored struct{ index, score int }
+	var found []scored
+	for i, item := range m.items {
+		if score, ok := fuzzyScore(string(m.query), item.Label); ok {
+			found = append(found, scored{i, score})
+		}
+	}
+	// Без запроса порядок исходный
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+	m.matches = m.matches[:0]
+	for _, f := range found {
+		m.matches = append(m.matches, f.index)
+	}
+	m.cursor = 0
+}
+
+func (m *pickerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch key.Type {
+	case tea.KeyCtrlC, tea.KeyEsc:
+		m.finished = true
+		return m, tea.Quit
+	case tea.KeyEnter:
+		if len(m.matches) > 0 {
+			m.chosen = m.matches[m.cursor]
+			m.finished = true
+			return m, tea.Quit
+		}
+	case tea.KeyUp, tea.KeyCtrlP:
+		m.cursor = max(m.cursor-1, 0)
+	case tea.KeyDown, tea.KeyCtrlN, tea.KeyTab:
+		m.cursor = max(min(m.cursor+1, len(m.matches)-1), 0)
+	case tea.KeyBackspace:
+		if len(m.query) > 0 {
+			m.query = m.query[:len(m.query)-1]
+			m.filter()
+		}
+	case tea.KeyCtrlU:
+		m.query = nil
+		m.filter()
+	case tea.KeySpace:
+		m.query = append(m.query, ' ')
+		m.filter()
+	case tea.KeyRunes:
+		m.query = append(m.query, key.Runes...)
+		m.filter()
+	}
+	return m, nil
+}
+
+func (m *pickerModel) View() string {
+	// После выбора список стирается, остается обычный вывод команды
+	if m.finished {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n🔎 %s▏\n", m.title, string(m.query))
+	start := max(m.cursor-pickerVisible+1, 0)
+	for i := start; i < len(m.matches) && i < start+pickerVisible; i++ {
+		marker := "  "
+		if i == m.cursor {
+			marker = "› "
+		}
+		b.WriteString(marker + m.items[m.matches[i]].Label + "\n")
+	}
+	if len(m.matches) == 0 {
+		b.WriteString(T("picker.nothing_found"))
+	}
+	fmt.Fprint(&b, T("picker.footer", len(m.matches), len(m.items)))
+	return b.String()
+}
+
+// Показывает список с поиском и возвращает выбранный вариант
+func runPicker(title string, items []pickItem) (pickItem, error) {
+	if len(items) == 0 {
+		return pickItem{}, errors.New(T("picker.empty"))
+	}
+	model := &pickerModel{title: title, items: items, chosen: -1}
+	model.filter()
+	if _, err := tea.NewProgram(model).Run(); err != nil {
+		return pickItem{}, err
+	}
+	if model.chosen < 0 {
+		return pickItem{}, errPickerCancelled
+	}
+	return items[model.chosen], nil
+}
+
+// Выбор контеста из списка (контесты кэшируются, повторный выбор быстрый)
+func (v *VSCodeExtension) pickContest(ctx context.Context) (string, error) {
+	contests, err := v.apiClient.GetContests(ctx)
+	if err != nil {
+		return "", err
+	}
+	items := make([]pickItem, len(contests))
+	for i, contest := range contests {
+		items[i] = pickItem{ID: contest.ID, Label: fmt.Sprintf("%s  %s  [%s]", contest.ID, contest.Name, contest.Status)}
+	}
+	item, err := runPicker(T("picker.contest_title"), items)
+	if err != nil {
+		return "", err
+	}
+	fmt.Print(T("picker.contest_selected", item.Label))
+	return item.ID, nil
+}
+
+// Выбор задачи контеста по букве, названию или ID
+func (v *VSCodeExtension) pickProblem(ctx context.Context, contestID string) (string, error) {
+	info, err := v.apiClient.GetContestInfo(ctx, contestID)
+	if err != nil {
+		return "", err
+	}
+	items := make([]pickItem, len(info.Tasks))
+	for i, task := range info.Tasks {
+		items[i] = pickItem{ID: fmt.Sprint(task.ID), Label: fmt.Sprintf("%s. %s  (%d)", taskLetter(i), task.Name, task.ID)}
+	}
+	item, err := runPicker(T("picker.task_title", contestID), items)
+	if err != nil {
+		return "", err
+	}
+	fmt.Print(T("picker.task_selected", item.Label))
+	return item.ID, nil
+}
+
+// Записывает выбор в ближайший .sortme.yaml от dir, а если его нет - создает
+// .sortme.yaml в dir. problemKey - имя файла без расширения для problems
+func saveWorkspacePick(dir, contestID, problemKey, problemID string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	_, files, err := findWorkspace(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(files) > 0 {
+		dir = filepath.Dir(files[0])
+	}
+	// Правим только сам файл, а не объединение всех найденных
+	binding, err := loadWorkspaceBinding(dir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		binding = &WorkspaceBinding{}
+	}
+	if contestID != "" {
+		binding.ContestID = contestID
+	}
+	if problemID != "" {
+		if binding.Problems == nil {
+			binding.Problems = make(map[string]string)
+		}
+		binding.Problems[problemKey] = problemID
+	}
+	if err := saveWorkspaceBinding(dir, *binding); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, workspaceFileName), nil
+}
+
+// Контест для problems и list: выбор из списка с сохранением в .sortme.yaml
+// текущего каталога
+func (v *VSCodeExtension) pickContestForWorkspace(ctx context.Context) (string, error) {
+	contestID, err := v.pickContest(ctx)
+	if err != nil {
+		return "", err
+	}
+	if path, err := saveWorkspacePick(".", contestID, "", ""); err != nil {
+		progress(T("picker.save_failed", err))
+	} else {
+		fmt.Print(T("picker.contest_saved", path))
+	}
+	return contestID, nil
+}
+
+// Недостающие контест и задача для submit. Задача запоминается для имени
+// файла (problems в .sortme.yaml), а не для всего каталога
+func (v *VSCodeExtension) pickSubmitTarget(ctx context.Context, filename string, opts *SubmitOptions) error {
+	pickedContest := ""
+	if opts.ContestID == "" {
+		contestID, err := v.pickContest(ctx)
+		if err != nil {
+			return err
+		}
+		opts.ContestID, pickedContest = contestID, contestID
+	}
+	pickedProblem := ""
+	if opts.ProblemID == "" {
+		problemID, err := v.pickProblem(ctx, opts.ContestID)
+		if err != nil {
+			return err
+		}
+		opts.ProblemID, pickedProblem = problemID, problemID
+	}
+
+	base := filepath.Base(filename)
+	key := strings.TrimSuffix(base, filepath.Ext(base))
+	if path, err := saveWorkspacePick(filepath.Dir(filename), pickedContest, key, pickedProblem); err != nil {
+		progress(T("picker.save_failed", err))
+	} else {
+		fmt.Print(T("picker.selection_saved", path))
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWorkers = 4
+	// Запросов в секунду ко всему API от всех горутин вместе
+	defaultRequestsPerSecond = 5.0
+)
+
+// Общий ограничитель запросов к API (token bucket): каждый запрос, включая
+// повторы и подключения WebSocket, забирает токен. Накопить можно не больше
+// токенов, чем приходит за секунду, поэтому параллельные воркеры, watch и
+// опрос статуса вместе не превышают requests_per_second
+type requestLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // токенов в секунду, 0 - без ограничения
+	burst  float64
+	tokens float64
+	last   time.Time
+	clock  clock
+}
+
+func newRequestLimiter(perSecond float64, c clock) *requestLimiter {
+	if perSecond <= 0 {
+		return &requestLimiter{clock: c}
+	}
+	burst := math.Max(1, math.Floor(perSecond))
+	return &requestLimiter{rate: perSecond, burst: burst, tokens: burst, last: c.Now(), clock: c}
+}
+
+func (l *requestLimiter) Wait(ctx context.Context) error {
+	if l.rate == 0 {
+		return ctx.Err()
+	}
+	l.mu.Lock()
+	now := l.clock.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	// Токен занимается сразу, даже в долг: ожидающие встают в очередь
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	return sleepClock(ctx, l.clock, wait)
+}
+
+// Число параллельных запросов. В режиме экономии трафика - по одному
+func (a *APIClient) workers() int {
+	if a.config.LowBandwidth || a.config.Workers < 1 {
+		return 1
+	}
+	return a.config.Workers
+}
+
+// Выполняет fn для каждого элемента в пуле из workers горутин.
+// Результаты и ошибки лежат на тех же позициях, что и элементы
+func parallelMap[T, R any](workers int, items []T, fn func(T) (R, error)) ([]R, []error) {
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = fn(items[i])
+			}
+		}()
+	}
+	for i := range items {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, errs
+}
+
+// Отправки по задачам контеста, загружаемые параллельно.
+// Каждой отправке проставляются задача и контест
+func (a *APIClient) fetchTaskSubmissions(ctx context.Context, contestID, contestName string, tasks []Task, endpoint func(Task) string, perTask int) ([][]Submission, []error) {
+	return parallelMap(a.workers(), tasks, func(task Task) ([]Submission, error) {
+		submissions, err := a.tryGetSubmissions(ctx, endpoint(task), perTask)
+		if err != nil {
+			return nil, err
+		}
+		for i := range submissions {
+			submissions[i].ProblemID = task.ID
+			submissions[i].ProblemName = task.Name
+			submissions[i].ContestID = contestID
+			submissions[i].ContestName = contestName
+		}
+		return submissions, nil
+	})
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// Именованные профили: отдельные аккаунты (учеба, личная практика) со своими
+// токенами. Основной профиль "default" - это session_token, user_id и username
+// верхнего уровня конфига, остальные лежат в profiles. Активный профиль
+// выбирается флагом --profile, переменной SORTME_PROFILE или current_profile
+const defaultProfileName = "default"
+
+var reProfileName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Активный профиль текущего запуска ("" - основной). Нужен путям кэша
+// и локальной базы, чтобы данные разных аккаунтов не смешивались
+var activeProfile string
+
+type ProfileCredentials struct {
+	SessionToken string `mapstructure:"session_token" yaml:"session_token"`
+	UserID       string `mapstructure:"user_id" yaml:"user_id"`
+	Username     string `mapstructure:"username" yaml:"username"`
+}
+
+func (c *Config) credentials() ProfileCredentials {
+	return ProfileCredentials{SessionToken: c.SessionToken, UserID: c.UserID, Username: c.Username}
+}
+
+func (c *Config) setCredentials(creds ProfileCredentials) {
+	c.SessionToken, c.UserID, c.Username = creds.SessionToken, creds.UserID, creds.Username
+}
+
+func normalizeProfileName(name string) string {
+	if name == defaultProfileName {
+		return ""
+	}
+	return name
+}
+
+// Переключает учетные данные на профиль name в памяти. create разрешает
+// еще не существующий профиль (для auth --profile)
+func (c *Config) useProfile(name string, create bool) error {
+	name = normalizeProfileName(name)
+	if name != "" && !reProfileName.MatchString(name) {
+		return errors.New(T("profile.bad_name", name))
+	}
+
+	// Возвращаемся к основному профилю, потом переключаемся на нужный
+	if c.profile != "" {
+		if c.Profiles == nil {
+			c.Profiles = make(map[string]ProfileCredentials)
+		}
+		c.Profiles[c.profile] = c.credentials()
+		c.setCredentials(c.defaultCredentials)
+		c.profile = ""
+	}
+	activeProfile = ""
+	if name == "" {
+		return nil
+	}
+
+	creds, ok := c.Profiles[name]
+	if !ok && !create {
+		return errors.New(T("profile.not_found_create", name, name))
+	}
+	c.defaultCredentials = c.credentials()
+	c.setCredentials(creds)
+	c.profile = name
+	activeProfile = name
+	return nil
+}
+
+// Имя активного профиля для вывода
+func (c *Config) profileName() string {
+	if c.profile == "" {
+		return defaultProfileName
+	}
+	return c.profile
+}
+
+// Учетные данные для записи: основной профиль - в корень конфига, активный - в profiles
+func (c *Config) persistedCredentials() (ProfileCredentials, map[string]ProfileCredentials) {
+	if c.profile == "" {
+		return c.credentials(), c.Profiles
+	}
+	profiles := make(map[string]ProfileCredentials, len(c.Profiles)+1)
+	for name, creds := range c.Profiles {
+		profiles[name] = creds
+	}
+	profiles[c.profile] = c.credentials()
+	return c.defaultCredentials, profiles
+}
+
+// Каталог данных активного профиля внутри base: основной профиль пишет в сам base
+func profileDataDir(base string) string {
+	if activeProfile == "" {
+		return base
+	}
+	return filepath.Join(base, "profiles", activeProfile)
+}
+
+// Применяет профиль и переносит кэш в его каталог
+func (v *VSCodeExtension) switchProfile(name string, create bool) error {
+	if err := v.config.useProfile(name, create); err != nil {
+		return err
+	}
+	v.apiClient.cache.dir = profileDataDir(getCacheDir())
+	return nil
+}
+
+func (v *VSCodeExtension) createProfileCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile [username]",
+		Short: T("profile.short"),
+		Long:  T("profile.long"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			username := ""
+			if len(args) > 0 {
+				username = args[0]
+			}
+			return v.handleUserProfile(cmd.Context(), username)
+		},
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: T("profile.list.short"),
+			Args:  cobra.NoArgs,
+			Run: func(cmd *cobra.Command, args []string) {
+				v.handleProfileList()
+			},
+		},
+		&cobra.Command{
+			Use:   "use <name>",
+			Short: T("profile.use.short"),
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cmd.SilenceUsage = true
+				return v.handleProfileUse(args[0])
+			},
+		},
+		&cobra.Command{
+			Use:   "rm <name>",
+			Short: T("profile.rm.short"),
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cmd.SilenceUsage = true
+				return v.handleProfileRemove(args[0])
+			},
+		},
+	)
+	return cmd
+}
+
+func (v *VSCodeExtension) handleProfileList() {
+	current, profiles := v.config.persistedCredentials()
+
+	type item struct {
+		Name     string `json:"name"`
+		Username string `json:"username"`
+		Active   bool   `json:"active"`
+		LoggedIn bool   `json:"logged_in"`
+	}
+	items := []item{{
+		Name:     defaultProfileName,
+		Username: current.Username,
+		Active:   v.config.profile == "",
+		LoggedIn: current.SessionToken != "",
+	}}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		items = append(items, item{
+			Name:     name,
+			Username: profiles[name].Username,
+			Active:   v.config.profile == name,
+			LoggedIn: profiles[name].SessionToken != "",
+		})
+	}
+
+	if v.jsonMode() {
+		v.emitJSON(items)
+		return
+	}
+
+	fmt.Println(T("profile.list_header"))
+	for _, it := range items {
+		marker := "  "
+		if it.Active {
+			marker = "▶ "
+		}
+		user := it.Username
+		if !it.LoggedIn {
+			user = T("profile.logged_out")
+		}
+		fmt.Printf("  %s%-16s %s\n", marker, it.Name, user)
+	}
+	progressln(T("profile.hint"))
+}
+
+func (v *VSCodeExtension) handleProfileUse(name string) error {
+	if err := v.switchProfile(name, false); err != nil {
+		return err
+	}
+	v.config.CurrentProfile = normalizeProfileName(name)
+	if err := SaveConfig(v.config); err != nil {
+		return fmt.Errorf("%s: %w", T("profile.config_save_failed"), err)
+	}
+
+	fmt.Print(T("profile.active", v.config.profileName()))
+	if v.config.Username != "" {
+		fmt.Printf(" (%s)", v.config.Username)
+	}
+	fmt.Println()
+	if os.Getenv("SORTME_PROFILE") != "" {
+		progressln(T("profile.env_override"))
+	}
+	return nil
+}
+
+func (v *VSCodeExtension) handleProfileRemove(name string) error {
+	name = normalizeProfileName(name)
+	if name == "" {
+		return errors.New(T("profile.default_remove"))
+	}
+	if _, ok := v.config.Profiles[name]; !ok && v.config.profile != name {
+		return errors.New(T("profile.not_found", name))
+	}
+
+	// Удаляемый профиль не должен остаться активным
+	if v.config.profile == name {
+		if err := v.switchProfile("", false); err != nil {
+			return err
+		}
+	}
+	if v.config.CurrentProfile == name {
+		v.config.CurrentProfile = ""
+	}
+	delete(v.config.Profiles, name)
+	keyringForget(name)
+	if err := v.config.storeNotifyToken(name, ""); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️ %v\n", err)
+	}
+	if err := SaveConfig(v.config); err != nil {
+		return fmt.Errorf("%s: %w", T("profile.config_save_failed"), err)
+	}
+
+	// Кэш и локальная база профиля больше не нужны
+	for _, base := range []string{getCacheDir(), getConfigPath()} {
+		os.RemoveAll(filepath.Join(base, "profiles", name))
+	}
+	fmt.Print(T("profile.removed", name))
+	return nil
+}
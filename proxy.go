@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Прокси для HTTP и WebSocket. Ключ proxy в конфиге важнее окружения,
+// proxy: direct - напрямую, даже если переменные заданы. Без ключа -
+// HTTPS_PROXY/HTTP_PROXY, затем ALL_PROXY, с учетом NO_PROXY
+
+const proxyDirect = "direct"
+
+type proxyFunc func(*http.Request) (*url.URL, error)
+
+// Адрес прокси из конфига: http://host:3128, socks5://host:1080 или host:3128
+func parseProxyURL(raw string) (*url.URL, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", T("proxy.invalid", raw), err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, errors.New(T("proxy.scheme", raw))
+	}
+	if u.Host == "" {
+		return nil, errors.New(T("proxy.no_host", raw))
+	}
+	return u, nil
+}
+
+func newProxyFunc(setting string) proxyFunc {
+	switch setting = strings.TrimSpace(setting); {
+	case setting == "":
+		return proxyFromEnvironment
+	case strings.EqualFold(setting, proxyDirect):
+		return nil
+	}
+	u, err := parseProxyURL(setting)
+	if err != nil {
+		// Молча идти в обход прокси нельзя: в такой сети это просто таймаут
+		return func(*http.Request) (*url.URL, error) { return nil, err }
+	}
+	return http.ProxyURL(u)
+}
+
+// http.ProxyFromEnvironment не знает ALL_PROXY, а SOCKS-прокси обычно задают им
+func proxyFromEnvironment(req *http.Request) (*url.URL, error) {
+	u, err := http.ProxyFromEnvironment(req)
+	if u != nil || err != nil {
+		return u, err
+	}
+	all := getenvAny("ALL_PROXY", "all_proxy")
+	if all == "" || bypassProxy(req.URL.Hostname()) {
+		return nil, nil
+	}
+	return parseProxyURL(all)
+}
+
+func getenvAny(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Хост из NO_PROXY или локальный - без прокси, как у http.ProxyFromEnvironment
+func bypassProxy(host string) bool {
+	host = strings.ToLower(host)
+	if host == "localhost" {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return true
+	}
+	for _, entry := range strings.Split(getenvAny("NO_PROXY", "no_proxy"), ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if h, _, err := net.SplitHostPort(entry); err == nil {
+			entry = h
+		}
+		switch {
+		case entry == "":
+		case entry == "*":
+			return true
+		case host == strings.TrimPrefix(entry, "."), strings.HasSuffix(host, "."+strings.TrimPrefix(entry, ".")):
+			return true
+		}
+	}
+	return false
+}
+
+// gorilla/websocket знает только http и socks5 (имя хоста и так
+// разрешает прокси, socks5h для него - тот же socks5)
+func (p proxyFunc) websocket() proxyFunc {
+	if p == nil {
+		return nil
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		u, err := p(req)
+		if u != nil && u.Scheme == "socks5h" {
+			copied := *u
+			copied.Scheme = "socks5"
+			u = &copied
+		}
+		return u, err
+	}
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Ответы API, которые не удалось разобрать, сохраняются в карантин,
+// чтобы пользователь мог приложить их к баг-репорту. Перед записью из них
+// вырезаются токены и другие личные данные
+const (
+	quarantineDirName  = "quarantine"
+	quarantineMaxFiles = 50
+	quarantineMaxBody  = 64 * 1024
+)
+
+// Поля, значения которых не должны попасть в отчет: по вхождению подстроки
+// и по точному имени (исходный код решения, но не exit_code и т.п.)
+var (
+	quarantineSensitiveKeys  = []string{"token", "password", "secret", "session", "cookie", "email", "phone"}
+	quarantineSensitiveExact = []string{"auth", "authorization", "code", "source", "source_code"}
+)
+
+type quarantineRecord struct {
+	Kind       string          `json:"kind"`
+	Endpoint   string          `json:"endpoint"`
+	Error      string          `json:"error"`
+	CapturedAt time.Time       `json:"captured_at"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	RawPayload string          `json:"raw_payload,omitempty"`
+	Truncated  bool            `json:"truncated,omitempty"`
+}
+
+// Подсказку печатаем один раз на вид ответа, а не на каждый запрос
+var quarantineHinted sync.Map
+
+func getQuarantineDir() string {
+	return filepath.Join(getConfigPath(), quarantineDirName)
+}
+
+// Сохраняет неразобранный ответ и печатает подсказку. Ошибки карантина
+// не должны мешать основной команде, поэтому они только выводятся
+func (a *APIClient) quarantine(kind, endpoint string, body []byte, parseErr error) {
+	path, err := saveQuarantine(kind, a.redactSecrets(endpoint), a.redactSecrets(string(body)), parseErr)
+	if err != nil {
+		progress(T("quarantine.save_failed", err))
+		return
+	}
+	if _, shown := quarantineHinted.LoadOrStore(kind, true); !shown {
+		progress(T("quarantine.saved", kind, path))
+	}
+}
+
+// Убирает из текста session token пользователя
+func (a *APIClient) redactSecrets(text string) string {
+	if a.config.SessionToken != "" {
+		text = strings.ReplaceAll(text, a.config.SessionToken, "[redacted]")
+	}
+	return text
+}
+
+func saveQuarantine(kind, endpoint, body string, parseErr error) (string, error) {
+	dir := getQuarantineDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	record := quarantineRecord{
+		Kind:       kind,
+		Endpoint:   endpoint,
+		CapturedAt: time.Now(),
+	}
+	if parseErr != nil {
+		record.Error = parseErr.Error()
+	}
+	if len(body) > quarantineMaxBody {
+		body = body[:quarantineMaxBody]
+		record.Truncated = true
+	}
+
+	// JSON чистим по ключам, все остальное сохраняем как текст
+	var payload interface{}
+	if !record.Truncated && json.Unmarshal([]byte(body), &payload) == nil {
+		sanitized, err := json.Marshal(sanitizePayload(payload))
+		if err != nil {
+			return "", err
+		}
+		record.Payload = sanitized
+	} else {
+		record.RawPayload = body
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	// Суффикс от CreateTemp не дает перезаписать ответ, полученный в ту же миллисекунду
+	file, err := os.CreateTemp(dir, fmt.Sprintf("%s_%s_*.json", record.CapturedAt.Format("20060102-150405.000"), kind))
+	if err != nil {
+		return "", err
+	}
+	path := file.Name()
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(path)
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	pruneQuarantine(quarantineMaxFiles)
+	return path, nil
+}
+
+// Заменяет значения чувствительных полей, сохраняя структуру ответа
+func sanitizePayload(value interface{}) interface{} {
+	redactPayload(value, isSensitiveKey)
+	return value
+}
+
+// Заменяет значения полей, для которых sensitive верно. true - что-то заменено
+func redactPayload(value interface{}, sensitive func(string) bool) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if sensitive(key) {
+				v[key] = "[redacted]"
+				changed = true
+			} else if redactPayload(item, sensitive) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if redactPayload(item, sensitive) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range quarantineSensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return slices.Contains(quarantineSensitiveExact, key)
+}
+
+// Файлы карантина, старые первыми
+func listQuarantine() ([]os.DirEntry, error) {
+	entries, err := os.ReadDir(getQuarantineDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var files []os.DirEntry
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			files = append(files, entry)
+		}
+	}
+	// Имена начинаются с времени, поэтому сортировка по имени - по времени
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+	return files, nil
+}
+
+// Оставляет только keep самых новых файлов
+func pruneQuarantine(keep int) {
+	files, err := listQuarantine()
+	if err != nil || len(files) <= keep {
+		return
+	}
+	for _, entry := range files[:len(files)-keep] {
+		os.Remove(filepath.Join(getQuarantineDir(), entry.Name()))
+	}
+}
+
+func (v *VSCodeExtension) createDevtoolsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "devtools",
+		Short: T("devtools.short"),
+	}
+
+	quarantineCmd := &cobra.Command{
+		Use:   "quarantine",
+		Short: T("devtools.quarantine.short"),
+		Long:  T("devtools.quarantine.long"),
+	}
+	quarantineCmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: T("devtools.quarantine.list.short"),
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cmd.SilenceUsage = true
+				return v.handleQuarantineList()
+			},
+		},
+		&cobra.Command{
+			Use:   "clear",
+			Short: T("devtools.quarantine.clear.short"),
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cmd.SilenceUsage = true
+				return v.handleQuarantineClear()
+			},
+		},
+	)
+
+	cmd.AddCommand(quarantineCmd)
+	return cmd
+}
+
+func (v *VSCodeExtension) handleQuarantineList() error {
+	files, err := listQuarantine()
+	if err != nil {
+		return err
+	}
+
+	type item struct {
+		File       string    `json:"file"`
+		Kind       string    `json:"kind"`
+		Endpoint   string    `json:"endpoint"`
+		Error      string    `json:"error"`
+		CapturedAt time.Time `json:"captured_at"`
+		Size       int64     `json:"size"`
+	}
+	items := []item{}
+	for _, entry := range files {
+		path := filepath.Join(getQuarantineDir(), entry.Name())
+		it := item{File: path}
+		if info, err := entry.Info(); err == nil {
+			it.Size = info.Size()
+		}
+		var record quarantineRecord
+		if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &record) == nil {
+			it.Kind, it.Endpoint, it.Error, it.CapturedAt = record.Kind, record.Endpoint, record.Error, record.CapturedAt
+		}
+		items = append(items, it)
+	}
+
+	if v.jsonMode() {
+		v.emitJSON(items)
+		return nil
+	}
+
+	if len(items) == 0 {
+		fmt.Println(T("quarantine.empty"))
+		return nil
+	}
+
+	fmt.Print(T("quarantine.header", len(items), getQuarantineDir()))
+	for _, it := range items {
+		fmt.Printf("  %s  %-20s %s\n", it.CapturedAt.Format("2006-01-02 15:04:05"), it.Kind, it.Endpoint)
+		if it.Error != "" {
+			fmt.Printf("  %19s  ↳ %s\n", "", it.Error)
+		}
+	}
+	progressln(T("quarantine.hint"))
+	return nil
+}
+
+func (v *VSCodeExtension) handleQuarantineClear() error {
+	files, err := listQuarantine()
+	if err != nil {
+		return err
+	}
+	for _, entry := range files {
+		if err := os.Remove(filepath.Join(getQuarantineDir(), entry.Name())); err != nil {
+			return err
+		}
+	}
+	fmt.Print(T("quarantine.cleared", len(files)))
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// Ожидание вердикта при загруженной очереди проверки. Пока решение
+// в очереди, сообщения приходят редко: таймаут чтения растет, а после
+// обрыва соединения переподключаемся с увеличивающейся паузой
+const (
+	wsFirstMessageTimeout = 60 * time.Second
+	wsMessageTimeout      = 30 * time.Second
+	// Столько сообщений "в очереди" подряд - признак загруженной очереди
+	queueCongestionUpdates = 3
+	queueMaxTimeout        = 3 * time.Minute
+	queueMaxWait           = 15 * time.Minute
+	queueReconnectMin      = 5 * time.Second
+	queueReconnectMax      = time.Minute
+)
+
+func isQueuedStatus(status string) bool {
+	switch strings.ToLower(status) {
+	case "pending", "in_queue", "queued", "waiting":
+		return true
+	}
+	return false
+}
+
+type queueTracker struct {
+	clock      clock
+	started    time.Time
+	updates    int // сообщений "в очереди" подряд
+	congested  bool
+	reconnects int
+}
+
+func newQueueTracker(c clock) *queueTracker {
+	return &queueTracker{clock: c, started: c.Now()}
+}
+
+// Учитывает статус. Возвращает true, когда очередь впервые признана загруженной
+func (q *queueTracker) observe(status *SubmissionStatus) bool {
+	if !isQueuedStatus(status.Status) {
+		q.updates = 0
+		return false
+	}
+	q.updates++
+	if q.updates >= queueCongestionUpdates && !q.congested {
+		q.congested = true
+		return true
+	}
+	return false
+}
+
+// Таймаут до следующего сообщения: удваивается каждые queueCongestionUpdates
+// сообщений "в очереди" подряд
+func (q *queueTracker) timeout() time.Duration {
+	timeout := wsMessageTimeout
+	for i := q.updates / queueCongestionUpdates; i > 0 && timeout < queueMaxTimeout; i-- {
+		timeout *= 2
+	}
+	return min(timeout, queueMaxTimeout)
+}
+
+// Пауза перед переподключением. false - ждать больше нет смысла:
+// очередь не загружена (просто пропала связь) или вышло общее время
+func (q *queueTracker) reconnectDelay() (time.Duration, bool) {
+	if !q.congested || q.clock.Now().Sub(q.started) > queueMaxWait {
+		return 0, false
+	}
+	delay := queueReconnectMin
+	for i := 0; i < q.reconnects && delay < queueReconnectMax; i++ {
+		delay *= 2
+	}
+	q.reconnects++
+	return min(delay, queueReconnectMax), true
+}
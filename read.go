@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func (v *VSCodeExtension) createReadCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "read [contest_id] <problem_id>",
+		Short: T("read.short"),
+		Long:  T("read.long"),
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			contestID := v.config.CurrentContest
+			problemID := args[0]
+			if len(args) == 2 {
+				contestID, problemID = args[0], args[1]
+			}
+			if contestID == "" {
+				progressln(T("download.hint"))
+				return fmt.Errorf("%s", T("contest.missing"))
+			}
+			if !v.apiClient.IsAuthenticated() {
+				return fmt.Errorf("%s", T("auth.required"))
+			}
+
+			// Условие берется из кэша, а при недоступном сервере - даже устаревшее
+			statement, err := v.apiClient.GetTaskStatement(cmd.Context(), contestID, problemID)
+			if err != nil {
+				return fmt.Errorf("%s: %w", T("read.statement_failed"), err)
+			}
+
+			if v.jsonMode() {
+				v.emitJSON(map[string]interface{}{
+					"contest_id": contestID,
+					"problem_id": problemID,
+					"name":       statement.Name,
+					"markdown":   statement.Markdown(),
+					"offline":    v.apiClient.Offline(),
+				})
+				return nil
+			}
+			fmt.Print("\n" + statement.Markdown())
+			return nil
+		},
+	}
+}
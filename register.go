@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func (v *VSCodeExtension) createRegisterCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "register [contest_id]",
+		Short: T("register.short"),
+		Long:  T("register.long"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			contestID := v.config.CurrentContest
+			if len(args) > 0 {
+				contestID = args[0]
+			}
+			if contestID == "" {
+				progressln(T("register.usage_hint"))
+				return fmt.Errorf("%s", T("contest.missing"))
+			}
+			if !v.apiClient.IsAuthenticated() {
+				return fmt.Errorf("%s", T("auth.required"))
+			}
+			return v.handleRegister(cmd.Context(), contestID)
+		},
+	}
+}
+
+func (v *VSCodeExtension) handleRegister(ctx context.Context, contestID string) error {
+	info, err := v.apiClient.GetContestInfo(ctx, contestID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", T("api.contest_info_failed"), err)
+	}
+
+	result := map[string]interface{}{
+		"contest_id": contestID,
+		"name":       info.Name,
+		"registered": info.Registered,
+		"already":    info.Registered,
+	}
+
+	if info.Registered {
+		fmt.Print(T("register.already", info.Name))
+		v.emitJSON(result)
+		return nil
+	}
+
+	progress(T("register.registering", info.Name))
+	if err := v.apiClient.RegisterForContest(ctx, contestID); err != nil {
+		return fmt.Errorf("%s: %w", T("start.register_failed"), err)
+	}
+
+	// Сервер мог ответить успехом, но проверяем по свежей информации о контесте
+	confirmed := true
+	if fresh, err := v.apiClient.GetContestInfo(ctx, contestID); err == nil {
+		info = fresh
+		confirmed = fresh.Registered
+	}
+	result["registered"] = confirmed
+	v.emitJSON(result)
+
+	if !confirmed {
+		progress(T("register.unconfirmed", contestID))
+		return nil
+	}
+
+	fmt.Println(T("register.done"))
+	if len(info.Tasks) > 0 {
+		rememberContest(contestID, info)
+		fmt.Print(T("register.tasks", len(info.Tasks)))
+		progress(T("register.start_hint", contestID))
+	} else {
+		progress(T("workspace.wait_hint", contestID))
+	}
+	return nil
+}
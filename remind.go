@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Напоминания о начале контеста: уведомление на рабочем столе (и в Telegram,
+// если он настроен) за --before до старта и в момент начала. Таймер работает
+// в терминале, с --detach - в фоне без терминала
+
+// Время контеста перепроверяется: старт иногда переносят
+const remindRefresh = 10 * time.Minute
+
+func (v *VSCodeExtension) createRemindCommand() *cobra.Command {
+	var before []time.Duration
+	var detach bool
+
+	cmd := &cobra.Command{
+		Use:   "remind [contest_id]",
+		Short: T("remind.short"),
+		Long:  T("remind.long"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			contestID := v.config.CurrentContest
+			if len(args) > 0 {
+				contestID = args[0]
+			}
+			if contestID == "" {
+				return fmt.Errorf("%s", T("contest.missing"))
+			}
+			if !v.apiClient.IsAuthenticated() {
+				return ErrNotAuthenticated
+			}
+			if detach {
+				return detachRemind(contestID)
+			}
+			return v.handleRemind(cmd.Context(), contestID, before)
+		},
+	}
+
+	cmd.Flags().DurationSliceVar(&before, "before", []time.Duration{15 * time.Minute}, T("flag.remind_before"))
+	cmd.Flags().BoolVarP(&detach, "detach", "d", false, T("flag.remind_detach"))
+	return cmd
+}
+
+// Уведомление на рабочем столе средствами системы
+func desktopNotify(title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title %q", message, title))
+	case "windows":
+		// Текст через окружение, чтобы не экранировать его для PowerShell
+		script := `Add-Type -AssemblyName System.Windows.Forms; $n = New-Object System.Windows.Forms.NotifyIcon; ` +
+			`$n.Icon = [System.Drawing.SystemIcons]::Information; $n.Visible = $true; ` +
+			`$n.ShowBalloonTip(10000, $env:SORTME_TITLE, $env:SORTME_MESSAGE, 'Info'); Start-Sleep -Seconds 10; $n.Dispose()`
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+		cmd.Env = append(os.Environ(), "SORTME_TITLE="+title, "SORTME_MESSAGE="+message)
+		return cmd.Start()
+	default:
+		cmd = exec.Command("notify-send", "--app-name=sortme", title, message)
+	}
+	return cmd.Run()
+}
+
+// Напоминание всеми доступными способами: терминал, рабочий стол, Telegram.
+// Если рабочий стол недоступен, после первой ошибки больше не пробуем
+func (v *VSCodeExtension) sendReminder(message string, desktop *bool) {
+	fmt.Printf("\a%s %s\n", time.Now().Format("15:04:05"), message)
+	if *desktop {
+		if err := desktopNotify("sortme", message); err != nil {
+			progress(T("remind.desktop_unavailable", err))
+			*desktop = false
+		}
+	}
+	if notifier, err := v.telegramNotifier(); err == nil && notifier != nil {
+		if err := notifier.Send(message); err != nil {
+			progressf("⚠️ Telegram: %v\n", err)
+		}
+	}
+}
+
+// Запускает этот же remind без --detach отдельным процессом без терминала
+func detachRemind(contestID string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	var args []string
+	for _, arg := range os.Args[1:] {
+		if arg != "--detach" && arg != "-d" {
+			args = append(args, arg)
+		}
+	}
+	cmd := exec.Command(executable, args...)
+	cmd.SysProcAttr = detachedProcAttr()
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%s: %w", T("remind.background_failed"), err)
+	}
+	fmt.Print(T("remind.background", contestID, cmd.Process.Pid))
+	if runtime.GOOS == "windows" {
+		progress(T("remind.cancel_windows", cmd.Process.Pid))
+	} else {
+		progress(T("remind.cancel", cmd.Process.Pid))
+	}
+	return cmd.Process.Release()
+}
+
+func (v *VSCodeExtension) handleRemind(ctx context.Context, contestID string, before []time.Duration) error {
+	timing, err := v.contestTiming(ctx, contestID)
+	if err != nil {
+		return err
+	}
+	offset, _ := v.apiClient.ServerClockOffset(ctx)
+	now := func() time.Time { return time.Now().Add(offset) }
+	if !now().Before(timing.Starts) {
+		return errors.New(T("remind.already_started", timing.Name))
+	}
+	// До начала информация о контесте бывает закрыта: тогда не предупреждаем
+	if info, err := v.apiClient.GetContestInfo(ctx, contestID); err == nil && !info.Registered {
+		progress(T("remind.not_registered", timing.Name, contestID))
+	}
+
+	// Сначала дальние напоминания. Прошедшие заменяются одним немедленным
+	before = slices.Clone(before)
+	slices.SortFunc(before, func(a, b time.Duration) int { return int(b - a) })
+	var pending []time.Duration
+	for _, d := range before {
+		if d > 0 && timing.Starts.Add(-d).After(now()) {
+			pending = append(pending, d)
+		}
+	}
+	fmt.Print(T("remind.scheduled",
+		timing.Name, timing.Starts.Local().Format("02.01 15:04"), formatWaitRemaining(timing.Starts.Sub(now()))))
+	desktop := true
+	if len(pending) < len(before) {
+		v.sendReminder(T("remind.starts_in", timing.Name, formatWaitRemaining(timing.Starts.Sub(now()))), &desktop)
+	}
+
+	refreshed := time.Now()
+	waitUntil := func(at func() time.Time) error {
+		// Короткие отрезки по часам: сон ноутбука не сдвигает напоминание
+		for left := at().Sub(now()); left > 0; left = at().Sub(now()) {
+			if err := sleepContext(ctx, min(left, time.Minute)); err != nil {
+				return err
+			}
+			if time.Since(refreshed) > remindRefresh {
+				refreshed = time.Now()
+				if fresh, err := v.contestTiming(ctx, contestID); err == nil && !fresh.Starts.Equal(timing.Starts) {
+					progress(T("remind.rescheduled", fresh.Starts.Local().Format("02.01 15:04")))
+					timing = fresh
+				}
+			}
+		}
+		return nil
+	}
+
+	for _, d := range pending {
+		if err := waitUntil(func() time.Time { return timing.Starts.Add(-d) }); err != nil {
+			return err
+		}
+		v.sendReminder(T("remind.starts_in_at",
+			timing.Name, formatWaitRemaining(timing.Starts.Sub(now())), timing.Starts.Local().Format("15:04")), &desktop)
+	}
+	if err := waitUntil(func() time.Time { return timing.Starts }); err != nil {
+		return err
+	}
+	v.sendReminder(T("remind.started", timing.Name, contestID), &desktop)
+	return nil
+}
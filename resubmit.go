@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func (v *VSCodeExtension) createResubmitCommand() *cobra.Command {
+	var opts SubmitOptions
+
+	cmd := &cobra.Command{
+		Use:   "resubmit <submission_id>",
+		Short: T("resubmit.short"),
+		Long:  T("resubmit.long"),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			result, err := v.handleResubmit(cmd.Context(), args[0], &opts)
+			if err != nil || result == nil || !opts.Watch {
+				return err
+			}
+			return v.watchVerdict(cmd.Context(), result, result["submission_id"].(string), opts.ContestID, opts.ProblemID)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.ContestID, "contest", "c", "", T("flag.resubmit_contest"))
+	cmd.Flags().StringVarP(&opts.ProblemID, "problem", "p", "", T("flag.resubmit_problem"))
+	cmd.Flags().StringVarP(&opts.Language, "language", "l", "", T("flag.language"))
+	cmd.Flags().BoolVarP(&opts.Watch, "watch", "w", false, T("flag.watch"))
+	addCopyFlag(cmd, &opts.Copy, v.config)
+	return cmd
+}
+
+// Код берется с сервера, контест, задача и язык - из флагов, ответа сервера
+// или локальной базы, в таком порядке
+func (v *VSCodeExtension) handleResubmit(ctx context.Context, submissionID string, opts *SubmitOptions) (map[string]interface{}, error) {
+	if !v.apiClient.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	submissionID = cleanSubmissionID(submissionID)
+	progress(T("resubmit.loading", submissionID))
+	source, err := v.apiClient.GetSubmissionSource(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	language := ""
+	if source.Language != "" {
+		// Язык сервера ("c++17", "python3") приводим к именам submit
+		language = v.apiClient.DetectLanguage("solution" + sourceExtension(source.Language))
+	}
+	contestID, problemID := source.ContestID, source.ProblemID
+	if id, err := strconv.Atoi(submissionID); err == nil && (contestID == "" || problemID == "" || language == "" || language == "unknown") {
+		if db, err := OpenSubmissionDB(); err == nil {
+			if local, ok, _ := db.Submission(id); ok {
+				if contestID == "" {
+					contestID = local.ContestID
+				}
+				if problemID == "" && local.ProblemID != 0 {
+					problemID = strconv.Itoa(local.ProblemID)
+				}
+				if (language == "" || language == "unknown") && local.Language != "" {
+					language = local.Language
+				}
+			}
+			db.Close()
+		}
+	}
+
+	if opts.ContestID == "" {
+		opts.ContestID = contestID
+	}
+	if opts.ProblemID == "" {
+		opts.ProblemID = problemID
+	}
+	if opts.Language == "" {
+		opts.Language = language
+		// Язык сервера из прошлой отправки ("c++20") точнее нашего "c++"
+		if source.Language != "" {
+			if exact, err := v.judgeLanguage(ctx, source.Language); err == nil {
+				opts.Language = exact
+			}
+		}
+	}
+	switch {
+	case opts.ContestID == "":
+		return nil, errors.New(T("resubmit.contest_unknown", submissionID))
+	case opts.ProblemID == "":
+		return nil, errors.New(T("resubmit.problem_unknown", submissionID))
+	case opts.Language == "" || opts.Language == "unknown":
+		return nil, errors.New(T("resubmit.language_unknown", submissionID))
+	}
+	language, err = v.judgeLanguage(ctx, opts.Language)
+	if err != nil {
+		return nil, err
+	}
+	opts.Language = language
+
+	return v.sendSolution(ctx, T("submission.label", submissionID), source.Code, *opts), nil
+}
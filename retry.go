@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultMaxRetries  = 3
+	defaultBackoffBase = 500 * time.Millisecond
+	maxBackoffDelay    = 30 * time.Second
+	maxRetryAfterDelay = 60 * time.Second
+)
+
+// Стоит ли повторить запрос: 429, 5xx и сетевые таймауты считаются временными
+func isRetryable(method string, resp *http.Response, err error) bool {
+	idempotent := method == http.MethodGet || method == http.MethodHead
+	if err != nil {
+		// Отправку решения не повторяем после обрыва: сервер мог ее уже принять
+		if !idempotent {
+			return false
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return true
+		}
+		return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return idempotent && resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
+
+// Задержка перед попыткой attempt (с нуля): base * 2^attempt плюс случайная добавка до половины
+func backoffDelay(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		base = defaultBackoffBase
+	}
+	delay := base << attempt
+	if delay <= 0 || delay > maxBackoffDelay {
+		delay = maxBackoffDelay
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+// Retry-After в секундах или в виде HTTP даты
+func retryAfterDelay(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		delay = time.Until(at)
+	} else {
+		return 0, false
+	}
+
+	if delay < 0 {
+		delay = 0
+	}
+	if delay > maxRetryAfterDelay {
+		delay = maxRetryAfterDelay
+	}
+	return delay, true
+}
+
+// Выполняет запрос с повторами при временных ошибках
+func (a *APIClient) doWithRetry(req *http.Request) (*http.Response, error) {
+	maxRetries := a.config.MaxRetries
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if err := a.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		started := time.Now()
+		resp, err := a.roundTrip(req)
+
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		a.usage.record(req.URL.Path, status, err)
+		logRequest(req.Method, req.URL, status, started, err)
+
+		// Отмена (Ctrl+C) - не временная ошибка
+		if attempt >= maxRetries || ctx.Err() != nil || !isRetryable(req.Method, resp, err) {
+			return resp, err
+		}
+
+		next, cloneErr := cloneRequest(req)
+		if cloneErr != nil {
+			return resp, err
+		}
+
+		delay, ok := retryAfterDelay(resp)
+		if !ok {
+			delay = backoffDelay(a.config.BackoffBase, attempt)
+		}
+
+		reason := ""
+		if err != nil {
+			reason = T("retry.network_error")
+		} else {
+			reason = fmt.Sprintf("HTTP %d", status)
+			resp.Body.Close()
+		}
+		progress(T("retry.retrying",
+			req.URL.Path, reason, delay.Seconds(), attempt+1, maxRetries))
+
+		if err := sleepClock(ctx, a.clock, delay); err != nil {
+			return nil, err
+		}
+		req = next
+	}
+}
+
+// Пауза, которую прерывает отмена ctx
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Как собрать и запустить решение на конкретном языке.
+// В командах подставляются {src}, {bin}, {dir} и {class}, а аргумент {flags}
+// заменяется на Flags целиком
+type LanguageRunner struct {
+	Compile []string
+	Flags   []string
+	Run     []string
+}
+
+var defaultRunners = map[string]LanguageRunner{
+	"c++": {
+		Compile: []string{"g++", "{flags}", "-o", "{bin}", "{src}"},
+		Flags:   []string{"-O2", "-std=c++17"},
+		Run:     []string{"{bin}"},
+	},
+	"c": {
+		Compile: []string{"gcc", "{flags}", "-o", "{bin}", "{src}", "-lm"},
+		Flags:   []string{"-O2", "-std=c11"},
+		Run:     []string{"{bin}"},
+	},
+	"go": {
+		Compile: []string{"go", "build", "{flags}", "-o", "{bin}", "{src}"},
+		Run:     []string{"{bin}"},
+	},
+	"rust": {
+		Compile: []string{"rustc", "{flags}", "-o", "{bin}", "{src}"},
+		Flags:   []string{"-O"},
+		Run:     []string{"{bin}"},
+	},
+	"java": {
+		Compile: []string{"javac", "{flags}", "-d", "{dir}", "{src}"},
+		Run:     []string{"java", "-cp", "{dir}", "{class}"},
+	},
+	"python": {
+		Run: []string{"python3", "{src}"},
+	},
+	"javascript": {
+		Run: []string{"node", "{src}"},
+	},
+}
+
+// Настройки языка из раздела languages конфига. Команды - строки,
+// разбиваемые по пробелам, с теми же подстановками, что и LanguageRunner
+type LanguageConfig struct {
+	Compiler string `mapstructure:"compiler" yaml:"compiler,omitempty"` // Компилятор (или интерпретатор) вместо стандартного
+	Flags    string `mapstructure:"flags" yaml:"flags,omitempty"`       // Флаги компиляции вместо стандартных
+	Compile  string `mapstructure:"compile" yaml:"compile,omitempty"`   // Вся команда сборки, например "clang++ {flags} -o {bin} {src}"
+	Run      string `mapstructure:"run" yaml:"run,omitempty"`           // Вся команда запуска, например "pypy3 {src}"
+
+	Extensions []string `mapstructure:"extensions" yaml:"extensions,omitempty"` // Расширения файлов этого языка, например [".kt"]
+	Judge      string   `mapstructure:"judge" yaml:"judge,omitempty"`           // ID языка на сервере для submit, например "c++20"
+	Include    []string `mapstructure:"include" yaml:"include,omitempty"`       // Каталоги заголовков для sortme bundle (C/C++)
+}
+
+// Стандартный способ запуска языка с поправками из конфига. Языки без
+// стандартного способа можно добавить, указав в конфиге run (и compile)
+func resolveRunner(language string, languages map[string]LanguageConfig) (LanguageRunner, error) {
+	runner, known := defaultRunners[language]
+	custom, configured := languages[strings.ToLower(language)]
+	if !known && !configured {
+		return LanguageRunner{}, errors.New(T("runner.unsupported", language))
+	}
+
+	// Срезы из defaultRunners не меняем на месте
+	runner.Compile = slices.Clone(runner.Compile)
+	runner.Run = slices.Clone(runner.Run)
+	if custom.Compile != "" {
+		runner.Compile = strings.Fields(custom.Compile)
+	}
+	if custom.Flags != "" {
+		runner.Flags = strings.Fields(custom.Flags)
+	}
+	if custom.Run != "" {
+		runner.Run = strings.Fields(custom.Run)
+	}
+	if custom.Compiler != "" {
+		// Для интерпретируемых языков "компилятор" - это интерпретатор
+		if len(runner.Compile) > 0 {
+			runner.Compile[0] = custom.Compiler
+		} else if len(runner.Run) > 0 {
+			runner.Run[0] = custom.Compiler
+		}
+	}
+
+	if len(runner.Run) == 0 {
+		return LanguageRunner{}, errors.New(T("runner.no_run_command", language, language))
+	}
+	return runner, nil
+}
+
+// Подготовленное к запуску решение
+type Solution struct {
+	Language string
+	Source   string
+	workDir  string
+	runCmd   []string
+}
+
+// Результат одного запуска
+type RunResult struct {
+	Output   []byte
+	Stderr   []byte
+	Duration time.Duration
+	PeakRSS  int64 // байты, 0 - неизвестно
+	ExitCode int
+	TimedOut bool
+	Err      error
+}
+
+// Компилирует решение (если нужно) во временный каталог.
+// languages - раздел languages конфига, может быть nil
+func PrepareSolution(source, language string, languages map[string]LanguageConfig) (*Solution, error) {
+	runner, err := resolveRunner(language, languages)
+	if err != nil {
+		return nil, err
+	}
+
+	absSource, err := filepath.Abs(source)
+	if err != nil {
+		return nil, err
+	}
+
+	workDir, err := os.MkdirTemp("", "sortme-run-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+
+	bin := filepath.Join(workDir, "solution")
+	if runtime.GOOS == "windows" {
+		bin += ".exe"
+	}
+	vars := map[string]string{
+		"{src}":   absSource,
+		"{bin}":   bin,
+		"{dir}":   workDir,
+		"{class}": strings.TrimSuffix(filepath.Base(source), filepath.Ext(source)),
+	}
+
+	solution := &Solution{
+		Language: language,
+		Source:   absSource,
+		workDir:  workDir,
+		runCmd:   expandRunnerArgs(runner.Run, runner.Flags, vars),
+	}
+
+	if len(runner.Compile) > 0 {
+		args := expandRunnerArgs(runner.Compile, runner.Flags, vars)
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = filepath.Dir(absSource)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			solution.Cleanup()
+			return nil, &CompileError{Output: string(output), Err: err}
+		}
+	}
+
+	return solution, nil
+}
+
+// Ошибка компиляции с выводом компилятора
+type CompileError struct {
+	Output string
+	Err    error
+}
+
+func (e *CompileError) Error() string {
+	return T("runner.compile_error", e.Err)
+}
+
+func expandRunnerArgs(args, flags []string, vars map[string]string) []string {
+	result := make([]string, 0, len(args)+len(flags))
+	for _, arg := range args {
+		if arg == "{flags}" {
+			result = append(result, flags...)
+			continue
+		}
+		for key, value := range vars {
+			arg = strings.ReplaceAll(arg, key, value)
+		}
+		result = append(result, arg)
+	}
+	return result
+}
+
+// Запускает решение на входных данных с ограничением по времени.
+// args добавляются к командной строке (например, seed для генератора)
+func (s *Solution) Run(input []byte, timeout time.Duration, args ...string) *RunResult {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	argv := append(append([]string(nil), s.runCmd[1:]...), args...)
+	cmd := exec.CommandContext(ctx, s.runCmd[0], argv...)
+	cmd.Dir = filepath.Dir(s.Source)
+	cmd.Stdin = bytes.NewReader(input)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	result := &RunResult{
+		Output:   stdout.Bytes(),
+		Stderr:   stderr.Bytes(),
+		Duration: time.Since(start),
+	}
+	if cmd.ProcessState != nil {
+		result.PeakRSS, _ = peakRSS(cmd.ProcessState)
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		result.TimedOut = true
+		return result
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.ExitCode = exitErr.ExitCode()
+	} else if err != nil {
+		result.Err = err
+	}
+	return result
+}
+
+func (s *Solution) Cleanup() {
+	if s.workDir != "" {
+		os.RemoveAll(s.workDir)
+	}
+}
+
+// Тест: входной файл и (необязательный) файл с ответом
+type TestCase struct {
+	Name       string
+	InputPath  string
+	AnswerPath string
+}
+
+// Ищет пары name.in / name.out (или name.ans) в каталоге
+func loadTestCases(dir string) ([]TestCase, error) {
+	inputs, err := filepath.Glob(filepath.Join(dir, "*.in"))
+	if err != nil {
+		return nil, err
+	}
+
+	var tests []TestCase
+	for _, input := range inputs {
+		base := strings.TrimSuffix(input, ".in")
+		test := TestCase{Name: filepath.Base(base), InputPath: input}
+		for _, ext := range []string{".out", ".ans"} {
+			if _, err := os.Stat(base + ext); err == nil {
+				test.AnswerPath = base + ext
+				break
+			}
+		}
+		tests = append(tests, test)
+	}
+
+	sort.Slice(tests, func(i, j int) bool {
+		return naturalLess(tests[i].Name, tests[j].Name)
+	})
+	return tests, nil
+}
+
+// Сравнение имен с учетом чисел: sample2 < sample10
+func naturalLess(a, b string) bool {
+	ai, bi := 0, 0
+	for ai < len(a) && bi < len(b) {
+		ca, cb := a[ai], b[bi]
+		if isDigit(ca) && isDigit(cb) {
+			aj, bj := ai, bi
+			for aj < len(a) && isDigit(a[aj]) {
+				aj++
+			}
+			for bj < len(b) && isDigit(b[bj]) {
+				bj++
+			}
+			na := strings.TrimLeft(a[ai:aj], "0")
+			nb := strings.TrimLeft(b[bi:bj], "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			ai, bi = aj, bj
+			continue
+		}
+		if ca != cb {
+			return ca < cb
+		}
+		ai++
+		bi++
+	}
+	return len(a)-ai < len(b)-bi
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Разбивает вывод на строки без хвостовых пробелов и пустых строк в конце
+func normalizeOutputLines(output []byte) []string {
+	text := strings.ReplaceAll(string(output), "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Сравнение как у большинства чекеров: игнорируем пробелы в конце строк и пустые строки в конце
+func outputsMatch(expected, actual []byte) bool {
+	exp := normalizeOutputLines(expected)
+	act := normalizeOutputLines(actual)
+	if len(exp) != len(act) {
+		return false
+	}
+	for i := range exp {
+		if exp[i] != act[i] {
+			return false
+		}
+	}
+	return true
+}
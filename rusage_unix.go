@@ -0,0 +1,22 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// Пиковый RSS завершившегося процесса в байтах
+func peakRSS(state *os.ProcessState) (int64, bool) {
+	usage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok || usage == nil {
+		return 0, false
+	}
+	// Linux и BSD отдают килобайты, macOS - байты
+	if runtime.GOOS == "darwin" {
+		return int64(usage.Maxrss), true
+	}
+	return int64(usage.Maxrss) * 1024, true
+}
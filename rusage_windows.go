@@ -0,0 +1,10 @@
+//go:build windows
+
+package main
+
+import "os"
+
+// Windows не сообщает память процесса после его завершения
+func peakRSS(state *os.ProcessState) (int64, bool) {
+	return 0, false
+}
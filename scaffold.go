@@ -0,0 +1,354 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Заготовка чтения входных данных по разделу "Входные данные" условия.
+// Разбор эвристический: распознаем самые частые формулировки
+// ("число n", "n — количество ...", "a_1, a_2, …, a_n", "строка s")
+// и количество тестов, остальное пользователь допишет сам.
+
+type ioValueType int
+
+const (
+	ioInt ioValueType = iota
+	ioFloat
+	ioString
+)
+
+type ioItem struct {
+	Name  string
+	Type  ioValueType
+	Size  string // для массива - выражение длины ("n", "n-1", "5")
+	Array bool
+}
+
+// Разобранный формат ввода
+type ioSpec struct {
+	TestsVar string   // переменная с количеством тестов, если есть
+	Items    []ioItem // что читается (внутри цикла по тестам, если он есть)
+}
+
+var (
+	reMathNoise = regexp.MustCompile(`\\[,;!: ]|[{}$*` + "`" + `]|\\[()\[\]]`)
+	reMathOp    = regexp.MustCompile(`\\(?:leq?|geq?|ne|lt|gt|cdot|times|le|ge)\b|[≤≥≠<>]=?`)
+	reEllipsis  = regexp.MustCompile(`\\[lc]?dots|\.\.\.|…`)
+	reSentence  = regexp.MustCompile(`\.\s+|\n+|;`)
+
+	// a_1, a_2, …, a_n  /  a_1 … a_n  /  a_0, …, a_n-1
+	reIOArray = regexp.MustCompile(`([a-z][a-z0-9]*)_[01]\s*,?\s*(?:[a-z][a-z0-9]*_[12]\s*,?\s*)?…\s*,?\s*[a-z][a-z0-9]*_([a-z][a-z0-9]*(?:\s*[-+]\s*\d+)?|\d+)`)
+	// t — количество тестов / number of test cases t
+	reIOTestsDash = regexp.MustCompile(`(?:^|[\s,(])([a-z])\s*(?:\([^)]*\))?\s*[—–-]+\s*(?:количество|число|the number of|number of)\s+(?:тест|набор|test)`)
+	reIOTestsWord = regexp.MustCompile(`(?:количество|число|number of)\s+(?:тестов|тестовых случаев|наборов(?: входных данных)?|test cases|tests)\s+([a-z])\b`)
+	// n (1 ≤ n ≤ 10^5) — количество ...
+	reIODash = regexp.MustCompile(`(?:^|[\s,(])([a-z][a-z0-9]{0,2})\s*(?:\([^)]*\))?\s*[—–]`)
+	// целые числа n и m / integers n, m and k
+	reIOScalars = regexp.MustCompile(`(?:числ[оа]|чисел|integers?|numbers?)\s+([a-z][a-z0-9]{0,2}(?:\s*(?:,|\sи\s|\sand\s)\s*[a-z][a-z0-9]{0,2})*)(?:[^a-z0-9_]|$)`)
+	reIOString  = regexp.MustCompile(`(?:строк[аиу]|string)\s+([a-z][a-z0-9]{0,2})(?:[^a-z0-9_]|$)`)
+	reIONameSep = regexp.MustCompile(`\s*(?:,|\sи\s|\sand\s)\s*`)
+	reIOIdent   = regexp.MustCompile(`^[a-z][a-z0-9]*`)
+)
+
+// Имена, занятые в заготовках, ключевые слова и короткие английские слова из текста условия
+var ioReservedNames = map[string]bool{
+	"i": true, "in": true, "out": true, "tc": true, "if": true, "do": true, "for": true, "int": true,
+	"go": true, "new": true, "var": true, "len": true, "max": true, "min": true,
+	"is": true, "or": true, "and": true, "the": true, "of": true, "to": true, "on": true, "by": true,
+	"at": true, "an": true, "as": true, "be": true, "it": true, "no": true, "per": true, "are": true,
+}
+
+func normalizeMathText(s string) string {
+	s = reEllipsis.ReplaceAllString(s, "…")
+	s = reMathOp.ReplaceAllString(s, " ")
+	s = reMathNoise.ReplaceAllString(s, "")
+	s = strings.ReplaceAll(s, `\`, "")
+	return strings.ToLower(s)
+}
+
+func ioTypeOf(sentence string) ioValueType {
+	for _, word := range []string{"веществен", "дробн", "real", "float", "decimal"} {
+		if strings.Contains(sentence, word) {
+			return ioFloat
+		}
+	}
+	return ioInt
+}
+
+// Разбирает раздел входных данных условия
+func parseInputSpec(input string) ioSpec {
+	var spec ioSpec
+	declared := make(map[string]bool)
+
+	text := normalizeMathText(htmlToMarkdown(input))
+
+	type found struct {
+		pos  int
+		item ioItem
+	}
+
+	for _, sentence := range reSentence.Split(text, -1) {
+		var items []found
+		typ := ioTypeOf(sentence)
+
+		// Массивы и количество тестов вырезаем, чтобы a_1 не нашлось как число a
+		rest := []byte(sentence)
+		blank := func(from, to int) {
+			for i := from; i < to; i++ {
+				rest[i] = ' '
+			}
+		}
+
+		for _, m := range reIOArray.FindAllStringSubmatchIndex(sentence, -1) {
+			name := sentence[m[2]:m[3]]
+			size := strings.ReplaceAll(sentence[m[4]:m[5]], " ", "")
+			items = append(items, found{m[0], ioItem{Name: name, Type: typ, Size: size, Array: true}})
+			blank(m[0], m[1])
+		}
+
+		if spec.TestsVar == "" {
+			for _, re := range []*regexp.Regexp{reIOTestsDash, reIOTestsWord} {
+				if m := re.FindSubmatchIndex(rest); m != nil {
+					spec.TestsVar = string(rest[m[2]:m[3]])
+					declared[spec.TestsVar] = true
+					blank(m[0], m[1])
+					break
+				}
+			}
+		}
+
+		for _, m := range reIOString.FindAllSubmatchIndex(rest, -1) {
+			items = append(items, found{m[2], ioItem{Name: string(rest[m[2]:m[3]]), Type: ioString}})
+			blank(m[0], m[1])
+		}
+		for _, m := range reIOScalars.FindAllSubmatchIndex(rest, -1) {
+			offset := m[2]
+			for _, name := range reIONameSep.Split(string(rest[m[2]:m[3]]), -1) {
+				items = append(items, found{offset, ioItem{Name: name, Type: typ}})
+				offset++
+			}
+			blank(m[0], m[1])
+		}
+		for _, m := range reIODash.FindAllSubmatchIndex(rest, -1) {
+			items = append(items, found{m[2], ioItem{Name: string(rest[m[2]:m[3]]), Type: typ}})
+		}
+
+		sort.SliceStable(items, func(i, j int) bool { return items[i].pos < items[j].pos })
+
+		for _, f := range items {
+			item := f.item
+			if item.Name == "" || ioReservedNames[item.Name] || declared[item.Name] {
+				continue
+			}
+			// Длина массива должна быть прочитана раньше самого массива
+			if item.Array {
+				sizeVar := reIOIdent.FindString(item.Size)
+				if sizeVar != "" && !declared[sizeVar] && !ioReservedNames[sizeVar] {
+					spec.Items = append(spec.Items, ioItem{Name: sizeVar, Type: ioInt})
+					declared[sizeVar] = true
+				}
+			}
+			spec.Items = append(spec.Items, item)
+			declared[item.Name] = true
+		}
+	}
+
+	return spec
+}
+
+// Соседние скалярные значения одного типа читаются одной строкой: cin >> n >> m
+func groupItems(items []ioItem) [][]ioItem {
+	var groups [][]ioItem
+	for _, item := range items {
+		last := len(groups) - 1
+		if !item.Array && last >= 0 && !groups[last][0].Array && groups[last][0].Type == item.Type {
+			groups[last] = append(groups[last], item)
+			continue
+		}
+		groups = append(groups, []ioItem{item})
+	}
+	return groups
+}
+
+func itemNames(items []ioItem, prefix, sep string) string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = prefix + item.Name
+	}
+	return strings.Join(names, sep)
+}
+
+// Расширение файла решения для языков, под которые умеем делать заготовку
+var scaffoldExtensions = map[string]string{
+	"c++":    ".cpp",
+	"python": ".py",
+	"go":     ".go",
+	"java":   ".java",
+}
+
+// Текст заготовки решения. className нужен только для Java
+func (spec ioSpec) Scaffold(language, header, className string) (string, error) {
+	switch language {
+	case "c++":
+		return spec.scaffoldCpp(header), nil
+	case "python":
+		return spec.scaffoldPython(header), nil
+	case "go":
+		return spec.scaffoldGo(header), nil
+	case "java":
+		return spec.scaffoldJava(header, className), nil
+	}
+	return "", errors.New(T("workspace.template_unsupported", language))
+}
+
+func (spec ioSpec) scaffoldCpp(header string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n#include <bits/stdc++.h>\nusing namespace std;\n\n", header)
+	b.WriteString("int main() {\n    ios::sync_with_stdio(false);\n    cin.tie(nullptr);\n\n")
+
+	indent := "    "
+	if spec.TestsVar != "" {
+		fmt.Fprintf(&b, "    int %s;\n    cin >> %s;\n    while (%s--) {\n", spec.TestsVar, spec.TestsVar, spec.TestsVar)
+		indent = "        "
+	}
+
+	cppType := map[ioValueType]string{ioInt: "long long", ioFloat: "double", ioString: "string"}
+	for _, group := range groupItems(spec.Items) {
+		item := group[0]
+		if item.Array {
+			fmt.Fprintf(&b, "%svector<%s> %s(%s);\n", indent, cppType[item.Type], item.Name, item.Size)
+			fmt.Fprintf(&b, "%sfor (auto &elem : %s) cin >> elem;\n", indent, item.Name)
+		} else {
+			fmt.Fprintf(&b, "%s%s %s;\n%scin >> %s;\n", indent, cppType[item.Type], itemNames(group, "", ", "), indent, itemNames(group, "", " >> "))
+		}
+	}
+	fmt.Fprint(&b, T("scaffold.todo", indent))
+
+	if spec.TestsVar != "" {
+		b.WriteString("    }\n")
+	}
+	b.WriteString("    return 0;\n}\n")
+	return b.String()
+}
+
+func (spec ioSpec) scaffoldPython(header string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\nimport sys\n\n\ndef main():\n    tokens = iter(sys.stdin.read().split())\n", header)
+
+	indent := "    "
+	if spec.TestsVar != "" {
+		fmt.Fprintf(&b, "    %s = int(next(tokens))\n    for _ in range(%s):\n", spec.TestsVar, spec.TestsVar)
+		indent = "        "
+	}
+
+	pyRead := map[ioValueType]string{ioInt: "int(next(tokens))", ioFloat: "float(next(tokens))", ioString: "next(tokens)"}
+	for _, item := range spec.Items {
+		if item.Array {
+			fmt.Fprintf(&b, "%s%s = [%s for _ in range(%s)]\n", indent, item.Name, pyRead[item.Type], item.Size)
+		} else {
+			fmt.Fprintf(&b, "%s%s = %s\n", indent, item.Name, pyRead[item.Type])
+		}
+	}
+	fmt.Fprint(&b, T("scaffold.todo_python", indent, indent))
+
+	b.WriteString("\n\nif __name__ == \"__main__\":\n    main()\n")
+	return b.String()
+}
+
+func (spec ioSpec) scaffoldGo(header string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\npackage main\n\nimport (\n\t\"bufio\"\n\t\"fmt\"\n\t\"os\"\n)\n\n", header)
+	b.WriteString("func main() {\n\tin := bufio.NewReader(os.Stdin)\n\tout := bufio.NewWriter(os.Stdout)\n\tdefer out.Flush()\n\n")
+
+	indent := "\t"
+	if spec.TestsVar != "" {
+		fmt.Fprintf(&b, "\tvar %s int\n\tfmt.Fscan(in, &%s)\n\tfor ; %s > 0; %s-- {\n", spec.TestsVar, spec.TestsVar, spec.TestsVar, spec.TestsVar)
+		indent = "\t\t"
+	}
+
+	goType := map[ioValueType]string{ioInt: "int", ioFloat: "float64", ioString: "string"}
+	for _, group := range groupItems(spec.Items) {
+		item := group[0]
+		if item.Array {
+			fmt.Fprintf(&b, "%s%s := make([]%s, %s)\n", indent, item.Name, goType[item.Type], item.Size)
+			fmt.Fprintf(&b, "%sfor i := range %s {\n%s\tfmt.Fscan(in, &%s[i])\n%s}\n", indent, item.Name, indent, item.Name, indent)
+		} else {
+			fmt.Fprintf(&b, "%svar %s %s\n%sfmt.Fscan(in, %s)\n", indent, itemNames(group, "", ", "), goType[item.Type], indent, itemNames(group, "&", ", "))
+		}
+	}
+
+	fmt.Fprint(&b, T("scaffold.todo", indent))
+	// Иначе заготовка не скомпилируется из-за неиспользуемых переменных
+	if len(spec.Items) > 0 {
+		blanks := strings.TrimSuffix(strings.Repeat("_, ", len(spec.Items)), ", ")
+		fmt.Fprintf(&b, "%s%s = %s\n", indent, blanks, itemNames(spec.Items, "", ", "))
+	}
+
+	if spec.TestsVar != "" {
+		b.WriteString("\t}\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func (spec ioSpec) scaffoldJava(header, className string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\nimport java.io.*;\nimport java.util.*;\n\npublic class %s {\n", header, className)
+	b.WriteString("    static BufferedReader in = new BufferedReader(new InputStreamReader(System.in));\n")
+	b.WriteString("    static StringTokenizer tokens = new StringTokenizer(\"\");\n\n")
+	b.WriteString("    static String next() throws IOException {\n")
+	b.WriteString("        while (!tokens.hasMoreTokens()) tokens = new StringTokenizer(in.readLine());\n")
+	b.WriteString("        return tokens.nextToken();\n    }\n\n")
+	b.WriteString("    public static void main(String[] args) throws IOException {\n")
+
+	indent := "        "
+	if spec.TestsVar != "" {
+		fmt.Fprintf(&b, "        int %s = Integer.parseInt(next());\n        for (int tc = 0; tc < %s; tc++) {\n", spec.TestsVar, spec.TestsVar)
+		indent = "            "
+	}
+
+	javaType := map[ioValueType]string{ioInt: "long", ioFloat: "double", ioString: "String"}
+	javaRead := map[ioValueType]string{ioInt: "Long.parseLong(next())", ioFloat: "Double.parseDouble(next())", ioString: "next()"}
+	for _, item := range spec.Items {
+		if item.Array {
+			fmt.Fprintf(&b, "%s%s[] %s = new %s[(int) (%s)];\n", indent, javaType[item.Type], item.Name, javaType[item.Type], item.Size)
+			fmt.Fprintf(&b, "%sfor (int i = 0; i < %s.length; i++) %s[i] = %s;\n", indent, item.Name, item.Name, javaRead[item.Type])
+		} else {
+			fmt.Fprintf(&b, "%s%s %s = %s;\n", indent, javaType[item.Type], item.Name, javaRead[item.Type])
+		}
+	}
+	fmt.Fprint(&b, T("scaffold.todo", indent))
+
+	if spec.TestsVar != "" {
+		b.WriteString("        }\n")
+	}
+	b.WriteString("    }\n}\n")
+	return b.String()
+}
+
+// Создает problem_<id>.<ext> с заготовкой чтения ввода. Уже начатое решение не перезаписываем
+func writeScaffold(statement *TaskStatement, problemID, dir, language string) (string, error) {
+	ext, ok := scaffoldExtensions[language]
+	if !ok {
+		return "", errors.New(T("workspace.template_unsupported", language))
+	}
+
+	className := "problem_" + problemID
+	filename := filepath.Join(dir, className+ext)
+	if _, err := os.Stat(filename); err == nil {
+		return "", errors.New(T("workspace.file_exists", filename))
+	}
+
+	header := T("scaffold.header", problemID, statement.Name)
+	code, err := parseInputSpec(statement.Input).Scaffold(language, header, className)
+	if err != nil {
+		return "", err
+	}
+	return filename, os.WriteFile(filename, []byte(code), 0644)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Поиск задач по названию во всех контестах. Задачи берутся из кэша
+// информации о контестах, недостающие контесты загружаются один раз
+// (потом живут в кэше), с --cached поиск идет без сети
+
+type SearchResult struct {
+	ContestID   string `json:"contest_id"`
+	ContestName string `json:"contest_name"`
+	TaskID      int    `json:"task_id"`
+	Letter      string `json:"letter"`
+	Name        string `json:"name"`
+	score       int
+}
+
+func (v *VSCodeExtension) createSearchCommand() *cobra.Command {
+	var cachedOnly bool
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: T("search.short"),
+		Long:  T("search.long"),
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return v.handleSearch(cmd.Context(), strings.Join(args, " "), cachedOnly, limit)
+		},
+	}
+
+	cmd.Flags().BoolVar(&cachedOnly, "cached", false, T("flag.search_cached"))
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, T("flag.search_limit"))
+	return cmd
+}
+
+// Каждое слово запроса входит в название, тогда совпадение ранжируем
+// как в выборе задачи. ID задачи совпадает только целиком
+func matchTask(query string, task Task) (int, bool) {
+	if strconv.Itoa(task.ID) == strings.TrimSpace(query) {
+		return 1000, true
+	}
+	name := strings.ToLower(task.Name)
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(name, word) {
+			return 0, false
+		}
+	}
+	return fuzzyScore(query, task.Name)
+}
+
+// Задачи всех контестов: из кэша, а без --cached - еще и с сервера
+func (v *VSCodeExtension) searchTasks(ctx context.Context, cachedOnly bool) (map[string][]Task, []Contest, error) {
+	// Запросы API печатают свой ход, а контестов может быть сотня:
+	// на время загрузки их вывод скрываем, показываем одну строку
+	stdout := os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer devNull.Close()
+
+	contests := v.cachedContests()
+	if !cachedOnly {
+		// Список контестов сам кэшируется, повторный поиск его не загружает
+		os.Stdout = devNull
+		contests, err = v.apiClient.GetContests(ctx)
+		os.Stdout = stdout
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	tasks := make(map[string][]Task)
+	var missing []Contest
+	for _, contest := range contests {
+		if cached := v.cachedTasks(contest.ID); len(cached) > 0 {
+			tasks[contest.ID] = cached
+		} else {
+			missing = append(missing, contest)
+		}
+	}
+	if cachedOnly || len(missing) == 0 {
+		return tasks, contests, nil
+	}
+
+	progress(T("search.loading", len(missing)))
+	os.Stdout = devNull
+	infos, errs := parallelMap(v.apiClient.workers(), missing, func(contest Contest) (*ContestInfo, error) {
+		return v.apiClient.GetContestInfo(ctx, contest.ID)
+	})
+	os.Stdout = stdout
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	failed := 0
+	for i, info := range infos {
+		if errs[i] != nil {
+			if isFatalAPIError(errs[i]) {
+				return nil, nil, errs[i]
+			}
+			failed++
+			continue
+		}
+		tasks[missing[i].ID] = info.Tasks
+	}
+	if failed > 0 {
+		progress(T("search.load_failed", failed))
+	}
+	return tasks, contests, nil
+}
+
+func (v *VSCodeExtension) handleSearch(ctx context.Context, query string, cachedOnly bool, limit int) error {
+	if !cachedOnly && !v.apiClient.IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+	tasks, contests, err := v.searchTasks(ctx, cachedOnly)
+	if err != nil {
+		return err
+	}
+
+	results := []SearchResult{}
+	for _, contest := range contests {
+		for i, task := range tasks[contest.ID] {
+			if score, ok := matchTask(query, task); ok {
+				results = append(results, SearchResult{
+					ContestID: contest.ID, ContestName: contest.Name,
+					TaskID: task.ID, Letter: taskLetter(i), Name: task.Name, score: score,
+				})
+			}
+		}
+	}
+	// Лучшие совпадения первыми, при равенстве - порядок контестов (новые выше)
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+	total := len(results)
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	v.emitJSON(results)
+	if total == 0 {
+		fmt.Print(T("search.none", query, len(tasks)))
+		if cachedOnly {
+			progressln(T("search.cached_hint"))
+		}
+		return nil
+	}
+
+	fmt.Print(T("search.found", total))
+	fmt.Printf("  %s  %s  %s  %s\n", padRunes(T("search.col_contest"), 8), padRunes(T("search.col_task"), 8), padRunes(T("search.col_name"), 32), T("search.col_contest_name"))
+	for _, r := range results {
+		fmt.Printf("  %s  %s  %s  %s\n", padRunes(r.ContestID, 8), padRunes(strconv.Itoa(r.TaskID), 8),
+			padRunes(r.Letter+". "+r.Name, 32), r.ContestName)
+	}
+	if total > len(results) {
+		fmt.Print(T("search.more", total-len(results)))
+	}
+	best := results[0]
+	progress(T("search.hint", best.ContestID, best.TaskID, best.ContestID, best.TaskID))
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+// Секреты, не связанные с авторизацией на sort-me.org (токен бота для уведомлений и т.п.),
+// хранятся отдельно от конфига в файле с правами 0600, чтобы их можно было менять
+// независимо от session token и не светить при показе конфига
+const secretsStateFile = "secrets.json"
+
+const secretNotifyTelegramToken = "notify_telegram_token"
+
+func loadSecrets() (map[string]string, error) {
+	secrets := make(map[string]string)
+	if err := loadState(secretsStateFile, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+func getSecret(name string) (string, error) {
+	secrets, err := loadSecrets()
+	if err != nil {
+		return "", err
+	}
+	return secrets[name], nil
+}
+
+// Сохраняет секрет, пустое значение удаляет его. Временный файл создается
+// с правами 0600, они сохраняются после переименования
+func setSecret(name, value string) error {
+	if value == "" {
+		if current, err := getSecret(name); err != nil || current == "" {
+			return err
+		}
+	}
+	secrets := make(map[string]string)
+	return updateState(secretsStateFile, &secrets, func() error {
+		if value == "" {
+			delete(secrets, name)
+		} else {
+			secrets[name] = value
+		}
+		return nil
+	})
+}
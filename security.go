@@ -0,0 +1,311 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Небезопасные настройки: токен открытым текстом, отключенная проверка
+// сертификата, токен в переменных окружения. Сводка печатается в stderr не
+// чаще раза в сутки (и сразу, если набор проблем изменился), полный разбор -
+// sortme doctor --security. Отключить сводку: SORTME_NO_SECURITY_WARNINGS=1
+const securityWarningFile = "security_warning.json"
+
+const securityWarningInterval = 24 * time.Hour
+
+type securitySeverity int
+
+const (
+	severityLow securitySeverity = iota
+	severityMedium
+	severityHigh
+)
+
+func (s securitySeverity) String() string {
+	switch s {
+	case severityHigh:
+		return "high"
+	case severityMedium:
+		return "medium"
+	}
+	return "low"
+}
+
+func (s securitySeverity) emoji() string {
+	switch s {
+	case severityHigh:
+		return "🔴"
+	case severityMedium:
+		return "🟠"
+	}
+	return "🟡"
+}
+
+type securityFinding struct {
+	Severity securitySeverity `json:"-"`
+	Level    string           `json:"severity"`
+	Title    string           `json:"title"`
+	Detail   string           `json:"detail"`
+	Fix      []string         `json:"fix"`
+}
+
+// Когда сводка показывалась последний раз и для какого набора проблем
+type securityWarningState struct {
+	Shown  time.Time `json:"shown"`
+	Digest string    `json:"digest"`
+}
+
+// Проверяет настройки, самые опасные проблемы первыми
+func (v *VSCodeExtension) securityFindings() []securityFinding {
+	findings := []securityFinding{}
+	add := func(severity securitySeverity, title, detail string, fix ...string) {
+		findings = append(findings, securityFinding{Severity: severity, Level: severity.String(), Title: title, Detail: detail, Fix: fix})
+	}
+	configFile := configFilePath()
+
+	if u, err := url.Parse(v.config.APIBaseURL); err == nil && u.Scheme == "http" && !isLoopbackHost(u.Hostname()) {
+		add(severityHigh, T("security.http_title"),
+			T("security.http_detail", v.config.APIBaseURL),
+			T("security.http_fix", defaultAPIURL, configFile))
+	}
+
+	if profiles := plaintextTokenProfiles(); len(profiles) > 0 {
+		severity := severityMedium
+		detail := T("security.plaintext_detail", strings.Join(profiles, ", "), configFile)
+		var fix []string
+		if readableByOthers(configFile) {
+			severity = severityHigh
+			detail += T("security.plaintext_readable")
+			fix = append(fix, "chmod 600 "+configFile)
+		}
+		if v.config.TokenStorage == tokenStoragePlaintext {
+			fix = append(fix, T("security.plaintext_fix", configFile))
+		} else {
+			fix = append(fix, T("security.keyring_fix"))
+		}
+		add(severity, T("security.plaintext_title"), detail, fix...)
+	}
+
+	if names := tokenEnvVars(v.config); len(names) > 0 {
+		add(severityMedium, T("security.env_title"),
+			T("security.env_detail", strings.Join(names, ", ")),
+			"unset "+strings.Join(names, " "),
+			T("security.env_fix"))
+	}
+
+	// insecure_tls: true по умолчанию и попадает в конфиг при первом сохранении,
+	// поэтому опасным считается только реальный переход на запросы без проверки
+	if v.config.InsecureTLS {
+		severity := severityLow
+		detail := T("security.tls_detail")
+		if v.apiClient.transport.useInsecure.Load() {
+			severity = severityHigh
+			detail = T("security.tls_detail_active")
+		}
+		add(severity, T("security.tls_title"), detail,
+			T("security.tls_fix", configFile))
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Severity > findings[j].Severity })
+	return findings
+}
+
+func configFilePath() string {
+	if file := viper.ConfigFileUsed(); file != "" {
+		return file
+	}
+	return filepath.Join(getConfigPath(), "config.yaml")
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Профили, чей токен записан в config.yaml, а не в хранилище паролей
+func plaintextTokenProfiles() []string {
+	var profiles []string
+	if viper.GetString("session_token") != "" {
+		profiles = append(profiles, defaultProfileName)
+	}
+	for name := range viper.GetStringMap("profiles") {
+		if viper.GetString("profiles."+name+".session_token") != "" {
+			profiles = append(profiles, name)
+		}
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+func readableByOthers(path string) bool {
+	if runtime.GOOS == "windows" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().Perm()&0o077 != 0
+}
+
+// Переменные окружения, значение которых совпадает с токеном одного из профилей
+func tokenEnvVars(config *Config) []string {
+	tokens := map[string]bool{}
+	for _, token := range config.persistedTokens() {
+		if len(token) >= 8 {
+			tokens[token] = true
+		}
+	}
+	var names []string
+	for _, env := range os.Environ() {
+		name, value, ok := strings.Cut(env, "=")
+		if ok && tokens[value] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Токены всех профилей, включая активный
+func (c *Config) persistedTokens() []string {
+	creds, profiles := c.persistedCredentials()
+	tokens := []string{c.SessionToken, creds.SessionToken}
+	for _, profile := range profiles {
+		tokens = append(tokens, profile.SessionToken)
+	}
+	return tokens
+}
+
+func securityDigest(findings []securityFinding) string {
+	h := sha256.New()
+	for _, finding := range findings {
+		fmt.Fprintf(h, "%s|%s\n", finding.Level, finding.Title)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// Сводка при запуске команды: только средние и серьезные проблемы
+func (v *VSCodeExtension) warnInsecureSettings() {
+	if os.Getenv("SORTME_NO_SECURITY_WARNINGS") != "" {
+		return
+	}
+	var findings []securityFinding
+	for _, finding := range v.securityFindings() {
+		if finding.Severity >= severityMedium {
+			findings = append(findings, finding)
+		}
+	}
+	if len(findings) == 0 {
+		return
+	}
+
+	digest := securityDigest(findings)
+	var state securityWarningState
+	if loadState(securityWarningFile, &state) == nil && state.Digest == digest && time.Since(state.Shown) < securityWarningInterval {
+		return
+	}
+	saveState(securityWarningFile, securityWarningState{Shown: time.Now(), Digest: digest})
+
+	fmt.Fprintln(os.Stderr, T("security.banner"))
+	for _, finding := range findings {
+		fmt.Fprintf(os.Stderr, "   %s %s: %s\n", finding.Severity.emoji(), finding.Title, finding.Detail)
+		for _, fix := range finding.Fix {
+			fmt.Fprintf(os.Stderr, "      → %s\n", fix)
+		}
+	}
+	fmt.Fprintln(os.Stderr, T("security.banner_hint"))
+}
+
+func (v *VSCodeExtension) createDoctorCommand() *cobra.Command {
+	var security bool
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: T("doctor.short"),
+		Long:  T("doctor.long"),
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if security {
+				v.handleSecurityReview()
+				return
+			}
+			v.handleDoctor(cmd.Context())
+		},
+	}
+	cmd.Flags().BoolVar(&security, "security", false, T("flag.doctor_security"))
+	return cmd
+}
+
+// Общая проверка: конфиг, токен, хранилище паролей, доступность API
+func (v *VSCodeExtension) handleDoctor(ctx context.Context) {
+	report := map[string]interface{}{}
+	check := func(key string, ok bool, text string) {
+		mark := "✅"
+		if !ok {
+			mark = "❌"
+		}
+		fmt.Printf("%s %s\n", mark, text)
+		report[key] = ok
+	}
+
+	configFile := configFilePath()
+	_, err := os.Stat(configFile)
+	check("config", err == nil, T("doctor.config", configFile))
+	check("auth", v.apiClient.IsAuthenticated(), T("doctor.token", maskToken(v.config.SessionToken), v.config.profileName()))
+	if v.apiClient.IsAuthenticated() {
+		if v.apiClient.SessionExpired() {
+			check("session", false, T("doctor.session_expired", v.apiClient.reauthHint()))
+		} else {
+			check("session", true, T("doctor.session"))
+		}
+	}
+	if v.config.useKeyring() {
+		check("keyring", keyringAvailable(), T("doctor.keyring"))
+	}
+	_, status, err := v.apiClient.get(ctx, "/getUpcomingContests")
+	apiText := "API: " + v.config.APIBaseURL
+	if err != nil {
+		apiText += fmt.Sprintf(" (%v)", err)
+	} else if status != 200 {
+		apiText += fmt.Sprintf(" (HTTP %d)", status)
+	}
+	check("api", err == nil && status == 200, apiText)
+
+	findings := v.securityFindings()
+	report["security_findings"] = len(findings)
+	v.emitJSON(report)
+	if len(findings) > 0 {
+		fmt.Print(T("security.summary", len(findings)))
+	}
+}
+
+func (v *VSCodeExtension) handleSecurityReview() {
+	findings := v.securityFindings()
+	v.emitJSON(map[string]interface{}{"findings": findings})
+	if len(findings) == 0 {
+		fmt.Println(T("security.none"))
+		return
+	}
+
+	fmt.Print(T("security.header", len(findings)))
+	for _, finding := range findings {
+		fmt.Printf("\n%s [%s] %s\n", finding.Severity.emoji(), finding.Level, finding.Title)
+		fmt.Printf("   %s\n", finding.Detail)
+		for _, fix := range finding.Fix {
+			fmt.Printf("   → %s\n", fix)
+		}
+	}
+}
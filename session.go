@@ -0,0 +1,101 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Истекший или отозванный токен: API отвечает 401 на любой запрос. Ответ
+// замечается в одном месте (doRequest и подключение WebSocket), после него
+// запросы этого запуска сразу завершаются errSessionExpired, а пользователь
+// один раз видит понятное сообщение вместо ошибок HTTP от каждой команды
+const sessionStateFile = "session.json"
+
+var errSessionExpired error = sessionError("session.expired")
+
+type sessionState struct {
+	expired atomic.Bool
+	once    sync.Once
+}
+
+// Отметка о недействительном токене между запусками. Хранится хэш, чтобы
+// новый токен после sortme auth не считался истекшим
+type expiredSession struct {
+	TokenHash string    `json:"token_hash"`
+	Since     time.Time `json:"since"`
+}
+
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Проверяет ответ API. true - токен отвергнут, ответ закрыт
+func (a *APIClient) checkUnauthorized(resp *http.Response) bool {
+	if resp == nil || resp.StatusCode != http.StatusUnauthorized || a.config.SessionToken == "" {
+		return false
+	}
+	resp.Body.Close()
+	a.markSessionExpired()
+	return true
+}
+
+func (a *APIClient) markSessionExpired() {
+	a.session.expired.Store(true)
+	a.session.once.Do(func() {
+		fmt.Fprint(os.Stderr, T("session.rejected", a.reauthHint()))
+		saveState(sessionStateFile, expiredSession{TokenHash: tokenHash(a.config.SessionToken), Since: time.Now()})
+	})
+}
+
+// Команда входа для текущего профиля
+func (a *APIClient) reauthHint() string {
+	hint := "sortme auth"
+	if a.config.profile != "" {
+		hint += " --profile " + a.config.profile
+	}
+	return hint
+}
+
+// Получал ли текущий токен 401 (в этом или прошлых запусках).
+// Запросы по отметке не блокируются: whoami и doctor только сообщают о ней
+func (a *APIClient) SessionExpired() bool {
+	if a.session.expired.Load() {
+		return true
+	}
+	var mark expiredSession
+	if a.config.SessionToken == "" || loadState(sessionStateFile, &mark) != nil {
+		return false
+	}
+	return mark.TokenHash == tokenHash(a.config.SessionToken)
+}
+
+// Сервер снова принял токен: отметка прошлых запусков больше не нужна
+func (a *APIClient) clearSessionExpired() {
+	if err := os.Remove(getStatePath(sessionStateFile)); err != nil && !os.IsNotExist(err) {
+		logger.Debug("не удалось удалить отметку сессии", "error", err)
+	}
+}
+
+// После команды, упавшей на истекшем токене, предлагает войти заново
+func (v *VSCodeExtension) offerReauth() {
+	if !v.apiClient.session.expired.Load() || v.jsonMode() {
+		return
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return
+	}
+	if !askConfirmation(T("session.relogin_prompt")) {
+		return
+	}
+	v.saveAuth(promptCredentials(), false)
+	progressln(T("session.retry_hint"))
+}
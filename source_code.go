@@ -0,0 +1,180 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Исходный код отправки. Код после отправки не меняется, поэтому кэшируется надолго
+type SubmissionSource struct {
+	ID        string `json:"id"`
+	Code      string `json:"code"`
+	Language  string `json:"language,omitempty"`
+	ContestID string `json:"contest_id,omitempty"`
+	ProblemID string `json:"problem_id,omitempty"`
+}
+
+// Кандидаты endpoint для кода отправки, {id} заменяется на ID
+var submissionSourceEndpoints = []string{
+	"/getSubmissionCode?id={id}",
+	"/submission/{id}/code",
+	"/getSubmission?id={id}",
+	"/submission/{id}",
+}
+
+func (a *APIClient) GetSubmissionSource(ctx context.Context, submissionID string) (*SubmissionSource, error) {
+	if !a.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	submissionID = cleanSubmissionID(submissionID)
+	if submissionID == "" {
+		return nil, errors.New(T("source.empty_id"))
+	}
+
+	return cached(a, "sources/"+submissionID, ttlSubmissionSource, func() (*SubmissionSource, error) {
+		var lastErr error
+		for _, template := range orderEndpoints("submission_source", submissionSourceEndpoints) {
+			endpoint := strings.ReplaceAll(template, "{id}", submissionID)
+			body, status, err := a.get(ctx, endpoint)
+			if isFatalAPIError(err) {
+				return nil, err
+			}
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if status != http.StatusOK {
+				lastErr = newAPIError(status, body)
+				continue
+			}
+			source, err := parseSubmissionSource(body)
+			if err != nil {
+				// Ответ без кода (например, только статус) - пробуем следующий вариант
+				lastErr = err
+				continue
+			}
+			source.ID = submissionID
+			rememberEndpoint("submission_source", template)
+			return source, nil
+		}
+		return nil, fmt.Errorf("%s: %w", T("source.unavailable", submissionID), cmp.Or(lastErr, ErrNotFound))
+	})
+}
+
+// Код лежит в одном из полей code/source/source_code, на верхнем уровне
+// или внутри submission/data
+func parseSubmissionSource(body []byte) (*SubmissionSource, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"submission", "data"} {
+		var inner map[string]json.RawMessage
+		if json.Unmarshal(raw[key], &inner) == nil && inner != nil {
+			raw = inner
+			break
+		}
+	}
+
+	source := &SubmissionSource{}
+	for _, key := range []string{"code", "source", "source_code", "solution"} {
+		if json.Unmarshal(raw[key], &source.Code) == nil && source.Code != "" {
+			break
+		}
+	}
+	if source.Code == "" {
+		return nil, errors.New(T("source.no_code"))
+	}
+	for _, key := range []string{"lang", "language"} {
+		if json.Unmarshal(raw[key], &source.Language) == nil && source.Language != "" {
+			break
+		}
+	}
+	for _, key := range []string{"contest_id", "contestid", "contest"} {
+		if id, ok := jsonInt(raw[key]); ok && id > 0 {
+			source.ContestID = strconv.Itoa(id)
+			break
+		}
+	}
+	for _, key := range []string{"task_id", "problem_id", "taskid", "task"} {
+		if id, ok := jsonInt(raw[key]); ok && id > 0 {
+			source.ProblemID = strconv.Itoa(id)
+			break
+		}
+	}
+	return source, nil
+}
+
+// Расширение файла по языку сервера: "c++17", "python3", "GNU C++" и т.п.
+func sourceExtension(language string) string {
+	if spec, ok := languageFromServerName(language); ok {
+		return spec.Extensions[0]
+	}
+	return ".txt"
+}
+
+func (v *VSCodeExtension) createCodeCommand() *cobra.Command {
+	var output string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "code <submission_id>",
+		Short: T("code.short"),
+		Long:  T("code.long"),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return v.handleCode(cmd.Context(), args[0], output, force)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", T("flag.code_output"))
+	cmd.Flags().BoolVarP(&force, "force", "f", false, T("flag.code_force"))
+	return cmd
+}
+
+func (v *VSCodeExtension) handleCode(ctx context.Context, submissionID, output string, force bool) error {
+	source, err := v.apiClient.GetSubmissionSource(ctx, submissionID)
+	if err != nil {
+		return err
+	}
+
+	if output == "-" {
+		if v.jsonMode() {
+			v.emitJSON(source)
+			return nil
+		}
+		fmt.Fprint(os.Stdout, ensureTrailingNewline(source.Code))
+		return nil
+	}
+
+	if output == "" {
+		output = "submission_" + source.ID + sourceExtension(source.Language)
+	}
+	if _, err := os.Stat(output); err == nil && !force {
+		return errors.New(T("source.file_exists", output))
+	}
+	if err := os.WriteFile(output, []byte(ensureTrailingNewline(source.Code)), 0644); err != nil {
+		return fmt.Errorf("%s: %w", T("source.write_failed"), err)
+	}
+
+	v.emitJSON(map[string]interface{}{
+		"id":       source.ID,
+		"language": source.Language,
+		"file":     output,
+	})
+	fmt.Print(T("source.saved", source.ID, output))
+	if source.Language != "" {
+		fmt.Print(T("source.language", source.Language))
+	}
+	return nil
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+// Таблица результатов контеста
+type Standings struct {
+	ContestID string
+	Tasks     []Task
+	Rows      []StandingsRow
+}
+
+type StandingsRow struct {
+	Place   int             `json:"place"`
+	Name    string          `json:"name"`
+	UserID  int             `json:"uid"`
+	Total   int             `json:"sum"`
+	Penalty int             `json:"penalty"`
+	Results []StandingsCell `json:"results"`
+}
+
+// Результат участника по одной задаче
+type StandingsCell struct {
+	Points   int
+	Attempts int
+}
+
+// API отдает ячейку либо массивом [баллы, попытки, ...], либо объектом
+func (c *StandingsCell) UnmarshalJSON(data []byte) error {
+	var arr []float64
+	if err := json.Unmarshal(data, &arr); err == nil {
+		if len(arr) > 0 {
+			c.Points = int(arr[0])
+		}
+		if len(arr) > 1 {
+			c.Attempts = int(arr[1])
+		}
+		return nil
+	}
+
+	var obj struct {
+		Points   int `json:"points"`
+		Score    int `json:"score"`
+		Attempts int `json:"attempts"`
+		Tries    int `json:"tries"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	c.Points = obj.Points + obj.Score
+	c.Attempts = obj.Attempts + obj.Tries
+	return nil
+}
+
+func (a *APIClient) GetStandings(ctx context.Context, contestID string) (*Standings, error) {
+	if !a.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	body, status, err := a.get(ctx, fmt.Sprintf("/getContestTable?contestid=%s", contestID))
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, newAPIError(status, body)
+	}
+
+	// Встречаются оба варианта названия поля с таблицей
+	var response struct {
+		Tasks []Task         `json:"tasks"`
+		Rows  []StandingsRow `json:"rows"`
+		Table []StandingsRow `json:"table"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		a.quarantine("standings", "/getContestTable?contestid="+contestID, body, err)
+		return nil, fmt.Errorf("%s: %w", T("standings.parse_error"), err)
+	}
+
+	rows := response.Rows
+	if len(rows) == 0 {
+		rows = response.Table
+	}
+
+	// Если место не пришло, считаем его по порядку строк
+	for i := range rows {
+		if rows[i].Place == 0 {
+			rows[i].Place = i + 1
+		}
+	}
+
+	return &Standings{
+		ContestID: contestID,
+		Tasks:     response.Tasks,
+		Rows:      rows,
+	}, nil
+}
+
+// Строка текущего пользователя (nil если его нет в таблице)
+func (s *Standings) FindUser(username string) *StandingsRow {
+	for i := range s.Rows {
+		if username != "" && strings.EqualFold(s.Rows[i].Name, username) {
+			return &s.Rows[i]
+		}
+	}
+	return nil
+}
+
+// Строка пользователя по ID из конфига, а если его нет - по имени
+func (v *VSCodeExtension) findMyRow(s *Standings) *StandingsRow {
+	if uid, err := strconv.Atoi(v.config.UserID); err == nil && uid != 0 {
+		for i := range s.Rows {
+			if s.Rows[i].UserID == uid {
+				return &s.Rows[i]
+			}
+		}
+	}
+	return s.FindUser(v.config.Username)
+}
+
+// Число задач: из списка задач, а если его нет - по самой длинной строке
+func (s *Standings) TaskCount() int {
+	count := len(s.Tasks)
+	for _, row := range s.Rows {
+		count = max(count, len(row.Results))
+	}
+	return count
+}
+
+// Популярность задачи по таблице: сколько участников набрали по ней
+// лучший результат контеста и сколько хотя бы пытались
+type TaskPopularity struct {
+	Solved int `json:"solved"`
+	Tried  int `json:"tried"`
+}
+
+// Популярность каждой из первых count задач
+func (s *Standings) Popularity(count int) []TaskPopularity {
+	best := make([]int, count)
+	for _, row := range s.Rows {
+		for task := 0; task < count && task < len(row.Results); task++ {
+			best[task] = max(best[task], row.Results[task].Points)
+		}
+	}
+
+	popularity := make([]TaskPopularity, count)
+	for _, row := range s.Rows {
+		for task := 0; task < count && task < len(row.Results); task++ {
+			cell := row.Results[task]
+			if cell.Points > 0 || cell.Attempts > 0 {
+				popularity[task].Tried++
+			}
+			if best[task] > 0 && cell.Points == best[task] {
+				popularity[task].Solved++
+			}
+		}
+	}
+	return popularity
+}
+
+func (v *VSCodeExtension) createStandingsCommand() *cobra.Command {
+	var widget bool
+	var refresh time.Duration
+	var output string
+	var opts StandingsOptions
+
+	cmd := &cobra.Command{
+		Use:   "standings [contest_id]",
+		Short: T("standings.short"),
+		Long:  T("standings.long"),
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			contestID := v.config.CurrentContest
+			if len(args) > 0 {
+				contestID = args[0]
+			}
+			if contestID == "" {
+				fmt.Println("❌ " + T("contest.missing"))
+				progressln(T("standings.contest_hint"))
+				return
+			}
+			if !v.apiClient.IsAuthenticated() {
+				fmt.Println("❌ " + T("auth.required"))
+				return
+			}
+
+			if widget {
+				v.runStandingsWidget(cmd.Context(), contestID, refresh, output)
+				return
+			}
+			v.handleStandings(cmd.Context(), contestID, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&widget, "widget", false, T("flag.standings_widget"))
+	cmd.Flags().DurationVar(&refresh, "refresh", 0, T("flag.standings_refresh"))
+	cmd.Flags().StringVarP(&output, "output", "o", "", T("flag.standings_output"))
+	cmd.Flags().BoolVar(&opts.Me, "me", false, T("flag.standings_me"))
+	cmd.Flags().IntVar(&opts.Top, "top", 0, T("flag.standings_top"))
+
+	return cmd
+}
+
+type StandingsOptions struct {
+	Me  bool // Выделить строку текущего пользователя
+	Top int  // Сколько первых строк показать (0 - все)
+}
+
+func (v *VSCodeExtension) handleStandings(ctx context.Context, contestID string, opts StandingsOptions) {
+	standings, err := v.apiClient.GetStandings(ctx, contestID)
+	if err != nil {
+		v.fail(T("standings.fetch_failed", err))
+		return
+	}
+
+	var me *StandingsRow
+	if opts.Me {
+		me = v.findMyRow(standings)
+	}
+
+	rows := standings.Rows
+	if opts.Top > 0 && opts.Top < len(rows) {
+		rows = rows[:opts.Top]
+	}
+	// Своя строка ниже --top показывается отдельно под таблицей
+	var meBelow *StandingsRow
+	if me != nil && !containsRow(rows, me) {
+		meBelow = me
+	}
+
+	if v.jsonMode() {
+		result := map[string]interface{}{
+			"contest_id":   contestID,
+			"tasks":        standings.Tasks,
+			"participants": len(standings.Rows),
+			"rows":         rows,
+		}
+		if me != nil {
+			result["me"] = me
+		}
+		v.emitJSON(result)
+	}
+
+	if len(standings.Rows) == 0 {
+		fmt.Println(T("standings.empty"))
+		return
+	}
+
+	taskCount := standings.TaskCount()
+	nameWidth := 28
+	border := func(left, mid, right string) string {
+		line := left + strings.Repeat("─", 7) + mid + strings.Repeat("─", nameWidth+2)
+		for i := 0; i < taskCount; i++ {
+			line += mid + strings.Repeat("─", 6)
+		}
+		return line + mid + strings.Repeat("─", 8) + right + "\n"
+	}
+	printRow := func(row StandingsRow) {
+		marker := " "
+		if me != nil && row.UserID == me.UserID && row.Name == me.Name {
+			marker = "▶"
+		}
+		fmt.Printf("│%s%-5d │ %s ", marker, row.Place, padRunes(row.Name, nameWidth))
+		for i := 0; i < taskCount; i++ {
+			fmt.Printf("│ %4s ", formatStandingsCell(row, i))
+		}
+		fmt.Printf("│ %6d │\n", row.Total)
+	}
+
+	fmt.Print(T("standings.header", contestID, len(standings.Rows)))
+	fmt.Print(border("┌", "┬", "┐"))
+	fmt.Printf("│ %-5s │ %s ", "#", padRunes(T("standings.participant"), nameWidth))
+	for i := 0; i < taskCount; i++ {
+		fmt.Printf("│ %4s ", taskLetter(i))
+	}
+	fmt.Printf("│ %6s │\n", "Σ")
+	fmt.Print(border("├", "┼", "┤"))
+	for _, row := range rows {
+		printRow(row)
+	}
+	if meBelow != nil {
+		fmt.Print(border("├", "┼", "┤"))
+		printRow(*meBelow)
+	}
+	fmt.Print(border("└", "┴", "┘"))
+
+	if opts.Top > 0 && opts.Top < len(standings.Rows) {
+		fmt.Print(T("standings.more", len(standings.Rows)-opts.Top))
+	}
+	if opts.Me && me == nil {
+		fmt.Println(T("standings.me_missing"))
+	}
+}
+
+// Ячейка задачи: баллы, "-N" за N неудачных попыток, пусто если не сдавал
+func formatStandingsCell(row StandingsRow, task int) string {
+	if task >= len(row.Results) {
+		return ""
+	}
+	cell := row.Results[task]
+	if cell.Points > 0 {
+		return strconv.Itoa(cell.Points)
+	}
+	if cell.Attempts > 0 {
+		return fmt.Sprintf("-%d", cell.Attempts)
+	}
+	return ""
+}
+
+func containsRow(rows []StandingsRow, target *StandingsRow) bool {
+	for _, row := range rows {
+		if row.UserID == target.UserID && row.Name == target.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// Размер блока виджета
+const (
+	widgetWidth  = 36
+	widgetHeight = 12
+)
+
+func (v *VSCodeExtension) runStandingsWidget(ctx context.Context, contestID string, refresh time.Duration, output string) {
+	for {
+		block := v.renderStandingsWidget(ctx, contestID)
+
+		if output != "" {
+			if err := writeFileAtomic(output, []byte(block)); err != nil {
+				fmt.Fprint(os.Stderr, T("standings.widget_write_failed", err))
+				return
+			}
+		} else {
+			if refresh > 0 {
+				// Очищаем экран и возвращаем курсор в начало
+				fmt.Print("\033[H\033[2J")
+			}
+			fmt.Print(block)
+		}
+
+		if refresh <= 0 {
+			return
+		}
+		if refresh < 10*time.Second {
+			refresh = 10 * time.Second
+		}
+		if sleepContext(ctx, refresh) != nil {
+			return
+		}
+	}
+}
+
+// Рисует блок фиксированного размера: одинаковое число строк одинаковой ширины
+func (v *VSCodeExtension) renderStandingsWidget(ctx context.Context, contestID string) string {
+	var lines []string
+
+	standings, err := v.apiClient.GetStandings(ctx, contestID)
+	if err != nil {
+		lines = append(lines, T("standings.widget_contest", contestID), "", T("standings.widget_no_data"), err.Error())
+	} else {
+		lines = append(lines, "🏅 "+T("standings.widget_contest", contestID))
+		lines = append(lines, strings.Repeat("─", widgetWidth))
+
+		me := v.findMyRow(standings)
+		// Оставляем место под строку пользователя и время обновления
+		maxRows := widgetHeight - 5
+		meShown := false
+		for i, row := range standings.Rows {
+			if i >= maxRows {
+				break
+			}
+			marker := " "
+			if me != nil && row.Place == me.Place && row.Name == me.Name {
+				marker = "▶"
+				meShown = true
+			}
+			lines = append(lines, formatWidgetRow(marker, row))
+		}
+
+		lines = append(lines, strings.Repeat("─", widgetWidth))
+		if me != nil && !meShown {
+			lines = append(lines, formatWidgetRow("▶", *me))
+		}
+	}
+
+	for len(lines) < widgetHeight-1 {
+		lines = append(lines, "")
+	}
+	lines = lines[:widgetHeight-1]
+	lines = append(lines, T("standings.widget_updated", time.Now().Format("15:04:05")))
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(padRunes(line, widgetWidth))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func formatWidgetRow(marker string, row StandingsRow) string {
+	return fmt.Sprintf("%s%3d %s %5d", marker, row.Place, padRunes(row.Name, widgetWidth-11), row.Total)
+}
+
+// Дополняет или обрезает строку до width символов (считаем руны, а не байты)
+func padRunes(s string, width int) string {
+	count := utf8.RuneCountInString(s)
+	if count > width {
+		runes := []rune(s)
+		if width <= 2 {
+			return string(runes[:width])
+		}
+		return string(runes[:width-2]) + ".."
+	}
+	return s + strings.Repeat(" ", width-count)
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type StartOptions struct {
+	Dir      string        // Каталог контеста (по умолчанию contest_<id>)
+	Editor   string        // Чем открыть каталог
+	NoOpen   bool          // Не открывать редактор
+	Wait     bool          // Дождаться начала контеста
+	Timeout  time.Duration // Ограничение ожидания для --wait
+	Download DownloadOptions
+}
+
+func (v *VSCodeExtension) createStartCommand() *cobra.Command {
+	var opts StartOptions
+
+	cmd := &cobra.Command{
+		Use:   "start <contest_id>",
+		Short: T("start.short"),
+		Long:  T("start.long"),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if !v.apiClient.IsAuthenticated() {
+				return fmt.Errorf("%s", T("auth.required"))
+			}
+			return v.handleStart(cmd.Context(), args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Dir, "dir", "d", "", T("flag.start_dir"))
+	cmd.Flags().StringVar(&opts.Editor, "editor", "", T("flag.start_editor"))
+	cmd.Flags().BoolVar(&opts.NoOpen, "no-open", false, T("flag.start_no_open"))
+	cmd.Flags().BoolVar(&opts.Wait, "wait", false, T("flag.start_wait"))
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 0, T("flag.start_timeout"))
+	cmd.Flags().BoolVar(&opts.Download.ScaffoldIO, "scaffold-io", false, T("flag.scaffold_io"))
+	cmd.Flags().StringVarP(&opts.Download.Language, "language", "l", "c++", T("flag.scaffold_language"))
+	return cmd
+}
+
+// Файлы и каталоги, созданные командой. При ошибке удаляются,
+// а то, что существовало до запуска, не трогаем
+type rollbackLog struct {
+	paths []string
+}
+
+// Создает каталог и запоминает самый верхний из тех, которых раньше не было
+func (r *rollbackLog) mkdir(path string) error {
+	top := ""
+	for p := filepath.Clean(path); ; p = filepath.Dir(p) {
+		if _, err := os.Stat(p); err == nil {
+			break
+		}
+		top = p
+		if filepath.Dir(p) == p {
+			break
+		}
+	}
+	if top == "" {
+		return nil
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+	r.paths = append(r.paths, top)
+	return nil
+}
+
+func (r *rollbackLog) undo() {
+	for i := len(r.paths) - 1; i >= 0; i-- {
+		if err := os.RemoveAll(r.paths[i]); err != nil {
+			progress(T("start.rollback_failed", r.paths[i], err))
+		} else {
+			progress(T("start.rollback_removed", r.paths[i]))
+		}
+	}
+	r.paths = nil
+}
+
+// Этап команды start: [2/4] 📁 Рабочий каталог
+func startStep(n int, title string) {
+	fmt.Printf("\n[%d/4] %s\n", n, title)
+}
+
+// Редактор из --editor, $VISUAL, $EDITOR или VS Code
+func startEditor(flag string) string {
+	for _, editor := range []string{flag, os.Getenv("VISUAL"), os.Getenv("EDITOR")} {
+		if editor != "" {
+			return editor
+		}
+	}
+	return "code"
+}
+
+func (v *VSCodeExtension) handleStart(ctx context.Context, contestID string, opts StartOptions) error {
+	if opts.Wait {
+		if err := v.handleWait(ctx, contestID, opts.Timeout); err != nil {
+			return err
+		}
+	}
+
+	// 1. Регистрация
+	startStep(1, T("start.step_register"))
+	info, err := v.apiClient.GetContestInfo(ctx, contestID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", T("api.contest_info_failed"), err)
+	}
+	if info.Registered {
+		fmt.Println(T("start.already_registered"))
+	} else if err := v.apiClient.RegisterForContest(ctx, contestID); err != nil {
+		// Открытые контесты доступны и без регистрации
+		if len(info.Tasks) == 0 {
+			return fmt.Errorf("%s: %w", T("start.register_failed"), err)
+		}
+		progress(T("start.register_skipped", err))
+	} else {
+		fmt.Println(T("start.registered"))
+		if fresh, err := v.apiClient.GetContestInfo(ctx, contestID); err == nil {
+			info = fresh
+		}
+	}
+
+	if len(info.Tasks) == 0 {
+		progress(T("start.wait_hint", contestID))
+		return errors.New(T("contest.no_tasks_yet", contestID))
+	}
+	rememberContest(contestID, info)
+
+	// 2. Рабочий каталог. С этого момента все созданное откатывается при ошибке
+	dir := opts.Dir
+	if dir == "" {
+		dir = "contest_" + contestID
+	}
+	startStep(2, T("start.step_dir", dir))
+
+	var created rollbackLog
+	fail := func(err error) error {
+		if len(created.paths) > 0 {
+			progressln(T("start.rollback"))
+			created.undo()
+		}
+		return err
+	}
+
+	taskDirs := make([]string, len(info.Tasks))
+	for i := range info.Tasks {
+		taskDirs[i] = filepath.Join(dir, taskLetter(i))
+		if err := created.mkdir(taskDirs[i]); err != nil {
+			return fail(fmt.Errorf("%s: %w", T("download.mkdir_failed"), err))
+		}
+	}
+	fmt.Print(T("start.dir_ready", info.Name, len(info.Tasks)))
+
+	// 3. Условия всех задач
+	startStep(3, T("start.step_statements"))
+	for i, task := range info.Tasks {
+		problemID := fmt.Sprintf("%d", task.ID)
+		fmt.Printf("  [%d/%d] %s. %s", i+1, len(info.Tasks), taskLetter(i), task.Name)
+
+		result, err := v.downloadTask(ctx, contestID, problemID, taskDirs[i], opts.Download)
+		if err != nil {
+			fmt.Println(" ❌")
+			return fail(fmt.Errorf("%s: %w", T("start.task_failed", taskLetter(i)), err))
+		}
+
+		details := ""
+		if result.Samples > 0 {
+			details = T("start.samples_count", result.Samples)
+		}
+		fmt.Printf(" ✅%s\n", details)
+		if result.ScaffoldErr != nil {
+			progress(T("start.scaffold_failed", result.ScaffoldErr))
+		}
+	}
+
+	// Дальше контест готов, ошибки редактора уже ничего не откатывают
+	if v.config.CurrentContest != contestID {
+		v.config.setCurrentContest(contestID)
+		if err := SaveConfig(v.config); err != nil {
+			progress(T("start.save_current_failed", err))
+		}
+	}
+
+	// 4. Редактор
+	startStep(4, T("start.step_editor"))
+	if opts.NoOpen {
+		fmt.Println(T("start.editor_skipped"))
+	} else {
+		editor := startEditor(opts.Editor)
+		cmd := exec.Command(editor, dir)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			progress(T("start.editor_failed", editor, err))
+		} else {
+			fmt.Print(T("start.editor_opened", editor))
+		}
+	}
+
+	fmt.Print(T("start.ready", contestID, dir))
+	progress(T("problems.submit_hint"))
+	progress(T("problems.submit_example", contestID))
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Локальное состояние плагина (теги, служебные данные) хранится рядом с конфигом в JSON файлах.
+// Файлы пишутся атомарно, поэтому читатель без блокировки видит либо старую, либо новую версию
+
+func getStatePath(name string) string {
+	return filepath.Join(getConfigPath(), name)
+}
+
+// Читает JSON файл состояния. Отсутствующий файл не считается ошибкой
+func loadState(name string, v interface{}) error {
+	data, err := os.ReadFile(getStatePath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+func saveState(name string, v interface{}) error {
+	return updateState(name, v, nil)
+}
+
+// Читает файл состояния в v, применяет modify и записывает результат, все под
+// блокировкой state: параллельный процесс sortme не потеряет свои изменения.
+// Если файл не читается, он не перезаписывается. modify == nil - просто запись v
+func updateState(name string, v interface{}, modify func() error) error {
+	if err := os.MkdirAll(getConfigPath(), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	lock, err := acquireLock("state")
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	if modify != nil {
+		if err := loadState(name, v); err != nil {
+			return err
+		}
+		if err := modify(); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+	return writeFileAtomic(getStatePath(name), data)
+}
+
+// Запись через временный файл, чтобы OBS или другой процесс sortme
+// не прочитал наполовину записанный файл
+func writeFileAtomic(filename string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".sortme-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
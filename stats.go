@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Сводка по одной теме
+type TagStats struct {
+	Tag         string
+	Tasks       int
+	Solved      int
+	Attempts    int
+	TotalPoints int
+	Unsolved    []string
+}
+
+func (s TagStats) SolveRate() int {
+	if s.Tasks == 0 {
+		return 0
+	}
+	return s.Solved * 100 / s.Tasks
+}
+
+func (v *VSCodeExtension) createStatsCommand() *cobra.Command {
+	var byTag, apiUsage, local bool
+	var tagFilter, contestID string
+	var days int
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: T("stats.short"),
+		Long:  T("stats.long"),
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			// Статистика запросов локальная, авторизация не нужна
+			if apiUsage {
+				v.handleAPIStats(days)
+				return
+			}
+			// Без тегов - аналитика по истории из локальной базы, тоже без сети
+			if !byTag && tagFilter == "" {
+				v.handleHistoryStats(contestID)
+				return
+			}
+			if !local && !v.apiClient.IsAuthenticated() {
+				fmt.Println("❌ " + T("auth.required"))
+				return
+			}
+			v.handleTagStats(cmd.Context(), tagFilter, byTag, local)
+		},
+	}
+
+	cmd.Flags().BoolVar(&byTag, "by-tag", false, T("flag.stats_by_tag"))
+	cmd.Flags().StringVarP(&tagFilter, "tag", "t", "", T("flag.stats_tag"))
+	cmd.Flags().BoolVar(&apiUsage, "api", false, T("flag.stats_api"))
+	cmd.Flags().IntVar(&days, "days", 7, T("flag.stats_days"))
+	cmd.Flags().BoolVar(&local, "local", false, T("flag.local"))
+	cmd.Flags().StringVarP(&contestID, "contest", "c", "", T("flag.stats_contest"))
+
+	return cmd
+}
+
+// Статистика по темам. С tagFilter учитываются только задачи с этим тегом;
+// byTag раскладывает их по всем их темам, иначе в таблице одна строка - сам тег
+func (v *VSCodeExtension) handleTagStats(ctx context.Context, tagFilter string, byTag, local bool) {
+	store, err := LoadTags()
+	if err != nil {
+		progress(T("tags.read_failed", err))
+		return
+	}
+
+	if len(store.Tasks) == 0 {
+		fmt.Println(T("stats.no_tagged"))
+		progressln(T("tags.hint"))
+		return
+	}
+
+	progress(T("stats.analyzing", len(store.Tasks)))
+
+	// Состояние каждой задачи получаем один раз, даже если у нее несколько тегов
+	type taskResult struct {
+		solved   bool
+		points   int
+		attempts int
+	}
+	results := make(map[int]taskResult)
+
+	// С --local берем попытки из базы sortme sync, без запросов к API
+	var db *SubmissionDB
+	if local {
+		db, err = OpenSubmissionDB()
+		if err != nil {
+			progress(T("stats.db_failed", err))
+			return
+		}
+		defer db.Close()
+	}
+
+	for _, task := range store.Tasks {
+		if tagFilter != "" && !store.HasTag(task.TaskID, tagFilter) {
+			continue
+		}
+		if db != nil {
+			summary, err := db.TaskSummary(task.ContestID, task.TaskID)
+			if err != nil {
+				progress(T("stats.task_failed", task.TaskID, err))
+				continue
+			}
+			results[task.TaskID] = taskResult{solved: summary.Solved, points: summary.Points, attempts: summary.Attempts}
+			continue
+		}
+		endpoint := fmt.Sprintf("/getMySubmissionsByTask?id=%d", task.TaskID)
+		if task.ContestID != "" {
+			endpoint += "&contestid=" + task.ContestID
+		}
+		submissions, err := v.apiClient.tryGetSubmissions(ctx, endpoint, 0)
+		if err != nil {
+			progress(T("stats.task_failed", task.TaskID, err))
+			continue
+		}
+
+		var res taskResult
+		res.attempts = len(submissions)
+		for _, sub := range submissions {
+			if sub.TotalPoints > res.points {
+				res.points = sub.TotalPoints
+			}
+			if sub.TotalPoints == 100 || sub.ShownVerdict == 1 {
+				res.solved = true
+			}
+		}
+		results[task.TaskID] = res
+	}
+
+	statsByTag := make(map[string]*TagStats)
+	for _, task := range store.Tasks {
+		res, ok := results[task.TaskID]
+		if !ok {
+			continue
+		}
+		for _, tag := range task.Tags {
+			if tagFilter != "" && !byTag && tag != normalizeTag(tagFilter) {
+				continue
+			}
+			stats, exists := statsByTag[tag]
+			if !exists {
+				stats = &TagStats{Tag: tag}
+				statsByTag[tag] = stats
+			}
+			stats.Tasks++
+			stats.Attempts += res.attempts
+			stats.TotalPoints += res.points
+			if res.solved {
+				stats.Solved++
+			} else {
+				name := fmt.Sprintf("%d", task.TaskID)
+				if task.Name != "" {
+					name = fmt.Sprintf("%d. %s", task.TaskID, task.Name)
+				}
+				stats.Unsolved = append(stats.Unsolved, name)
+			}
+		}
+	}
+
+	if len(statsByTag) == 0 {
+		fmt.Println(T("stats.no_data"))
+		return
+	}
+
+	var list []*TagStats
+	for _, stats := range statsByTag {
+		list = append(list, stats)
+	}
+	// Слабые темы первыми
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].SolveRate() != list[j].SolveRate() {
+			return list[i].SolveRate() < list[j].SolveRate()
+		}
+		return list[i].Tag < list[j].Tag
+	})
+
+	fmt.Print(T("stats.by_topic"))
+	fmt.Printf("┌──────────────────────┬────────┬────────┬──────────┬────────┐\n")
+	fmt.Printf("│ %-20s │ %-6s │ %-6s │ %-8s │ %-6s │\n", T("stats.col_topic"), T("stats.col_tasks"), T("stats.col_solved"), T("stats.col_attempts"), "%")
+	fmt.Printf("├──────────────────────┼────────┼────────┼──────────┼────────┤\n")
+	for _, stats := range list {
+		tag := stats.Tag
+		if len(tag) > 20 {
+			tag = tag[:18] + ".."
+		}
+		fmt.Printf("│ %-20s │ %-6d │ %-6d │ %-8d │ %-6d │\n",
+			tag, stats.Tasks, stats.Solved, stats.Attempts, stats.SolveRate())
+	}
+	fmt.Printf("└──────────────────────┴────────┴────────┴──────────┴────────┘\n")
+
+	weakest := list[0]
+	if len(weakest.Unsolved) > 0 {
+		fmt.Print(T("stats.weakest", weakest.Tag, strings.Join(weakest.Unsolved, ", ")))
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Аналитика по истории отправок из локальной базы (sortme sync): какие
+// вердикты получаю чаще, на каком языке решаю лучше, сколько попыток уходит
+// на AC и какие задачи не поддаются. Без запросов к API
+
+const statsMostFailed = 5 // сколько самых проблемных задач показать
+
+type VerdictCount struct {
+	Verdict string `json:"verdict"`
+	Count   int    `json:"count"`
+}
+
+type LanguageStats struct {
+	Language    string `json:"language"`
+	Submissions int    `json:"submissions"`
+	Accepted    int    `json:"accepted"`
+}
+
+type FailedTask struct {
+	ContestID string `json:"contest_id"`
+	TaskID    int    `json:"task_id"`
+	Name      string `json:"name"`
+	Failed    int    `json:"failed"`
+	Solved    bool   `json:"solved"`
+}
+
+type HistoryStats struct {
+	Submissions   int             `json:"submissions"`
+	Tasks         int             `json:"tasks"`
+	Solved        int             `json:"solved"`
+	FirstTry      int             `json:"first_try"` // решено с первой попытки
+	AttemptsPerAC float64         `json:"attempts_per_ac"`
+	Verdicts      []VerdictCount  `json:"verdicts"`
+	Languages     []LanguageStats `json:"languages"`
+	MostFailed    []FailedTask    `json:"most_failed"`
+}
+
+func isAcceptedSubmission(sub Submission) bool {
+	return sub.ShownVerdict == 1 || sub.TotalPoints == 100
+}
+
+// Группа вердикта для распределения: частичные решения отдельно от WA
+func verdictGroup(sub Submission) string {
+	switch {
+	case isAcceptedSubmission(sub):
+		return "OK"
+	case isPendingSubmission(sub):
+		return T("stats.group_pending")
+	case sub.ShownVerdict >= 2 && sub.ShownVerdict <= 6:
+		return getShortStatusText(sub.ShownVerdict)
+	case sub.ShownVerdict == 7 || sub.TotalPoints > 0:
+		return T("stats.group_partial")
+	}
+	return T("stats.group_other")
+}
+
+// Считает статистику по отправкам в порядке отправки (старые первыми)
+func buildHistoryStats(submissions []Submission) HistoryStats {
+	type taskKey struct {
+		contestID string
+		taskID    int
+	}
+	type taskState struct {
+		name     string
+		attempts int // попыток до первого AC включительно
+		failed   int
+		solved   bool
+	}
+
+	stats := HistoryStats{Submissions: len(submissions)}
+	verdicts := make(map[string]int)
+	languages := make(map[string]*LanguageStats)
+	tasks := make(map[taskKey]*taskState)
+	var order []taskKey
+
+	for _, sub := range submissions {
+		group := verdictGroup(sub)
+		verdicts[group]++
+
+		language := cmp.Or(sub.Language, "?")
+		lang, ok := languages[language]
+		if !ok {
+			lang = &LanguageStats{Language: language}
+			languages[language] = lang
+		}
+		lang.Submissions++
+		if group == "OK" {
+			lang.Accepted++
+		}
+
+		key := taskKey{sub.ContestID, sub.ProblemID}
+		task, ok := tasks[key]
+		if !ok {
+			task = &taskState{}
+			tasks[key] = task
+			order = append(order, key)
+		}
+		task.name = cmp.Or(task.name, sub.ProblemName)
+		if group != "OK" && group != T("stats.group_pending") {
+			task.failed++
+		}
+		if !task.solved {
+			task.attempts++
+			task.solved = group == "OK"
+		}
+	}
+
+	totalAttempts := 0
+	for _, key := range order {
+		task := tasks[key]
+		stats.Tasks++
+		if task.solved {
+			stats.Solved++
+			totalAttempts += task.attempts
+			if task.attempts == 1 {
+				stats.FirstTry++
+			}
+		}
+		if task.failed > 0 {
+			stats.MostFailed = append(stats.MostFailed, FailedTask{
+				ContestID: key.contestID, TaskID: key.taskID, Name: task.name, Failed: task.failed, Solved: task.solved,
+			})
+		}
+	}
+	if stats.Solved > 0 {
+		stats.AttemptsPerAC = float64(totalAttempts) / float64(stats.Solved)
+	}
+
+	// Нерешенные выше решенных при равном числе неудач: их стоит дорешать
+	sort.SliceStable(stats.MostFailed, func(i, j int) bool {
+		a, b := stats.MostFailed[i], stats.MostFailed[j]
+		if a.Failed != b.Failed {
+			return a.Failed > b.Failed
+		}
+		return !a.Solved && b.Solved
+	})
+	if len(stats.MostFailed) > statsMostFailed {
+		stats.MostFailed = stats.MostFailed[:statsMostFailed]
+	}
+
+	for verdict, count := range verdicts {
+		stats.Verdicts = append(stats.Verdicts, VerdictCount{verdict, count})
+	}
+	sort.Slice(stats.Verdicts, func(i, j int) bool {
+		if stats.Verdicts[i].Count != stats.Verdicts[j].Count {
+			return stats.Verdicts[i].Count > stats.Verdicts[j].Count
+		}
+		return stats.Verdicts[i].Verdict < stats.Verdicts[j].Verdict
+	})
+
+	for _, lang := range languages {
+		stats.Languages = append(stats.Languages, *lang)
+	}
+	sort.Slice(stats.Languages, func(i, j int) bool {
+		if stats.Languages[i].Submissions != stats.Languages[j].Submissions {
+			return stats.Languages[i].Submissions > stats.Languages[j].Submissions
+		}
+		return stats.Languages[i].Language < stats.Languages[j].Language
+	})
+	return stats
+}
+
+func (v *VSCodeExtension) handleHistoryStats(contestID string) {
+	db, err := OpenSubmissionDB()
+	if err != nil {
+		v.fail(T("stats.db_open_error", err))
+		return
+	}
+	defer db.Close()
+
+	submissions, err := db.AllSubmissions(contestID)
+	if err != nil {
+		v.fail(T("stats.db_read_error", err))
+		return
+	}
+	if len(submissions) == 0 {
+		if contestID != "" {
+			fmt.Print(T("stats.empty_contest", contestID))
+			progress(T("stats.sync_contest_hint", contestID))
+		} else {
+			fmt.Println(T("stats.empty"))
+			progressln(T("stats.sync_hint"))
+		}
+		v.emitJSON(HistoryStats{})
+		return
+	}
+
+	stats := buildHistoryStats(submissions)
+	v.emitJSON(stats)
+
+	scope := T("stats.scope_all")
+	if contestID != "" {
+		scope = T("stats.scope_contest", contestID)
+	}
+	fmt.Print(T("stats.header", scope))
+	fmt.Print(T("stats.totals",
+		stats.Submissions, stats.Tasks, stats.Solved, percent(stats.Solved, stats.Tasks)))
+	if stats.Solved > 0 {
+		fmt.Print(T("stats.attempts", stats.AttemptsPerAC, stats.FirstTry))
+	}
+
+	fmt.Print(T("stats.verdicts"))
+	for _, verdict := range stats.Verdicts {
+		share := percent(verdict.Count, stats.Submissions)
+		fmt.Printf("  %s %5d  %5.1f%%  %s\n", padRunes(verdict.Verdict, 10), verdict.Count, share,
+			strings.Repeat("█", int(share/5+0.5)))
+	}
+
+	width := len([]rune(T("stats.language")))
+	for _, lang := range stats.Languages {
+		width = max(width, len([]rune(lang.Language)))
+	}
+	fmt.Print(T("stats.languages"))
+	fmt.Print(T("stats.languages_header", padRunes(T("stats.language"), width)))
+	for _, lang := range stats.Languages {
+		fmt.Printf("  %s  %8d  %5d  %4.0f%%\n", padRunes(lang.Language, width),
+			lang.Submissions, lang.Accepted, percent(lang.Accepted, lang.Submissions))
+	}
+
+	if len(stats.MostFailed) > 0 {
+		fmt.Print(T("stats.most_failed"))
+		for _, task := range stats.MostFailed {
+			mark := "❌"
+			if task.Solved {
+				mark = "✅"
+			}
+			name := fmt.Sprint(task.TaskID)
+			if task.Name != "" {
+				name = fmt.Sprintf("%d. %s", task.TaskID, task.Name)
+			}
+			fmt.Print(T("stats.failed_task", mark, name, task.ContestID, task.Failed))
+		}
+	}
+}
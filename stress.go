@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// Стресс-тестирование: генератор -> медленное верное решение и основное,
+// до первого расхождения. Контрпример сохраняется в tests/ как stressN.in/.out,
+// чтобы sortme test проверял его дальше вместе с примерами
+type StressOptions struct {
+	Generator  string
+	Brute      string
+	Language   string // язык основного решения, остальные определяются по расширению
+	Iterations int
+	Seed       int // seed первой итерации, дальше +1
+	Timeout    time.Duration
+	TestsDir   string
+}
+
+func (v *VSCodeExtension) createStressCommand() *cobra.Command {
+	var opts StressOptions
+
+	cmd := &cobra.Command{
+		Use:   "stress <solution>",
+		Short: T("stress.short"),
+		Long:  T("stress.long"),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if opts.Brute == "" {
+				return errors.New(T("stress.brute_missing"))
+			}
+			if opts.Generator == "" {
+				generator, _, err := resolveGenerator("", filepath.Dir(args[0]))
+				if err != nil {
+					return err
+				}
+				opts.Generator = generator
+			}
+			if opts.TestsDir == "" {
+				opts.TestsDir = filepath.Join(filepath.Dir(args[0]), "tests")
+			}
+			return v.handleStress(cmd.Context(), args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Generator, "gen", "g", "", T("flag.stress_gen"))
+	cmd.Flags().StringVarP(&opts.Brute, "brute", "b", "", T("flag.stress_brute"))
+	cmd.Flags().StringVarP(&opts.Language, "language", "l", "", T("flag.language"))
+	cmd.Flags().IntVarP(&opts.Iterations, "iterations", "n", 1000, T("flag.stress_iterations"))
+	cmd.Flags().IntVar(&opts.Seed, "seed", 1, T("flag.stress_seed"))
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 5*time.Second, T("flag.stress_timeout"))
+	cmd.Flags().StringVarP(&opts.TestsDir, "tests", "t", "", T("flag.stress_tests"))
+	return cmd
+}
+
+// Компилирует файл, определяя язык по расширению, если он не задан
+func (v *VSCodeExtension) prepareStressProgram(role, filename, language string) (*Solution, error) {
+	if _, err := os.Stat(filename); err != nil {
+		return nil, fmt.Errorf("%s: %w", role, err)
+	}
+	if language == "" {
+		language = v.apiClient.DetectLanguage(filename)
+		if language == "unknown" {
+			return nil, errors.New(T("stress.language_unknown", role, filename))
+		}
+	}
+	progress(T("stress.compiling", role, filename, language))
+	program, err := PrepareSolution(filename, language, v.config.Languages)
+	if err != nil {
+		var compileErr *CompileError
+		if errors.As(err, &compileErr) {
+			fmt.Print(T("stress.compile_error", compileErr.Output))
+		}
+		return nil, fmt.Errorf("%s: %w", role, err)
+	}
+	return program, nil
+}
+
+func (v *VSCodeExtension) handleStress(ctx context.Context, filename string, opts StressOptions) error {
+	generator, err := v.prepareStressProgram(T("stress.role_generator"), opts.Generator, "")
+	if err != nil {
+		return err
+	}
+	defer generator.Cleanup()
+	brute, err := v.prepareStressProgram(T("stress.role_brute"), opts.Brute, "")
+	if err != nil {
+		return err
+	}
+	defer brute.Cleanup()
+	solution, err := v.prepareStressProgram(T("stress.role_solution"), filename, opts.Language)
+	if err != nil {
+		return err
+	}
+	defer solution.Cleanup()
+
+	interactive := term.IsTerminal(int(os.Stderr.Fd()))
+	progress(T("stress.started", opts.Iterations, opts.Seed))
+
+	for i := 0; i < opts.Iterations; i++ {
+		if ctx.Err() != nil {
+			fmt.Print(T("stress.interrupted", i))
+			return ctx.Err()
+		}
+		seed := strconv.Itoa(opts.Seed + i)
+		if interactive {
+			progress(T("stress.iteration", i+1, opts.Iterations, seed))
+		} else if (i+1)%100 == 0 {
+			progress(T("stress.iterations", i+1))
+		}
+
+		// Генератор получает seed аргументом, чтобы тест можно было воспроизвести
+		generated := generator.Run(nil, opts.Timeout, seed)
+		if problem := runProblem(generated, opts.Timeout); problem != "" {
+			progressln()
+			printStderrTail(generated.Stderr)
+			return errors.New(T("stress.generator_failed", seed, problem))
+		}
+		input := generated.Output
+
+		expected := brute.Run(input, opts.Timeout)
+		if problem := runProblem(expected, opts.Timeout); problem != "" {
+			progressln()
+			printStderrTail(expected.Stderr)
+			return errors.New(T("stress.brute_failed", seed, problem))
+		}
+
+		actual := solution.Run(input, opts.Timeout)
+		problem := runProblem(actual, opts.Timeout)
+		if problem == "" && outputsMatch(expected.Output, actual.Output) {
+			continue
+		}
+
+		progressln()
+		fmt.Print(T("stress.mismatch", i+1, seed))
+		if problem != "" {
+			fmt.Print(T("stress.solution_failed", problem))
+			printStderrTail(actual.Stderr)
+		} else {
+			printOutputDiff(expected.Output, actual.Output)
+		}
+		if len(input) <= 500 {
+			fmt.Println(T("stress.input"))
+			printIndented(string(input))
+		}
+
+		base, err := saveCounterexample(opts.TestsDir, input, expected.Output)
+		if err != nil {
+			return fmt.Errorf("%s: %w", T("stress.save_failed"), err)
+		}
+		fmt.Print(T("stress.saved", base, base))
+		progress(T("stress.retest_hint", filename))
+		return &exitCodeError{code: exitError, reason: T("stress.counterexample", seed)}
+	}
+
+	if interactive {
+		progressln()
+	}
+	fmt.Print(T("stress.passed", opts.Iterations))
+	return nil
+}
+
+// Описание неудачного запуска или пустая строка, если программа отработала
+func runProblem(result *RunResult, timeout time.Duration) string {
+	switch {
+	case result.TimedOut:
+		return T("stress.timeout", timeout)
+	case result.Err != nil:
+		return T("stress.run_error", result.Err)
+	case result.ExitCode != 0:
+		return T("stress.exit_code", result.ExitCode)
+	}
+	return ""
+}
+
+// Сохраняет тест под первым свободным именем stressN, возвращает путь без расширения
+func saveCounterexample(dir string, input, answer []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	for n := 1; ; n++ {
+		base := filepath.Join(dir, fmt.Sprintf("stress%d", n))
+		if _, err := os.Stat(base + ".in"); err == nil {
+			continue
+		}
+		if err := os.WriteFile(base+".in", input, 0644); err != nil {
+			return "", err
+		}
+		return base, os.WriteFile(base+".out", answer, 0644)
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Подсказка о задаче, найденная в имени файла или первом комментарии
+type taskHint struct {
+	Letter string // "C"
+	TaskID int    // 2472
+	Name   string // название задачи из комментария
+	Source string // где нашли: имя файла, комментарий
+	// Найдена в имени файла: "s.cpp" может значить solution, а не задачу S
+	FromFilename bool
+}
+
+var (
+	// "problem_c", "task-B", "Задача C", "problem 2472"
+	reTaskWord = regexp.MustCompile(`(?i)(?:problem|task|задача)[\s_\-#№:]+([a-zа-я]|\d+)(?:[^a-zа-я0-9]|$)`)
+	// Имя файла из одной буквы: "b.cpp", "C1.py", "a_brute.cpp"
+	reLetterFile = regexp.MustCompile(`(?i)^([a-z])(?:\d*|[_\-].*)$`)
+	// Длинное число в имени файла похоже на ID задачи: "2472.cpp"
+	reTaskIDInName = regexp.MustCompile(`(\d{3,})`)
+)
+
+// Кириллические буквы, которые пишут вместо латинских в названии задач
+var cyrillicToLatin = map[string]string{"А": "A", "В": "B", "С": "C", "Е": "E", "Н": "H", "К": "K", "М": "M", "О": "O", "Р": "P", "Т": "T", "Х": "X"}
+
+func normalizeTaskLetter(s string) string {
+	s = strings.ToUpper(s)
+	if latin, ok := cyrillicToLatin[s]; ok {
+		return latin
+	}
+	return s
+}
+
+func hintFromText(text, source string) *taskHint {
+	match := reTaskWord.FindStringSubmatch(text)
+	if match == nil {
+		return nil
+	}
+	if id, err := strconv.Atoi(match[1]); err == nil {
+		// Короткие числа - скорее номер задачи, чем ID
+		if id < 100 && id > 0 {
+			return &taskHint{Letter: taskLetter(id - 1), Source: source}
+		}
+		return &taskHint{TaskID: id, Source: source}
+	}
+	letter := match[1]
+	if letter[0] >= 0x80 {
+		// Кириллица: "Задача С" - буква, а "задача о рюкзаке" - нет
+		if strings.ToUpper(letter) != letter {
+			return nil
+		}
+		if _, ok := cyrillicToLatin[letter]; !ok {
+			return nil
+		}
+	}
+	return &taskHint{Letter: normalizeTaskLetter(letter), Source: source}
+}
+
+func detectTaskHints(filename, sourceCode string) []*taskHint {
+	var hints []*taskHint
+
+	base := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	if hint := hintFromText(base, T("guard.source_filename")); hint != nil {
+		hints = append(hints, hint)
+	} else if match := reLetterFile.FindStringSubmatch(base); match != nil {
+		hints = append(hints, &taskHint{Letter: strings.ToUpper(match[1]), Source: T("guard.source_filename")})
+	} else if match := reTaskIDInName.FindStringSubmatch(base); match != nil {
+		id, _ := strconv.Atoi(match[1])
+		hints = append(hints, &taskHint{TaskID: id, Source: T("guard.source_filename")})
+	}
+	for _, hint := range hints {
+		hint.FromFilename = true
+	}
+
+	if comment := firstCommentLine(sourceCode); comment != "" {
+		if hint := hintFromText(comment, T("guard.source_comment")); hint != nil {
+			hints = append(hints, hint)
+		} else {
+			hints = append(hints, &taskHint{Name: comment, Source: T("guard.source_comment")})
+		}
+	}
+
+	return hints
+}
+
+// Первая строка-комментарий в начале файла (пропускаем пустые строки и #include)
+func firstCommentLine(sourceCode string) string {
+	scanner := bufio.NewScanner(strings.NewReader(sourceCode))
+	for i := 0; scanner.Scan() && i < 10; i++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "", strings.HasPrefix(line, "#include"), strings.HasPrefix(line, "#!"):
+			continue
+		case strings.HasPrefix(line, "//"), strings.HasPrefix(line, "--"):
+			return strings.TrimSpace(strings.TrimLeft(line, "/-"))
+		case strings.HasPrefix(line, "/*"):
+			return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, "/*"), "*/"))
+		case strings.HasPrefix(line, "#"):
+			return strings.TrimSpace(strings.TrimLeft(line, "#"))
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// Проверяет подсказки против задачи, в которую отправляем. Возвращает описание расхождения
+func checkTaskMismatch(hints []*taskHint, tasks []Task, problemID int) string {
+	targetIndex := -1
+	for i, task := range tasks {
+		if task.ID == problemID {
+			targetIndex = i
+			break
+		}
+	}
+	if targetIndex < 0 {
+		return ""
+	}
+	target := tasks[targetIndex]
+	targetLetter := taskLetter(targetIndex)
+
+	for _, hint := range hints {
+		switch {
+		case hint.Letter != "" && hint.FromFilename && !hasTaskLetter(tasks, hint.Letter):
+			// Буквы нет в контесте: имя файла, скорее всего, не про задачу
+			continue
+		case hint.Letter != "" && hint.Letter != targetLetter:
+			return T("guard.letter_mismatch",
+				hint.Source, hint.Letter, targetLetter, target.Name)
+		case hint.TaskID != 0 && hint.TaskID != target.ID:
+			for _, task := range tasks {
+				if task.ID == hint.TaskID {
+					return T("guard.id_mismatch",
+						hint.Source, hint.TaskID, task.Name, target.ID, target.Name)
+				}
+			}
+		case hint.Name != "":
+			// Комментарий совпадает с названием другой задачи контеста
+			comment := strings.ToLower(hint.Name)
+			if strings.Contains(comment, strings.ToLower(target.Name)) {
+				continue
+			}
+			for i, task := range tasks {
+				if i != targetIndex && len(task.Name) > 3 && strings.Contains(comment, strings.ToLower(task.Name)) {
+					return T("guard.name_mismatch",
+						hint.Source, taskLetter(i), task.Name, targetLetter, target.Name)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func hasTaskLetter(tasks []Task, letter string) bool {
+	for i := range tasks {
+		if taskLetter(i) == letter {
+			return true
+		}
+	}
+	return false
+}
+
+// Эвристическая защита от отправки не того файла. Возвращает false если отправку нужно отменить
+func (v *VSCodeExtension) confirmTaskMatch(ctx context.Context, filename, sourceCode, contestID, problemID string, assumeYes bool) bool {
+	hints := detectTaskHints(filename, sourceCode)
+	if len(hints) == 0 {
+		return true
+	}
+
+	problemIDInt, err := strconv.Atoi(problemID)
+	if err != nil {
+		return true
+	}
+
+	contestInfo, err := v.apiClient.GetContestInfo(ctx, contestID)
+	if err != nil {
+		// Без списка задач проверить нечего, не мешаем отправке
+		return true
+	}
+
+	mismatch := checkTaskMismatch(hints, contestInfo.Tasks, problemIDInt)
+	if mismatch == "" {
+		return true
+	}
+
+	progress(T("guard.wrong_file", mismatch))
+	if assumeYes {
+		progressln(T("guard.continue_yes"))
+		return true
+	}
+	return askConfirmation(T("guard.confirm"))
+}
+
+// Задача уже решена на полный балл: лишняя отправка на ICPC-контесте может
+// стоить штрафа. Сначала смотрим локальную базу (решенная задача решенной
+// и останется), потом сервер. Если проверить не удалось, отправке не мешаем
+func (v *VSCodeExtension) confirmNotSolved(ctx context.Context, contestID, problemID string, opts SubmitOptions) bool {
+	if opts.Force {
+		return true
+	}
+	taskID, err := strconv.Atoi(problemID)
+	if err != nil {
+		return true
+	}
+
+	var summary TaskSummary
+	if db, err := OpenSubmissionDB(); err == nil {
+		summary, _ = db.TaskSummary(contestID, taskID)
+		db.Close()
+	}
+	if !summary.Solved {
+		solved, points, attempts, err := v.apiClient.GetTaskStatus(ctx, contestID, taskID)
+		if err != nil {
+			return true
+		}
+		summary = TaskSummary{Solved: solved || points >= 100, Points: points, Attempts: attempts}
+	}
+	if !summary.Solved {
+		return true
+	}
+
+	fmt.Print(T("submit.already_solved", problemID, summary.Points, summary.Attempts))
+	if opts.DryRun {
+		return true
+	}
+	// --yes отвечает на вопросы, но решенную задачу отправляет только --force
+	if opts.AssumeYes {
+		progressln(T("submit.already_solved_hint"))
+		return false
+	}
+	if !askConfirmation(T("submit.already_solved_confirm")) {
+		progressln(T("submit.already_solved_hint"))
+		return false
+	}
+	return true
+}
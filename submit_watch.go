@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Коды завершения submit --watch, чтобы скрипты могли проверить вердикт
+const (
+	exitAccepted         = 0
+	exitError            = 1
+	exitRejected         = 2 // WA, TLE, MLE, RE и частичное решение
+	exitCompilationError = 3
+	exitNoVerdict        = 4 // вердикт не пришел за время ожидания
+)
+
+// Ошибка с заданным кодом завершения процесса. Вердикт уже напечатан,
+// поэтому main не выводит ее текст еще раз
+type exitCodeError struct {
+	code   int
+	reason string
+}
+
+func (e *exitCodeError) Error() string {
+	return e.reason
+}
+
+// Код завершения для ошибки команды
+func exitCodeOf(err error) int {
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return exitError
+}
+
+func isExitCodeError(err error) bool {
+	var exitErr *exitCodeError
+	return errors.As(err, &exitErr)
+}
+
+func verdictExitCode(status *SubmissionStatus) int {
+	switch strings.ToLower(status.Status) {
+	case "accepted", "ac":
+		return exitAccepted
+	case "compilation_error", "ce":
+		return exitCompilationError
+	}
+	return exitRejected
+}
+
+// Ждет вердикт только что отправленного решения, печатая ход проверки
+func (v *VSCodeExtension) watchVerdict(ctx context.Context, result map[string]interface{}, submissionID, contestID, problemID string) error {
+	progressln(T("submit.waiting_verdict"))
+
+	lastLine := ""
+	status, err := v.apiClient.watchSubmission(ctx, submissionID, func(status *SubmissionStatus) {
+		line := "  " + getStatusEmoji(status.Status)
+		if status.QueuePosition > 0 {
+			line += T("submit.queue_position", status.QueuePosition)
+		}
+		if status.Test > 0 {
+			line += T("submit.test", status.Test)
+		}
+		if status.Score > 0 {
+			line += T("submit.points", status.Score)
+		}
+		// Одинаковые сообщения подряд не повторяем
+		if line != lastLine {
+			fmt.Println(line)
+			lastLine = line
+		}
+	})
+	if err != nil {
+		progress(T("submit.check_later", submissionID))
+		return fmt.Errorf("%s: %w", T("submit.verdict_failed"), err)
+	}
+
+	result["verdict"] = status
+	v.emitJSON(result)
+
+	if !v.apiClient.isFinalStatus(status.Status) {
+		fmt.Print(T("submit.verdict_not_ready", getStatusEmoji(status.Status)))
+		progress(T("submit.check_later", submissionID))
+		return &exitCodeError{code: exitNoVerdict, reason: T("submit.no_verdict")}
+	}
+
+	fmt.Print(T("submit.verdict", getStatusEmoji(status.Status)))
+	if status.Result != "" {
+		fmt.Print(T("status.result", status.Result))
+	}
+	if status.Score > 0 {
+		fmt.Print(T("status.score", status.Score))
+	}
+	if status.Time != "" {
+		fmt.Print(T("status.time", status.Time))
+	}
+	if status.Memory != "" {
+		fmt.Print(T("status.memory", status.Memory))
+	}
+
+	v.warnNearLimits(ctx, status, contestID, problemID)
+	v.notifyVerdict(status, contestID, problemID, "")
+
+	if code := verdictExitCode(status); code != exitAccepted {
+		return &exitCodeError{code: code, reason: T("submit.not_accepted", status.Status)}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func (v *VSCodeExtension) createSyncCommand() *cobra.Command {
+	var all, full bool
+
+	cmd := &cobra.Command{
+		Use:   "sync [contest_id...]",
+		Short: T("sync.short"),
+		Long:  T("sync.long"),
+		Run: func(cmd *cobra.Command, args []string) {
+			if !v.apiClient.IsAuthenticated() {
+				fmt.Println("❌ " + T("auth.required"))
+				return
+			}
+			v.handleSync(cmd.Context(), args, all, full)
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, T("flag.sync_all"))
+	cmd.Flags().BoolVar(&full, "full", false, T("flag.sync_full"))
+	return cmd
+}
+
+// Контесты для синхронизации по умолчанию: текущий, идущие сейчас
+// и все, что уже есть в локальной базе
+func (v *VSCodeExtension) syncTargets(ctx context.Context, db *SubmissionDB, all bool) []string {
+	var targets []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			targets = append(targets, id)
+		}
+	}
+
+	add(v.config.CurrentContest)
+
+	if upcoming, err := v.apiClient.getUpcomingContestList(ctx); err == nil {
+		now := time.Now().Unix()
+		for _, contest := range upcoming {
+			// В еще не начавшихся контестах отправок быть не может
+			if contest.Starts <= now {
+				add(fmt.Sprintf("%d", contest.ID))
+			}
+		}
+	} else {
+		progress(T("sync.active_failed", err))
+	}
+
+	if synced, err := db.SyncedContests(); err == nil {
+		for _, id := range synced {
+			add(id)
+		}
+	}
+
+	if all {
+		archive, err := v.apiClient.getArchiveContests(ctx)
+		if err != nil {
+			progress(T("sync.archive_failed", err))
+		}
+		for _, contest := range archive {
+			add(contest.ID)
+		}
+	}
+
+	return targets
+}
+
+func (v *VSCodeExtension) handleSync(ctx context.Context, contestIDs []string, all, full bool) {
+	// Два sync одновременно только удвоят нагрузку на API
+	lock, err := acquireLock("sync")
+	if err != nil {
+		fmt.Println("❌ " + T("error.generic", err))
+		return
+	}
+	defer lock.Release()
+
+	db, err := OpenSubmissionDB()
+	if err != nil {
+		fmt.Println("❌ " + T("stats.db_open_error", err))
+		return
+	}
+	defer db.Close()
+
+	if len(contestIDs) == 0 {
+		contestIDs = v.syncTargets(ctx, db, all)
+	}
+	if len(contestIDs) == 0 {
+		fmt.Println(T("sync.nothing"))
+		progressln(T("sync.contest_hint"))
+		return
+	}
+
+	progress(T("sync.started", len(contestIDs)))
+
+	var created, changed, total, skipped, failed int
+	for _, contestID := range contestIDs {
+		if !full {
+			upToDate, err := db.ContestUpToDate(contestID)
+			if err == nil && upToDate {
+				skipped++
+				continue
+			}
+		}
+
+		contestInfo, err := v.apiClient.GetContestInfo(ctx, contestID)
+		if err != nil {
+			progressf("  ⚠️ %s: %v\n", contestID, err)
+			failed++
+			continue
+		}
+
+		submissions, err := v.apiClient.GetContestSubmissions(ctx, contestID, 0)
+		if err != nil {
+			progressf("  ⚠️ %s: %v\n", contestID, err)
+			failed++
+			continue
+		}
+
+		contestCreated, contestChanged := 0, 0
+		for _, sub := range submissions {
+			if sub.ContestID == "" {
+				sub.ContestID = contestID
+			}
+			isNew, isChanged, err := db.Upsert(sub)
+			if err != nil {
+				progress(T("sync.write_failed", sub.ID, err))
+				continue
+			}
+			if isNew {
+				contestCreated++
+			} else if isChanged {
+				contestChanged++
+			}
+		}
+		if err := db.MarkContestSynced(contestID, contestInfo); err != nil {
+			progressf("  ⚠️ %s: %v\n", contestID, err)
+		}
+
+		created += contestCreated
+		changed += contestChanged
+		total += len(submissions)
+
+		line := T("sync.contest_done", contestID, contestInfo.Name, len(submissions))
+		if contestCreated > 0 || contestChanged > 0 {
+			line += T("sync.contest_changes", contestCreated, contestChanged)
+		}
+		progressln(line)
+	}
+
+	progressln()
+	fmt.Print(T("sync.done", total, created, changed))
+	if skipped > 0 {
+		fmt.Print(T("sync.skipped", skipped))
+	}
+	if failed > 0 {
+		progress(T("sync.failed", failed))
+	}
+	fmt.Print(T("sync.db_path", getSubmissionDBPath()))
+}
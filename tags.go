@@ -0,0 +1,248 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const tagsStateFile = "tags.json"
+
+// Локальные теги задач (dp, graphs, bitmask...)
+type TagStore struct {
+	Tasks map[string]*TaggedTask `json:"tasks"` // ключ - ID задачи
+}
+
+type TaggedTask struct {
+	TaskID    int      `json:"task_id"`
+	ContestID string   `json:"contest_id,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Tags      []string `json:"tags"`
+}
+
+func LoadTags() (*TagStore, error) {
+	store := &TagStore{}
+	if err := loadState(tagsStateFile, store); err != nil {
+		return nil, err
+	}
+	if store.Tasks == nil {
+		store.Tasks = make(map[string]*TaggedTask)
+	}
+	return store, nil
+}
+
+func (s *TagStore) Save() error {
+	return saveState(tagsStateFile, s)
+}
+
+// Меняет теги под блокировкой состояния: свежая версия файла читается заново,
+// чтобы не затереть теги, добавленные другим процессом после LoadTags
+func UpdateTags(modify func(store *TagStore)) (*TagStore, error) {
+	store := &TagStore{}
+	err := updateState(tagsStateFile, store, func() error {
+		if store.Tasks == nil {
+			store.Tasks = make(map[string]*TaggedTask)
+		}
+		modify(store)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Теги задачи (пустой срез если задача не размечена)
+func (s *TagStore) TagsFor(taskID int) []string {
+	if task, ok := s.Tasks[strconv.Itoa(taskID)]; ok {
+		return task.Tags
+	}
+	return nil
+}
+
+func (s *TagStore) HasTag(taskID int, tag string) bool {
+	for _, t := range s.TagsFor(taskID) {
+		if t == normalizeTag(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Все теги с количеством задач
+func (s *TagStore) AllTags() map[string]int {
+	counts := make(map[string]int)
+	for _, task := range s.Tasks {
+		for _, tag := range task.Tags {
+			counts[tag]++
+		}
+	}
+	return counts
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// Разбирает строку вида "dp,bitmask, graphs"
+func parseTagList(value string) []string {
+	var tags []string
+	for _, part := range strings.Split(value, ",") {
+		if tag := normalizeTag(part); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func (v *VSCodeExtension) createTagCommand() *cobra.Command {
+	var contestID string
+	var remove, clear bool
+
+	cmd := &cobra.Command{
+		Use:   "tag [task_id] [tags]",
+		Short: T("tag.short"),
+		Long:  T("tag.long"),
+		Args:  cobra.MaximumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			store, err := LoadTags()
+			if err != nil {
+				fmt.Print(T("tags.read_failed", err))
+				return
+			}
+
+			if len(args) == 0 {
+				printAllTags(store)
+				return
+			}
+
+			taskID, err := strconv.Atoi(args[0])
+			if err != nil {
+				fmt.Print(T("tags.bad_task_id", args[0]))
+				return
+			}
+			key := strconv.Itoa(taskID)
+
+			if len(args) == 1 && !clear {
+				tags := store.TagsFor(taskID)
+				if len(tags) == 0 {
+					fmt.Print(T("tags.none_for_task", taskID))
+					return
+				}
+				fmt.Print(T("tags.task", taskID, strings.Join(tags, ", ")))
+				return
+			}
+
+			// Контест и название задачи узнаем до блокировки: это может быть запрос к API
+			info := TaggedTask{TaskID: taskID}
+			if known, ok := store.Tasks[key]; ok {
+				info.ContestID, info.Name = known.ContestID, known.Name
+			}
+			if !clear && !remove {
+				v.fillTaggedTaskInfo(cmd.Context(), &info, contestID)
+			}
+
+			store, err = UpdateTags(func(store *TagStore) {
+				task, exists := store.Tasks[key]
+				if !exists {
+					task = &TaggedTask{TaskID: taskID}
+					store.Tasks[key] = task
+				}
+
+				switch {
+				case clear:
+					delete(store.Tasks, key)
+				case remove:
+					removeSet := make(map[string]bool)
+					for _, tag := range parseTagList(args[1]) {
+						removeSet[tag] = true
+					}
+					var kept []string
+					for _, tag := range task.Tags {
+						if !removeSet[tag] {
+							kept = append(kept, tag)
+						}
+					}
+					task.Tags = kept
+					if len(task.Tags) == 0 {
+						delete(store.Tasks, key)
+					}
+				default:
+					for _, tag := range parseTagList(args[1]) {
+						if !store.HasTag(taskID, tag) {
+							task.Tags = append(task.Tags, tag)
+						}
+					}
+					sort.Strings(task.Tags)
+					task.ContestID = cmp.Or(info.ContestID, task.ContestID)
+					task.Name = cmp.Or(task.Name, info.Name)
+				}
+			})
+			if err != nil {
+				fmt.Print(T("tags.save_failed", err))
+				return
+			}
+
+			if tags := store.TagsFor(taskID); len(tags) > 0 {
+				fmt.Print(T("tags.saved", taskID, strings.Join(tags, ", ")))
+			} else {
+				fmt.Print(T("tags.cleared", taskID))
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&contestID, "contest", "c", "", T("flag.tag_contest"))
+	cmd.Flags().BoolVar(&remove, "remove", false, T("flag.tag_remove"))
+	cmd.Flags().BoolVar(&clear, "clear", false, T("flag.tag_clear"))
+
+	return cmd
+}
+
+// Запоминаем контест и название задачи, чтобы статистика не требовала лишних запросов
+func (v *VSCodeExtension) fillTaggedTaskInfo(ctx context.Context, task *TaggedTask, contestID string) {
+	if contestID == "" {
+		contestID = v.config.CurrentContest
+	}
+	if contestID != "" {
+		task.ContestID = contestID
+	}
+	if task.ContestID == "" || task.Name != "" || !v.apiClient.IsAuthenticated() || v.config.LowBandwidth {
+		return
+	}
+
+	contestInfo, err := v.apiClient.GetContestInfo(ctx, task.ContestID)
+	if err != nil {
+		return
+	}
+	for _, t := range contestInfo.Tasks {
+		if t.ID == task.TaskID {
+			task.Name = t.Name
+			return
+		}
+	}
+}
+
+func printAllTags(store *TagStore) {
+	counts := store.AllTags()
+	if len(counts) == 0 {
+		fmt.Println(T("tags.none"))
+		progressln(T("tags.hint"))
+		return
+	}
+
+	var tags []string
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	fmt.Print(T("tags.header", len(tags)))
+	for _, tag := range tags {
+		fmt.Print(T("tags.count", tag, counts[tag]))
+	}
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gorilla/websocket"
+)
+
+// Запись обмена с API (--record <dir>) и его воспроизведение без сети
+// (--replay <dir>). Каждый запрос - отдельный JSON-файл: метод, адрес, тела
+// запроса и ответа, для WebSocket - полученные кадры. Заголовок Authorization
+// не пишется, session token и поля вроде token или password заменяются
+// на [redacted], так что каталог можно приложить к баг-репорту.
+// При воспроизведении ответы на один и тот же запрос выдаются по порядку,
+// последний повторяется: опрос статуса в цикле тоже воспроизводится
+
+const tapeWebSocket = "WS"
+
+// Заголовки ответа, от которых зависит разбор. Остальные (cookie, адреса
+// серверов) в запись не попадают
+var tapeHeaders = []string{"Content-Type", "Content-Disposition", "ETag", "Last-Modified", "Retry-After"}
+
+// Только то, что выдает вход: в ответах API есть поля code и source,
+// без которых воспроизводить нечего
+var tapeSensitiveKeys = []string{"token", "password", "secret", "session", "cookie"}
+
+type tapeEntry struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	RequestBody string      `json:"request_body,omitempty"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        string      `json:"body,omitempty"`
+	BinaryBody  []byte      `json:"binary_body,omitempty"` // не UTF-8, например архив с тестами
+	Frames      []string    `json:"frames,omitempty"`
+}
+
+type apiTape struct {
+	dir      string
+	replay   bool
+	basePath string
+	redact   func(string) string
+	dialer   wsDialer
+
+	mu  sync.Mutex
+	seq int
+	// Воспроизведение: ответы по ключу "метод адрес" в порядке записи
+	entries map[string][]*tapeEntry
+	// Запись: открытые WebSocket и их файлы
+	streams map[*websocket.Conn]*tapeStream
+}
+
+type tapeStream struct {
+	path  string
+	entry *tapeEntry
+}
+
+// Включает запись или воспроизведение для всех запросов клиента. Кэш
+// отключается: иначе часть ответов не дойдет до записи или возьмется из него
+func (a *APIClient) useTape(dir string, replay bool) error {
+	tape := &apiTape{
+		dir:      dir,
+		replay:   replay,
+		basePath: a.transport.baseURL.Path,
+		redact:   a.redactSecrets,
+		dialer:   a.transport.localDialer,
+		entries:  map[string][]*tapeEntry{},
+		streams:  map[*websocket.Conn]*tapeStream{},
+	}
+	if replay {
+		if err := tape.load(); err != nil {
+			return err
+		}
+		progress(T("tape.replaying", dir, tape.seq))
+	} else {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+		// Несколько команд подряд пишутся в один каталог и воспроизводятся по порядку
+		files, err := tapeFiles(dir)
+		if err != nil {
+			return err
+		}
+		tape.seq = len(files)
+		progress(T("tape.recording", dir))
+	}
+	a.tape = tape
+	a.cache.disabled = true
+	return nil
+}
+
+func tapeFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func (t *apiTape) load() error {
+	files, err := tapeFiles(t.dir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return errors.New(T("tape.empty", t.dir))
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var entry tapeEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		key := tapeKey(entry.Method, entry.URL)
+		t.entries[key] = append(t.entries[key], &entry)
+	}
+	t.seq = len(files)
+	return nil
+}
+
+func tapeKey(method, url string) string {
+	return method + " " + url
+}
+
+// Адрес без базового пути API и токена: запись не зависит от api_base_url
+// и от того, чей токен в конфиге при воспроизведении
+func (t *apiTape) endpoint(requestURI string) string {
+	return t.redact(strings.TrimPrefix(requestURI, t.basePath))
+}
+
+// Следующий ответ на запрос, nil - такого запроса в записи нет
+func (t *apiTape) next(method, endpoint string) *tapeEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := tapeKey(method, endpoint)
+	queue := t.entries[key]
+	if len(queue) == 0 {
+		return nil
+	}
+	if len(queue) > 1 {
+		t.entries[key] = queue[1:]
+	}
+	return queue[0]
+}
+
+func (t *apiTape) missing(method, endpoint string) error {
+	return errors.New(T("tape.missing", t.dir, method, endpoint))
+}
+
+// Ответ из записи вместо запроса к серверу
+func (t *apiTape) response(req *http.Request) (*http.Response, error) {
+	endpoint := t.endpoint(req.URL.RequestURI())
+	entry := t.next(req.Method, endpoint)
+	if entry == nil {
+		return nil, t.missing(req.Method, endpoint)
+	}
+	body := []byte(entry.Body)
+	if entry.BinaryBody != nil {
+		body = entry.BinaryBody
+	}
+	header := entry.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.Status, http.StatusText(entry.Status)),
+		StatusCode:    entry.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// Записывает запрос и ответ. Тело ответа читается целиком и подменяется копией
+func (t *apiTape) record(req *http.Request, resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry := &tapeEntry{
+		Method: req.Method,
+		URL:    t.endpoint(req.URL.RequestURI()),
+		Status: resp.StatusCode,
+		Header: http.Header{},
+	}
+	for _, name := range tapeHeaders {
+		if values := resp.Header.Values(name); len(values) > 0 {
+			entry.Header[name] = values
+		}
+	}
+	if req.GetBody != nil {
+		if reader, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(reader)
+			reader.Close()
+			entry.RequestBody = string(t.sanitize(data))
+		}
+	}
+	if sanitized := t.sanitize(body); utf8.Valid(sanitized) {
+		entry.Body = string(sanitized)
+	} else {
+		entry.BinaryBody = sanitized
+	}
+	t.save(entry)
+	return resp, nil
+}
+
+// Убирает токен и значения полей вроде token и password. JSON без таких
+// полей остается байт в байт: порядок ключей тоже бывает причиной ошибки
+func (t *apiTape) sanitize(data []byte) []byte {
+	if !utf8.Valid(data) {
+		return data
+	}
+	data = []byte(t.redact(string(data)))
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var payload interface{}
+	if decoder.Decode(&payload) != nil {
+		return data
+	}
+	sensitive := func(key string) bool {
+		key = strings.ToLower(key)
+		return slices.ContainsFunc(tapeSensitiveKeys, func(s string) bool { return strings.Contains(key, s) })
+	}
+	if !redactPayload(payload, sensitive) {
+		return data
+	}
+	if sanitized, err := json.Marshal(payload); err == nil {
+		return sanitized
+	}
+	return data
+}
+
+// Имя файла: порядковый номер, метод и последний сегмент пути
+func (t *apiTape) fileName(entry *tapeEntry) string {
+	t.mu.Lock()
+	t.seq++
+	seq := t.seq
+	t.mu.Unlock()
+
+	name := path.Base(strings.SplitN(entry.URL, "?", 2)[0])
+	name = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return -1
+	}, name)
+	return filepath.Join(t.dir, fmt.Sprintf("%04d-%s-%s.json", seq, entry.Method, name))
+}
+
+// Ошибки записи только печатаются: команда должна отработать как обычно
+func (t *apiTape) save(entry *tapeEntry) string {
+	path := t.fileName(entry)
+	t.write(path, entry)
+	return path
+}
+
+func (t *apiTape) write(path string, entry *tapeEntry) {
+	// Без экранирования & в адресах: файлы читают глазами
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(entry)
+	if err == nil {
+		err = writeFileAtomic(path, buf.Bytes())
+	}
+	if err != nil {
+		progress(T("tape.write_failed", t.dir, err))
+	}
+}
+
+// Запоминает открытый WebSocket, кадры дописываются в его файл по мере чтения
+func (t *apiTape) openStream(conn *websocket.Conn, endpoint string) {
+	entry := &tapeEntry{Method: tapeWebSocket, URL: t.endpoint(endpoint), Status: http.StatusSwitchingProtocols}
+	path := t.save(entry)
+	t.mu.Lock()
+	t.streams[conn] = &tapeStream{path: path, entry: entry}
+	t.mu.Unlock()
+}
+
+// Кадр, прочитанный из WebSocket. Без записи ничего не делает
+func (t *apiTape) frame(conn *websocket.Conn, message []byte) {
+	if t == nil || t.replay {
+		return
+	}
+	t.mu.Lock()
+	stream := t.streams[conn]
+	if stream == nil {
+		t.mu.Unlock()
+		return
+	}
+	stream.entry.Frames = append(stream.entry.Frames, string(t.sanitize(message)))
+	entry := *stream.entry
+	t.mu.Unlock()
+	t.write(stream.path, &entry)
+}
+
+// WebSocket из записи: локальный сервер отдает записанные кадры и закрывает
+// соединение, как сервер после финального вердикта
+func (t *apiTape) dialWebSocket(ctx context.Context, endpoint string) (*websocket.Conn, error) {
+	endpoint = t.endpoint(endpoint)
+	entry := t.next(tapeWebSocket, endpoint)
+	if entry == nil {
+		return nil, t.missing(tapeWebSocket, endpoint)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	// Уже принятое соединение живет и после закрытия listener
+	defer listener.Close()
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for _, frame := range entry.Frames {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(frame)); err != nil {
+				return
+			}
+		}
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	})}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, net.ErrClosed) {
+			logger.Debug("сервер записи WebSocket", "error", err)
+		}
+	}()
+
+	conn, _, err := t.dialer.DialContext(ctx, "ws://"+listener.Addr().String()+"/", nil)
+	return conn, err
+}
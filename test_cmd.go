@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type TestOptions struct {
+	Language string
+	TestsDir string
+	Timeout  time.Duration
+	Only     []string // виды тестов (sample, custom, gen, stress), пусто - все
+}
+
+func (v *VSCodeExtension) createTestCommand() *cobra.Command {
+	var opts TestOptions
+
+	cmd := &cobra.Command{
+		Use:   "test [file]",
+		Short: T("test.short"),
+		Long:  T("test.long"),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			filename := args[0]
+			if opts.TestsDir == "" {
+				opts.TestsDir = filepath.Join(filepath.Dir(filename), "tests")
+			}
+			for _, kind := range opts.Only {
+				if !slices.Contains(testKinds, kind) {
+					return errors.New(T("test.unknown_kind", kind, strings.Join(testKinds, ", ")))
+				}
+			}
+			passed, total, err := v.handleTest(cmd.Context(), filename, opts)
+			if err != nil {
+				return err
+			}
+			if passed < total {
+				return errors.New(T("test.failed_count", total-passed, total))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Language, "language", "l", "", T("flag.language"))
+	cmd.Flags().StringVarP(&opts.TestsDir, "tests", "t", "", T("flag.test_dir"))
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 5*time.Second, T("flag.test_timeout"))
+	cmd.Flags().StringSliceVar(&opts.Only, "only", nil, T("flag.test_only"))
+
+	return cmd
+}
+
+func (v *VSCodeExtension) handleTest(ctx context.Context, filename string, opts TestOptions) (int, int, error) {
+	language, testsDir, timeout := opts.Language, opts.TestsDir, opts.Timeout
+
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return 0, 0, errors.New(T("file.not_found", filename))
+	}
+
+	if language == "" {
+		language = v.apiClient.DetectLanguage(filename)
+		if language == "unknown" {
+			return 0, 0, errors.New(T("test.language_unknown"))
+		}
+	}
+
+	tests, err := loadTestCases(testsDir)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(opts.Only) > 0 {
+		tests = slices.DeleteFunc(tests, func(test TestCase) bool {
+			return !slices.Contains(opts.Only, testKind(test.Name))
+		})
+		if len(tests) == 0 {
+			fmt.Print(T("test.none_of_kind", testsDir, strings.Join(opts.Only, ", ")))
+			return 0, 0, nil
+		}
+	}
+	if len(tests) == 0 {
+		fmt.Print(T("test.none", testsDir))
+		return 0, 0, nil
+	}
+
+	progress(T("test.compiling", filename, language))
+	solution, err := PrepareSolution(filename, language, v.config.Languages)
+	if err != nil {
+		var compileErr *CompileError
+		if errors.As(err, &compileErr) {
+			fmt.Print(T("test.compile_error", compileErr.Output))
+			fmt.Print(T("test.local_verdict", getStatusEmoji("compilation_error")))
+		}
+		return 0, 0, err
+	}
+	defer solution.Cleanup()
+
+	// Ограничения задачи, если файл привязан к ней через .sortme.yaml
+	statement := v.workspaceStatement(ctx, filename)
+	if statement != nil && (statement.TimeLimit > 0 || statement.MemoryLimit > 0) {
+		fmt.Print(T("test.limits", statement.TimeLimit, statement.MemoryLimit))
+	}
+
+	progress(T("test.running", len(tests)))
+
+	passed := 0
+	verdicts := make([]localVerdict, 0, len(tests))
+	var slowest, hungriest *RunResult
+	var slowestTest, hungriestTest string
+	for _, test := range tests {
+		verdict := localVerdict{Test: test.Name}
+		input, err := os.ReadFile(test.InputPath)
+		if err != nil {
+			progress(T("test.input_failed", test.Name, err))
+			verdicts = append(verdicts, verdict)
+			continue
+		}
+
+		result := solution.Run(input, timeout)
+		timeInfo := formatRunUsage(result)
+		if !result.TimedOut && (slowest == nil || result.Duration > slowest.Duration) {
+			slowest, slowestTest = result, test.Name
+		}
+		if hungriest == nil || result.PeakRSS > hungriest.PeakRSS {
+			hungriest, hungriestTest = result, test.Name
+		}
+
+		switch {
+		case result.TimedOut:
+			verdict.Status = "time_limit_exceeded"
+			fmt.Printf("  ⏰ %-12s TLE (> %s)\n", test.Name, timeout)
+		case result.Err != nil:
+			verdict.Status = "runtime_error"
+			fmt.Print(T("test.run_failed", test.Name, result.Err))
+		case result.ExitCode != 0:
+			verdict.Status = "runtime_error"
+			fmt.Print(T("test.runtime_error", test.Name, result.ExitCode, timeInfo))
+			printStderrTail(result.Stderr)
+		case test.AnswerPath == "":
+			passed++
+			fmt.Print(T("test.no_answer", test.Name, timeInfo))
+			printIndented(string(result.Output))
+		default:
+			expected, err := os.ReadFile(test.AnswerPath)
+			if err != nil {
+				fmt.Print(T("test.answer_failed", test.Name, err))
+				break
+			}
+			if outputsMatch(expected, result.Output) {
+				passed++
+				verdict.Status = "accepted"
+				fmt.Printf("  ✅ %-12s PASS %s\n", test.Name, timeInfo)
+			} else {
+				verdict.Status = "wrong_answer"
+				fmt.Printf("  ❌ %-12s FAIL %s\n", test.Name, timeInfo)
+				printOutputDiff(expected, result.Output)
+			}
+		}
+		for _, warning := range localLimitWarnings(result, statement, v.config.LimitWarning) {
+			progressln(warning)
+		}
+		verdicts = append(verdicts, verdict)
+	}
+
+	fmt.Print(T("test.passed", passed, len(tests)))
+	if slowest != nil {
+		fmt.Print(T("test.max_time", slowest.Duration.Milliseconds(), slowestTest))
+	}
+	if hungriest != nil && hungriest.PeakRSS > 0 {
+		fmt.Print(T("test.max_memory", formatPeakRSS(hungriest.PeakRSS), hungriestTest))
+	}
+	v.printLocalJudgement(ctx, filename, verdicts)
+	return passed, len(tests), nil
+}
+
+// Время и пиковая память запуска: "103 мс, 12.4 МБ"
+func formatRunUsage(result *RunResult) string {
+	usage := T("unit.ms", result.Duration.Milliseconds())
+	if result.PeakRSS > 0 {
+		usage += ", " + formatPeakRSS(result.PeakRSS)
+	}
+	return usage
+}
+
+func formatPeakRSS(bytes int64) string {
+	return T("unit.mb", float64(bytes)/(1<<20))
+}
+
+// Построчное сравнение ожидаемого и полученного вывода
+func printOutputDiff(expected, actual []byte) {
+	exp := normalizeOutputLines(expected)
+	act := normalizeOutputLines(actual)
+
+	maxLines := len(exp)
+	if len(act) > maxLines {
+		maxLines = len(act)
+	}
+
+	shown := 0
+	for i := 0; i < maxLines; i++ {
+		var e, a string
+		if i < len(exp) {
+			e = exp[i]
+		}
+		if i < len(act) {
+			a = act[i]
+		}
+		if e == a {
+			continue
+		}
+		if shown >= 10 {
+			fmt.Println(T("test.diff_more"))
+			break
+		}
+		fmt.Print(T("test.diff_line", i+1))
+		if i < len(exp) {
+			fmt.Printf("         - %s\n", e)
+		} else {
+			fmt.Println("         - " + T("test.diff_no_line"))
+		}
+		if i < len(act) {
+			fmt.Printf("         + %s\n", a)
+		} else {
+			fmt.Println("         + " + T("test.diff_no_line"))
+		}
+		shown++
+	}
+}
+
+func printIndented(text string) {
+	for _, line := range normalizeOutputLines([]byte(text)) {
+		fmt.Printf("       %s\n", line)
+	}
+}
+
+// Последние строки stderr помогают понять причину падения
+func printStderrTail(stderr []byte) {
+	lines := normalizeOutputLines(stderr)
+	if len(lines) > 5 {
+		lines = lines[len(lines)-5:]
+	}
+	if len(lines) > 0 {
+		printIndented(strings.Join(lines, "\n"))
+	}
+}
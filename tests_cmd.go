@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// Свои тесты лежат в том же tests/, что и примеры, с префиксом custom,
+// поэтому sortme test запускает их вместе со всеми остальными
+const customTestPrefix = "custom"
+
+// Виды тестов по префиксу имени файла
+var testKinds = []string{"sample", "custom", "gen", "stress"}
+
+func testKind(name string) string {
+	for _, kind := range testKinds {
+		if strings.HasPrefix(name, kind) {
+			return kind
+		}
+	}
+	return "other"
+}
+
+func (v *VSCodeExtension) createTestsCommand() *cobra.Command {
+	var testsDir string
+
+	cmd := &cobra.Command{
+		Use:   "tests",
+		Short: T("tests.short"),
+		Long:  T("tests.long"),
+	}
+	cmd.PersistentFlags().StringVarP(&testsDir, "tests", "t", "", T("flag.tests_dir"))
+	cmd.AddCommand(
+		v.createTestsAddCommand(&testsDir),
+		v.createTestsListCommand(&testsDir),
+		v.createTestsRemoveCommand(&testsDir),
+	)
+	return cmd
+}
+
+// tests/ каталога задачи из ближайшего .sortme.yaml, иначе tests/ в текущем каталоге
+func defaultTestsDir(explicit string) (string, error) {
+	if explicit != "" {
+		return explicit, nil
+	}
+	binding, _, err := findWorkspace(".")
+	if err != nil {
+		return "", err
+	}
+	if binding != nil && (binding.ProblemID != "" || binding.File != "") {
+		return filepath.Join(binding.dir, "tests"), nil
+	}
+	return "tests", nil
+}
+
+func (v *VSCodeExtension) createTestsAddCommand(testsDir *string) *cobra.Command {
+	var inputFile, answerFile string
+
+	cmd := &cobra.Command{
+		Use:   "add [name]",
+		Short: T("tests.add_short"),
+		Long:  T("tests.add_long"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			dir, err := defaultTestsDir(*testsDir)
+			if err != nil {
+				return err
+			}
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			return handleTestsAdd(dir, name, inputFile, answerFile)
+		},
+	}
+
+	cmd.Flags().StringVarP(&inputFile, "input", "i", "", T("flag.tests_input"))
+	cmd.Flags().StringVarP(&answerFile, "answer", "a", "", T("flag.tests_answer"))
+	return cmd
+}
+
+func (v *VSCodeExtension) createTestsListCommand(testsDir *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: T("tests.list_short"),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			dir, err := defaultTestsDir(*testsDir)
+			if err != nil {
+				return err
+			}
+			return v.handleTestsList(dir)
+		},
+	}
+}
+
+func (v *VSCodeExtension) createTestsRemoveCommand(testsDir *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <name>...",
+		Short: T("tests.rm_short"),
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			dir, err := defaultTestsDir(*testsDir)
+			if err != nil {
+				return err
+			}
+			return handleTestsRemove(dir, args)
+		},
+	}
+}
+
+// Имя своего теста: custom-<name> или первое свободное customN
+func customTestName(dir, name string) (string, error) {
+	if name != "" {
+		if strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+			return "", errors.New(T("tests.bad_name", name))
+		}
+		if !strings.HasPrefix(name, customTestPrefix) {
+			name = customTestPrefix + "-" + name
+		}
+		return name, nil
+	}
+	for n := 1; ; n++ {
+		name := fmt.Sprintf("%s%d", customTestPrefix, n)
+		if _, err := os.Stat(filepath.Join(dir, name+".in")); os.IsNotExist(err) {
+			return name, nil
+		}
+	}
+}
+
+// Вход из файла, а без него - из stdin (в терминале с подсказкой)
+func readTestInput(filename, prompt string) ([]byte, error) {
+	if filename != "" && filename != "-" {
+		return os.ReadFile(filename)
+	}
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Println(prompt)
+	}
+	return io.ReadAll(os.Stdin)
+}
+
+func handleTestsAdd(dir, name, inputFile, answerFile string) error {
+	if inputFile == "-" && answerFile == "-" {
+		return errors.New(T("tests.stdin_twice"))
+	}
+	name, err := customTestName(dir, name)
+	if err != nil {
+		return err
+	}
+	base := filepath.Join(dir, name)
+	if _, err := os.Stat(base + ".in"); err == nil {
+		return errors.New(T("tests.exists", name, name))
+	}
+
+	input, err := readTestInput(inputFile, T("tests.input_prompt"))
+	if err != nil {
+		return fmt.Errorf("%s: %w", T("tests.input_read_error"), err)
+	}
+	if len(bytes.TrimSpace(input)) == 0 {
+		return errors.New(T("tests.input_empty"))
+	}
+	var answer []byte
+	if answerFile != "" {
+		if answer, err = readTestInput(answerFile, T("tests.answer_prompt")); err != nil {
+			return fmt.Errorf("%s: %w", T("tests.answer_read_error"), err)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(base+".in", []byte(ensureTrailingNewline(string(input))), 0644); err != nil {
+		return err
+	}
+	if answer != nil {
+		if err := os.WriteFile(base+".out", []byte(ensureTrailingNewline(string(answer))), 0644); err != nil {
+			return err
+		}
+		fmt.Print(T("tests.saved", name, base, base))
+	} else {
+		fmt.Print(T("tests.saved_no_answer", name, base))
+	}
+	progressln(T("tests.custom_hint"))
+	return nil
+}
+
+func (v *VSCodeExtension) handleTestsList(dir string) error {
+	tests, err := loadTestCases(dir)
+	if err != nil {
+		return err
+	}
+
+	if v.jsonMode() {
+		items := make([]map[string]interface{}, 0, len(tests))
+		for _, test := range tests {
+			items = append(items, map[string]interface{}{
+				"name":       test.Name,
+				"kind":       testKind(test.Name),
+				"input":      test.InputPath,
+				"answer":     test.AnswerPath,
+				"has_answer": test.AnswerPath != "",
+			})
+		}
+		v.emitJSON(map[string]interface{}{"dir": dir, "tests": items})
+	}
+
+	if len(tests) == 0 {
+		fmt.Print(T("tests.none", dir))
+		progressln(T("tests.add_hint"))
+		return nil
+	}
+
+	fmt.Print(T("tests.header", dir, len(tests)))
+	fmt.Println("┌──────────────────┬────────┬──────────┬───────┬──────────────────────────┐")
+	fmt.Printf("│ %s │ %s │ %s │ %s │ %s │\n", padRunes(T("tests.col_test"), 16), padRunes(T("tests.col_kind"), 6), padRunes(T("tests.col_size"), 8), padRunes(T("tests.col_answer"), 5), padRunes(T("tests.col_input"), 24))
+	fmt.Println("├──────────────────┼────────┼──────────┼───────┼──────────────────────────┤")
+	counts := make(map[string]int)
+	for _, test := range tests {
+		kind := testKind(test.Name)
+		counts[kind]++
+		size, preview := "—", ""
+		if info, err := os.Stat(test.InputPath); err == nil {
+			size = formatTestSize(info.Size())
+			preview = firstLine(test.InputPath)
+		}
+		answer := "—"
+		if test.AnswerPath != "" {
+			answer = "✓"
+		}
+		fmt.Printf("│ %s │ %-6s │ %s │ %s │ %s │\n",
+			padRunes(test.Name, 16), kind, padRunes(size, 8), padRunes(answer, 5), padRunes(preview, 24))
+	}
+	fmt.Println("└──────────────────┴────────┴──────────┴───────┴──────────────────────────┘")
+
+	var summary []string
+	for _, kind := range append(testKinds, "other") {
+		if counts[kind] > 0 {
+			summary = append(summary, fmt.Sprintf("%s: %d", kind, counts[kind]))
+		}
+	}
+	fmt.Println("📊 " + strings.Join(summary, ", "))
+	return nil
+}
+
+func handleTestsRemove(dir string, names []string) error {
+	var missing []string
+	for _, name := range names {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+		base := filepath.Join(dir, name)
+		if _, err := os.Stat(base + ".in"); os.IsNotExist(err) && !strings.HasPrefix(name, customTestPrefix) {
+			// "sortme tests rm big" для теста, добавленного как "tests add big"
+			base = filepath.Join(dir, customTestPrefix+"-"+name)
+		}
+
+		removed := 0
+		for _, ext := range []string{".in", ".out", ".ans"} {
+			err := os.Remove(base + ext)
+			if err == nil {
+				removed++
+			} else if !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if removed == 0 {
+			missing = append(missing, name)
+			continue
+		}
+		fmt.Print(T("tests.removed", filepath.Base(base)))
+	}
+	if len(missing) > 0 {
+		return errors.New(T("tests.not_found", dir, strings.Join(missing, ", ")))
+	}
+	return nil
+}
+
+func formatTestSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return T("unit.mb", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return T("unit.kb", float64(size)/(1<<10))
+	}
+	return T("unit.b", size)
+}
+
+// Первая строка файла без чтения его целиком: входы бывают на десятки мегабайт
+func firstLine(filename string) string {
+	file, err := os.Open(filename)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	line, _ := bufio.NewReader(io.LimitReader(file, 256)).ReadString('\n')
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,448 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultAPIURL = "https://api.sort-me.org"
+	// Значение api_base_url, которое старые версии записывали в конфиг
+	legacyAPIBaseURL = "https://sort-me.org/api"
+	// Адрес сервера API на случай проблем с DNS
+	defaultAPIIP = "94.103.85.238"
+)
+
+// Единый транспорт к API: базовый URL, необязательный IP сервера, прокси
+// и запасной режим без проверки сертификата
+type apiTransport struct {
+	baseURL       *url.URL
+	ip            string
+	proxy         proxyFunc // nil - без прокси
+	allowInsecure bool
+
+	client         *http.Client
+	insecureClient *http.Client
+	external       http.RoundTripper
+	dialer         wsDialer
+	insecureDialer wsDialer
+	localDialer    wsDialer // к локальному серверу --replay, без прокси
+	// Сертификат уже не прошел проверку, дальше сразу ходим без нее
+	useInsecure atomic.Bool
+}
+
+// Подключение к WebSocket, *websocket.Dialer подходит как есть
+type wsDialer interface {
+	DialContext(ctx context.Context, urlStr string, requestHeader http.Header) (*websocket.Conn, *http.Response, error)
+}
+
+// Внешние зависимости клиента API. Пустое поле - настоящая сеть или часы;
+// тест подставляет свои, чтобы проверить повторы, разбор ответов
+// и таймауты без сервера. Подставной транспорт заменяет только соединения:
+// gzip, повторы и запасной режим без проверки сертификата остаются
+type apiDeps struct {
+	Transport http.RoundTripper
+	Dialer    wsDialer
+	Clock     clock
+}
+
+func newAPITransport(config *Config, deps apiDeps) *apiTransport {
+	base, err := url.Parse(strings.TrimRight(config.APIBaseURL, "/"))
+	if err != nil || base.Host == "" {
+		base, _ = url.Parse(defaultAPIURL)
+	}
+
+	t := &apiTransport{
+		baseURL:       base,
+		ip:            strings.TrimSpace(config.APIIP),
+		proxy:         newProxyFunc(config.Proxy),
+		allowInsecure: config.InsecureTLS,
+	}
+	t.client, t.dialer = t.newClient(deps.Transport, false), t.newDialer(deps.Dialer, false)
+	if t.allowInsecure {
+		t.insecureClient, t.insecureDialer = t.newClient(deps.Transport, true), t.newDialer(deps.Dialer, true)
+	}
+	t.localDialer = deps.Dialer
+	if t.localDialer == nil {
+		t.localDialer = &websocket.Dialer{HandshakeTimeout: 10 * time.Second}
+	}
+	t.external = deps.Transport
+	if t.external == nil {
+		t.external = &http.Transport{
+			Proxy:               t.proxy,
+			DialContext:         (&net.Dialer{Timeout: 10 * time.Second}).DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		}
+	}
+	return t
+}
+
+// Клиент для адресов вне API: картинки условий, Telegram, webhooks, хранилище
+// состояния. Прокси и подставной транспорт те же, но без IP сервера API
+// и запасного режима без проверки сертификата
+func (t *apiTransport) externalClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: t.external}
+}
+
+func (t *apiTransport) newClient(base http.RoundTripper, insecure bool) *http.Client {
+	if base == nil {
+		base = &http.Transport{
+			Proxy:               t.proxy,
+			DialContext:         t.dialContext,
+			TLSClientConfig:     t.tlsConfig(insecure),
+			TLSHandshakeTimeout: 10 * time.Second,
+		}
+	}
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: gzipTransport{base},
+	}
+}
+
+// Dialer WebSocket с теми же настройками, что и у HTTP
+func (t *apiTransport) newDialer(dialer wsDialer, insecure bool) wsDialer {
+	if dialer != nil {
+		return dialer
+	}
+	return &websocket.Dialer{
+		NetDialContext:   t.dialContext,
+		Proxy:            t.proxy.websocket(),
+		HandshakeTimeout: 10 * time.Second,
+		TLSClientConfig:  t.tlsConfig(insecure),
+	}
+}
+
+// Явно просит gzip и распаковывает ответ сам. http.Transport умеет это
+// и без нас, но молча; так в журнале (-vv) видно, сколько пришло по сети:
+// список архива и условия сжимаются в разы, на мобильном интернете это заметно
+type gzipTransport struct {
+	base http.RoundTripper
+}
+
+func (g gzipTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Запрос свой Accept-Encoding не задал: остальное - его дело
+	if req.Header.Get("Accept-Encoding") != "" || req.Header.Get("Range") != "" {
+		return g.base.RoundTrip(req)
+	}
+	// RoundTrip не должен менять чужой запрос
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := g.base.RoundTrip(req)
+	if err != nil || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") ||
+		req.Method == http.MethodHead || resp.ContentLength == 0 {
+		return resp, err
+	}
+
+	raw := &countingReader{r: resp.Body}
+	reader, err := gzip.NewReader(raw)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %w", T("transport.bad_gzip", req.URL.Path), err)
+	}
+	resp.Body = &gzipBody{reader: reader, raw: raw, closer: resp.Body, path: req.URL.Path}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Распакованное тело ответа, при закрытии пишет в журнал степень сжатия
+type gzipBody struct {
+	reader *gzip.Reader
+	raw    *countingReader
+	closer io.Closer
+	path   string
+	size   int64
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	n, err := b.reader.Read(p)
+	b.size += int64(n)
+	return n, err
+}
+
+func (b *gzipBody) Close() error {
+	logger.Debug("ответ сжат", "path", b.path, "compressed", b.raw.n, "size", b.size)
+	return b.closer.Close()
+}
+
+func (t *apiTransport) tlsConfig(insecure bool) *tls.Config {
+	// SNI и проверка сертификата идут по имени хоста, даже если соединяемся по IP
+	return &tls.Config{
+		ServerName:         t.baseURL.Hostname(),
+		InsecureSkipVerify: insecure,
+	}
+}
+
+// Соединяется с заданным IP вместо результата DNS. Если IP не отвечает
+// (сервер переехал), пробуем обычное имя хоста. Через прокси сюда приходит
+// адрес самого прокси, а имя сервера API разрешает уже он
+func (t *apiTransport) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+
+	host, port, err := net.SplitHostPort(addr)
+	if t.ip == "" || err != nil || host != t.baseURL.Hostname() {
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(t.ip, port))
+	if err == nil {
+		return conn, nil
+	}
+	return dialer.DialContext(ctx, network, addr)
+}
+
+// Полный URL для endpoint вида "/getContestTasks?id=1"
+func (t *apiTransport) URL(endpoint string) string {
+	return t.baseURL.String() + endpoint
+}
+
+func (t *apiTransport) Do(req *http.Request) (*http.Response, error) {
+	if t.useInsecure.Load() {
+		return t.insecureClient.Do(req)
+	}
+
+	resp, err := t.client.Do(req)
+	if err == nil || !t.allowInsecure || !isCertificateError(err) {
+		return resp, err
+	}
+
+	// Сертификат не подошел (например, сервер отвечает по IP со своим сертификатом)
+	retry, rerr := cloneRequest(req)
+	if rerr != nil {
+		return nil, err
+	}
+	t.useInsecure.Store(true)
+	return t.insecureClient.Do(retry)
+}
+
+func isCertificateError(err error) bool {
+	var verifyErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &verifyErr) || errors.As(err, &unknownAuthority) ||
+		errors.As(err, &hostnameErr) || errors.As(err, &invalidErr)
+}
+
+// Копия запроса с заново открытым телом для повторной отправки
+func cloneRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return clone, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New(T("transport.body_not_replayable"))
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone.Body = body
+	return clone, nil
+}
+
+// WebSocket URL для endpoint
+func (t *apiTransport) websocketURL(endpoint string) string {
+	scheme := "wss"
+	if t.baseURL.Scheme == "http" {
+		scheme = "ws"
+	}
+	return scheme + "://" + t.baseURL.Host + t.baseURL.Path + endpoint
+}
+
+func (t *apiTransport) websocketDialer() wsDialer {
+	if t.useInsecure.Load() {
+		return t.insecureDialer
+	}
+	return t.dialer
+}
+
+func (a *APIClient) dialWebSocket(ctx context.Context, endpoint string) (*websocket.Conn, error) {
+	if a.session.expired.Load() {
+		return nil, errSessionExpired
+	}
+	if err := a.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	var conn *websocket.Conn
+	var resp *http.Response
+	var err error
+	if a.tape != nil && a.tape.replay {
+		conn, err = a.tape.dialWebSocket(ctx, endpoint)
+	} else {
+		conn, resp, err = a.transport.dialWebSocket(ctx, endpoint)
+		if err == nil && a.tape != nil {
+			a.tape.openStream(conn, endpoint)
+		}
+	}
+	path := endpoint
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+	a.usage.record(path, 0, err)
+	if err != nil && a.checkUnauthorized(resp) {
+		return nil, errSessionExpired
+	}
+	return conn, err
+}
+
+func (t *apiTransport) dialWebSocket(ctx context.Context, endpoint string) (*websocket.Conn, *http.Response, error) {
+	started := time.Now()
+	conn, resp, err := t.websocketDialer().DialContext(ctx, t.websocketURL(endpoint), nil)
+	if err != nil && !t.useInsecure.Load() && t.allowInsecure && isCertificateError(err) {
+		t.useInsecure.Store(true)
+		conn, resp, err = t.websocketDialer().DialContext(ctx, t.websocketURL(endpoint), nil)
+	}
+	if u, perr := url.Parse(t.websocketURL(endpoint)); perr == nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		logRequest("WS", u, status, started, err)
+	}
+	return conn, resp, err
+}
+
+// ReadMessage не знает о ctx: закрытие соединения при отмене прерывает чтение.
+// Возвращает функцию, которая перестает следить за ctx
+func closeOnCancel(ctx context.Context, conn *websocket.Conn) func() {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	return func() { close(done) }
+}
+
+// Запрос к API с токеном пользователя
+func (a *APIClient) newRequest(ctx context.Context, method, endpoint string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, a.transport.URL(endpoint), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.config.SessionToken)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// Запрос к серверу или ответ из записи (--record, --replay)
+func (a *APIClient) roundTrip(req *http.Request) (*http.Response, error) {
+	if a.tape == nil {
+		return a.transport.Do(req)
+	}
+	if a.tape.replay {
+		return a.tape.response(req)
+	}
+	resp, err := a.transport.Do(req)
+	if err != nil {
+		return resp, err
+	}
+	return a.tape.record(req, resp)
+}
+
+func (a *APIClient) doRequest(req *http.Request) (*http.Response, error) {
+	// Токен уже отвергнут: остальные запросы тоже получат 401
+	if a.session.expired.Load() {
+		return nil, errSessionExpired
+	}
+	resp, err := a.doWithRetry(req)
+	if a.checkUnauthorized(resp) {
+		return nil, errSessionExpired
+	}
+	// Повторы исчерпаны, а сервер все еще ограничивает: пустой ответ не выдаем за настоящий
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		resp.Body.Close()
+		delay, _ := retryAfterDelay(resp)
+		return nil, &ErrRateLimited{RetryAfter: delay}
+	}
+	return resp, err
+}
+
+// GET запрос к API, возвращает тело и код ответа. Запрос условный,
+// если прошлый ответ сохранен с валидаторами: на 304 отдается сохраненное тело
+func (a *APIClient) get(ctx context.Context, endpoint string) ([]byte, int, error) {
+	req, err := a.newRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	stored := a.setValidators(req, endpoint)
+
+	resp, err := a.doRequest(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && stored != nil {
+		logger.Debug("не изменился", "url", redactURL(req.URL))
+		return stored.Body, http.StatusOK, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	logger.Debug("ответ", "url", redactURL(req.URL), "body", logBody(body))
+	if resp.StatusCode == http.StatusOK {
+		a.storeValidated(endpoint, resp, body)
+	}
+	return body, resp.StatusCode, nil
+}
+
+// POST запрос к API с JSON телом (nil - без тела)
+func (a *APIClient) post(ctx context.Context, endpoint string, data []byte) ([]byte, int, error) {
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+	req, err := a.newRequest(ctx, "POST", endpoint, body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := a.doRequest(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	logger.Debug("ответ", "url", redactURL(req.URL), "body", logBody(respBody))
+	return respBody, resp.StatusCode, nil
+}
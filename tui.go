@@ -0,0 +1,504 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// Полноэкранная панель на время контеста: контесты, задачи с отметками,
+// последние отправки и живой поток вердиктов в одном окне вместо
+// contests, problems, list и monitor по отдельности
+const (
+	tuiRecentLimit = 30  // сколько последних отправок контеста показывать
+	tuiLogLimit    = 200 // сколько строк потока вердиктов хранить
+)
+
+type tuiPane int
+
+const (
+	tuiContestsPane tuiPane = iota
+	tuiProblemsPane
+	tuiSubmissionsPane
+	tuiVerdictsPane
+	tuiPaneCount
+)
+
+var tuiPaneTitles = [tuiPaneCount]string{"tui.pane_contests", "tui.pane_tasks", "tui.pane_submissions", "tui.pane_verdicts"}
+
+// Результаты фоновых запросов приходят в Update сообщениями
+type (
+	tuiContestsMsg struct {
+		contests []Contest
+		err      error
+	}
+	tuiContestMsg struct {
+		contestID string
+		info      *ContestInfo
+		progress  *ContestProgress
+		err       error
+	}
+	tuiSubmissionsMsg struct {
+		contestID   string
+		submissions []Submission
+		err         error
+	}
+	tuiVerdictMsg struct {
+		id     int
+		event  VerdictEvent
+		closed bool
+		events <-chan VerdictEvent
+	}
+	tuiRescanMsg struct{}
+)
+
+type tuiModel struct {
+	v        *VSCodeExtension
+	ctx      context.Context
+	renderer *lipgloss.Renderer
+	rescan   time.Duration
+
+	width, height int
+	focus         tuiPane
+	cursor        [tuiPaneCount]int
+
+	contests    []Contest
+	contestsErr error
+	contestID   string
+	info        *ContestInfo
+	progress    *ContestProgress
+	submissions []Submission
+
+	rows   map[int]*monitorRow // отправки, вердикт которых слушаем
+	log    []string            // поток вердиктов, новые в конце
+	status string              // строка внизу: загрузка и ошибки
+}
+
+func (v *VSCodeExtension) createTUICommand() *cobra.Command {
+	var rescan time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "tui [contest_id]",
+		Short: T("tui.short"),
+		Long:  T("tui.long"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			contestID := v.config.CurrentContest
+			if len(args) > 0 {
+				contestID = args[0]
+			}
+			return v.handleTUI(cmd.Context(), contestID, rescan)
+		},
+	}
+
+	cmd.Flags().DurationVar(&rescan, "rescan", monitorDefaultRescan, T("flag.tui_rescan"))
+	return cmd
+}
+
+func (v *VSCodeExtension) handleTUI(ctx context.Context, contestID string, rescan time.Duration) error {
+	if v.jsonMode() {
+		return errors.New(T("tui.json_unsupported"))
+	}
+	if !term.IsTerminal(int(os.Stdout.Fd())) || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return errors.New(T("tui.no_terminal"))
+	}
+	if !v.apiClient.IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+
+	// Запросы к API печатают свой ход в stdout: на время панели он уходит
+	// в никуда, иначе строки лягут поверх экрана. Панель рисуется в терминал
+	screen, stderr := os.Stdout, os.Stderr
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer devNull.Close()
+	os.Stdout, os.Stderr = devNull, devNull
+	defer func() { os.Stdout, os.Stderr = screen, stderr }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	model := &tuiModel{
+		v:         v,
+		ctx:       ctx,
+		renderer:  lipgloss.NewRenderer(screen),
+		rescan:    rescan,
+		contestID: contestID,
+		rows:      make(map[int]*monitorRow),
+	}
+	_, err = tea.NewProgram(model, tea.WithAltScreen(), tea.WithInput(os.Stdin), tea.WithOutput(screen)).Run()
+	return err
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	cmds := []tea.Cmd{m.loadContests(), m.nextRescan()}
+	if m.contestID != "" {
+		cmds = append(cmds, m.openContest(m.contestID))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *tuiModel) loadContests() tea.Cmd {
+	v, ctx := m.v, m.ctx
+	return func() tea.Msg {
+		contests, err := v.apiClient.GetContests(ctx)
+		return tuiContestsMsg{contests: contests, err: err}
+	}
+}
+
+func (m *tuiModel) loadContest(contestID string) tea.Cmd {
+	v, ctx := m.v, m.ctx
+	return func() tea.Msg {
+		info, err := v.apiClient.GetContestInfo(ctx, contestID)
+		if err != nil {
+			return tuiContestMsg{contestID: contestID, err: err}
+		}
+		progress, err := v.apiClient.GetContestProgress(ctx, contestID, info.Tasks)
+		return tuiContestMsg{contestID: contestID, info: info, progress: progress, err: err}
+	}
+}
+
+func (m *tuiModel) loadSubmissions(contestID string) tea.Cmd {
+	v, ctx := m.v, m.ctx
+	return func() tea.Msg {
+		submissions, err := v.apiClient.GetContestSubmissions(ctx, contestID, tuiRecentLimit)
+		return tuiSubmissionsMsg{contestID: contestID, submissions: submissions, err: err}
+	}
+}
+
+func (m *tuiModel) nextRescan() tea.Cmd {
+	if m.rescan <= 0 {
+		return nil
+	}
+	return tea.Tick(m.rescan, func(time.Time) tea.Msg { return tuiRescanMsg{} })
+}
+
+// Ждет следующего состояния отправки из потока WatchSubmission
+func waitVerdict(id int, events <-chan VerdictEvent) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-events
+		return tuiVerdictMsg{id: id, event: event, closed: !ok, events: events}
+	}
+}
+
+func (m *tuiModel) openContest(contestID string) tea.Cmd {
+	if contestID != m.contestID {
+		m.info, m.progress, m.submissions = nil, nil, nil
+		m.cursor[tuiProblemsPane], m.cursor[tuiSubmissionsPane] = 0, 0
+	}
+	m.contestID = contestID
+	m.status = T("tui.loading_contest", contestID)
+	return tea.Batch(m.loadContest(contestID), m.loadSubmissions(contestID))
+}
+
+// Подписывается на вердикт отправки, если еще не подписаны
+func (m *tuiModel) watch(sub Submission) tea.Cmd {
+	if _, ok := m.rows[sub.ID]; ok {
+		return nil
+	}
+	row := &monitorRow{Submission: sub}
+	m.rows[sub.ID] = row
+	events, err := m.v.apiClient.WatchSubmission(m.ctx, strconv.Itoa(sub.ID))
+	if err != nil {
+		row.Err, row.Final = err, true
+		m.addLog(row)
+		return nil
+	}
+	return waitVerdict(sub.ID, events)
+}
+
+func (m *tuiModel) addLog(row *monitorRow) {
+	m.log = append(m.log, time.Now().Format("15:04:05")+" "+formatMonitorRow(row))
+	if len(m.log) > tuiLogLimit {
+		m.log = m.log[len(m.log)-tuiLogLimit:]
+	}
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+
+	case tea.KeyMsg:
+		return m, m.handleKey(msg.String())
+
+	case tuiContestsMsg:
+		m.contests, m.contestsErr = msg.contests, msg.err
+		for i, contest := range m.contests {
+			if contest.ID == m.contestID {
+				m.cursor[tuiContestsPane] = i
+			}
+		}
+
+	case tuiContestMsg:
+		if msg.contestID != m.contestID {
+			break
+		}
+		if msg.err != nil {
+			m.status = T("tui.contest_error", msg.contestID, msg.err)
+		} else {
+			m.status = ""
+		}
+		if msg.info != nil {
+			m.info = msg.info
+		}
+		if msg.progress != nil {
+			m.progress = msg.progress
+		}
+
+	case tuiSubmissionsMsg:
+		if msg.contestID != m.contestID {
+			break
+		}
+		if msg.err != nil {
+			m.status = T("tui.submissions_error", msg.err)
+			break
+		}
+		m.submissions = msg.submissions
+		// Отправки на проверке сразу попадают в поток вердиктов
+		var cmds []tea.Cmd
+		for _, sub := range m.submissions {
+			if isPendingSubmission(sub) {
+				cmds = append(cmds, m.watch(sub))
+			}
+		}
+		return m, tea.Batch(cmds...)
+
+	case tuiVerdictMsg:
+		row := m.rows[msg.id]
+		if row == nil {
+			break
+		}
+		if msg.closed {
+			row.Final = true
+			break
+		}
+		if msg.event.Status != nil {
+			row.Status = msg.event.Status
+		}
+		if msg.event.Err != nil {
+			row.Err = msg.event.Err
+		}
+		row.Final = row.Final || msg.event.Final || msg.event.Err != nil
+		m.addLog(row)
+		cmds := []tea.Cmd{waitVerdict(msg.id, msg.events)}
+		if msg.event.Final && msg.event.Status != nil {
+			sub, status, contestID := row.Submission, msg.event.Status, m.contestID
+			cmds = append(cmds, func() tea.Msg {
+				m.v.notifySubmissionVerdict(sub, status, contestID)
+				return nil
+			})
+		}
+		// Окончательный вердикт меняет список отправок и отметки задач
+		if row.Final && m.contestID != "" {
+			cmds = append(cmds, m.loadContest(m.contestID), m.loadSubmissions(m.contestID))
+		}
+		return m, tea.Batch(cmds...)
+
+	case tuiRescanMsg:
+		cmds := []tea.Cmd{m.nextRescan()}
+		if m.contestID != "" {
+			cmds = append(cmds, m.loadSubmissions(m.contestID))
+		}
+		return m, tea.Batch(cmds...)
+	}
+	return m, nil
+}
+
+func (m *tuiModel) handleKey(key string) tea.Cmd {
+	switch key {
+	case "ctrl+c", "q", "esc":
+		return tea.Quit
+	case "tab", "right", "l":
+		m.focus = (m.focus + 1) % tuiPaneCount
+	case "shift+tab", "left", "h":
+		m.focus = (m.focus + tuiPaneCount - 1) % tuiPaneCount
+	case "up", "k":
+		m.cursor[m.focus] = max(m.cursor[m.focus]-1, 0)
+	case "down", "j":
+		m.cursor[m.focus] = max(min(m.cursor[m.focus]+1, m.itemCount(m.focus)-1), 0)
+	case "r":
+		cmds := []tea.Cmd{m.loadContests()}
+		if m.contestID != "" {
+			cmds = append(cmds, m.openContest(m.contestID))
+		}
+		return tea.Batch(cmds...)
+	case "enter":
+		switch m.focus {
+		case tuiContestsPane:
+			if i := m.cursor[tuiContestsPane]; i < len(m.contests) {
+				m.focus = tuiProblemsPane
+				return m.openContest(m.contests[i].ID)
+			}
+		case tuiSubmissionsPane:
+			// Подробности проверки выбранной отправки в потоке вердиктов
+			if i := m.cursor[tuiSubmissionsPane]; i < len(m.submissions) {
+				delete(m.rows, m.submissions[i].ID)
+				return m.watch(m.submissions[i])
+			}
+		}
+	}
+	return nil
+}
+
+func (m *tuiModel) itemCount(pane tuiPane) int {
+	switch pane {
+	case tuiContestsPane:
+		return len(m.contests)
+	case tuiProblemsPane:
+		if m.info != nil {
+			return len(m.info.Tasks)
+		}
+	case tuiSubmissionsPane:
+		return len(m.submissions)
+	}
+	return 0
+}
+
+func (m *tuiModel) paneLines(pane tuiPane) []string {
+	var lines []string
+	switch pane {
+	case tuiContestsPane:
+		if m.contestsErr != nil {
+			return []string{"⚠️ " + m.contestsErr.Error()}
+		}
+		if m.contests == nil {
+			return []string{T("tui.loading")}
+		}
+		for _, contest := range m.contests {
+			mark := "📦"
+			switch contest.Status {
+			case "active":
+				mark = "🟢"
+			case "upcoming":
+				mark = "🕒"
+			}
+			if contest.ID == m.contestID {
+				mark = "👉"
+			}
+			lines = append(lines, fmt.Sprintf("%s %s %s", mark, contest.ID, contest.Name))
+		}
+
+	case tuiProblemsPane:
+		if m.contestID == "" {
+			return []string{T("tui.select_contest")}
+		}
+		if m.info == nil {
+			return []string{T("tui.loading")}
+		}
+		for i, task := range m.info.Tasks {
+			mark, details := "⬜", ""
+			if m.progress != nil {
+				points, attempts := m.progress.Points[task.ID], m.progress.Attempts[task.ID]
+				switch {
+				case m.progress.Solved[task.ID]:
+					mark = "✅"
+				case points > 0:
+					mark = "🟡"
+				case attempts > 0:
+					mark = "❌"
+				}
+				if attempts > 0 {
+					details = T("tui.task_details", points, attempts)
+				}
+			}
+			lines = append(lines, fmt.Sprintf("%s %s. %s%s", mark, taskLetter(i), task.Name, details))
+		}
+
+	case tuiSubmissionsPane:
+		if m.contestID == "" {
+			return nil
+		}
+		if m.submissions == nil {
+			return []string{T("tui.loading")}
+		}
+		for _, sub := range m.submissions {
+			mark, verdict := getShortStatusEmoji(sub.ShownVerdict), getShortStatusText(sub.ShownVerdict)
+			if isPendingSubmission(sub) {
+				mark, verdict = "⏳", "..."
+			}
+			lines = append(lines, T("tui.submission_line", mark, sub.ID, padRunes(getTaskDisplayName(sub), 20), verdict, sub.TotalPoints))
+		}
+
+	case tuiVerdictsPane:
+		if len(m.log) == 0 {
+			return []string{T("tui.submissions_empty")}
+		}
+		return m.log
+	}
+	return lines
+}
+
+func (m *tuiModel) View() string {
+	if m.width == 0 {
+		return T("tui.loading")
+	}
+	topHeight := (m.height - 1) / 2
+	bottomHeight := m.height - 1 - topHeight
+	left := m.width / 3
+	half := m.width / 2
+
+	top := lipgloss.JoinHorizontal(lipgloss.Top,
+		m.renderPane(tuiContestsPane, left, topHeight),
+		m.renderPane(tuiProblemsPane, m.width-left, topHeight))
+	bottom := lipgloss.JoinHorizontal(lipgloss.Top,
+		m.renderPane(tuiSubmissionsPane, half, bottomHeight),
+		m.renderPane(tuiVerdictsPane, m.width-half, bottomHeight))
+
+	footer := m.status
+	if footer == "" {
+		footer = T("tui.footer")
+	}
+	footer = m.renderer.NewStyle().Faint(true).MaxWidth(m.width).Render(footer)
+	return lipgloss.JoinVertical(lipgloss.Left, top, bottom, footer)
+}
+
+// Панель в рамке размером width x height вместе с рамкой
+func (m *tuiModel) renderPane(pane tuiPane, width, height int) string {
+	innerWidth, innerHeight := max(width-2, 1), max(height-2, 1)
+	focused := pane == m.focus
+
+	title := m.renderer.NewStyle().Bold(true)
+	border := m.renderer.NewStyle().Border(lipgloss.RoundedBorder()).Width(innerWidth).Height(innerHeight)
+	if focused {
+		border = border.BorderForeground(lipgloss.Color("12"))
+		title = title.Foreground(lipgloss.Color("12"))
+	}
+	line := m.renderer.NewStyle().MaxWidth(innerWidth)
+	selected := line.Reverse(focused).Bold(true)
+
+	items := m.paneLines(pane)
+	visible := innerHeight - 1
+	start := 0
+	cursor := -1
+	if pane == tuiVerdictsPane {
+		// Поток вердиктов всегда показывает последние строки
+		start = max(len(items)-visible, 0)
+	} else if m.itemCount(pane) > 0 {
+		cursor = m.cursor[pane]
+		start = max(cursor-visible+1, 0)
+	}
+
+	out := []string{title.Render(T(tuiPaneTitles[pane]))}
+	for i := start; i < len(items) && i < start+visible; i++ {
+		if i == cursor {
+			out = append(out, selected.Render(items[i]))
+		} else {
+			out = append(out, line.Render(items[i]))
+		}
+	}
+	return border.Render(strings.Join(out, "\n"))
+}
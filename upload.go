@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Большие решения (например, сгенерированные таблицы у предела размера)
+// отправляются частями: обрыв связи стоит одной части, а не всей отправки.
+// Порядок обмена:
+//
+//	POST /submit/upload            {contest_id, task_id, lang, size, sha256} -> {upload_id, chunk_size}
+//	PUT  /submit/upload/{id}?offset=N  часть исходника                       -> {received}
+//	GET  /submit/upload/{id}                                                  -> {received}
+//	POST /submit/upload/{id}/finish                                           -> как у /submit
+//
+// Если сервер не знает /submit/upload, решение уходит обычным POST /submit
+const (
+	chunkedUploadThreshold = 64 * 1024
+	defaultUploadChunkSize = 16 * 1024
+	maxUploadChunkSize     = 1024 * 1024
+
+	uploadEndpointPurpose = "submit_upload"
+	// Запоминается вместо шаблона, если сервер не поддерживает отправку частями
+	uploadUnsupported = "-"
+
+	progressBarWidth = 30
+)
+
+var errChunkedUnsupported = errors.New("chunked upload is not supported by the server")
+
+type uploadInitRequest struct {
+	ContestID int    `json:"contest_id"`
+	TaskID    int    `json:"task_id"`
+	Lang      string `json:"lang"`
+	Size      int    `json:"size"`
+	SHA256    string `json:"sha256"`
+}
+
+type uploadState struct {
+	UploadID  string `json:"upload_id"`
+	ChunkSize int    `json:"chunk_size"`
+	Received  int    `json:"received"`
+}
+
+func shouldUploadChunked(size int) bool {
+	return size >= chunkedUploadThreshold && rememberedEndpoint(uploadEndpointPurpose) != uploadUnsupported
+}
+
+// Отправляет решение частями с повтором с последнего подтвержденного байта.
+// errChunkedUnsupported означает, что можно отправить решение обычным запросом
+func (a *APIClient) submitChunked(ctx context.Context, request SubmitRequest) (*SubmitResponse, error) {
+	code := []byte(request.Code)
+	sum := sha256.Sum256(code)
+
+	initData, err := json.Marshal(uploadInitRequest{
+		ContestID: request.ContestID,
+		TaskID:    request.TaskID,
+		Lang:      request.Lang,
+		Size:      len(code),
+		SHA256:    hex.EncodeToString(sum[:]),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	body, status, err := a.post(ctx, "/submit/upload", initData)
+	if err != nil {
+		return nil, fmt.Errorf("network error: %w", err)
+	}
+	switch status {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		rememberEndpoint(uploadEndpointPurpose, uploadUnsupported)
+		return nil, errChunkedUnsupported
+	}
+	if status >= 400 {
+		return nil, newAPIError(status, body)
+	}
+
+	var state uploadState
+	if err := json.Unmarshal(body, &state); err != nil || state.UploadID == "" {
+		a.quarantine("submit_upload", "/submit/upload", body, err)
+		return nil, errChunkedUnsupported
+	}
+	rememberEndpoint(uploadEndpointPurpose, "/submit/upload")
+
+	chunkSize := state.ChunkSize
+	if chunkSize <= 0 || chunkSize > maxUploadChunkSize {
+		chunkSize = defaultUploadChunkSize
+	}
+
+	progress(T("upload.chunked", formatSize(len(code)), formatSize(chunkSize)))
+	base := "/submit/upload/" + state.UploadID
+
+	offset := state.Received
+	failures := 0
+	printUploadProgress(offset, len(code))
+	for offset < len(code) {
+		end := min(offset+chunkSize, len(code))
+		received, err := a.putChunk(ctx, base, offset, code[offset:end])
+		if err == nil && received > offset {
+			offset = min(received, len(code))
+			failures = 0
+			printUploadProgress(offset, len(code))
+			continue
+		}
+
+		failures++
+		if failures > a.config.MaxRetries {
+			progressln()
+			if err == nil {
+				err = errors.New(T("upload.chunk_unconfirmed", offset))
+			}
+			return nil, fmt.Errorf("%s: %w", T("upload.interrupted", formatSize(offset), formatSize(len(code))), err)
+		}
+
+		delay := backoffDelay(a.config.BackoffBase, failures-1)
+		progress(T("upload.chunk_retry", err, delay.Round(100*time.Millisecond)))
+		if err := sleepContext(ctx, delay); err != nil {
+			progressln()
+			return nil, err
+		}
+
+		// Часть могла дойти, а потерялся только ответ: продолжаем с того, что есть на сервере
+		if received, err := a.uploadReceived(ctx, base); err == nil {
+			offset = min(received, len(code))
+		}
+		printUploadProgress(offset, len(code))
+	}
+	progressln()
+
+	body, status, err = a.post(ctx, base+"/finish", nil)
+	if err != nil {
+		return nil, fmt.Errorf("network error: %w", err)
+	}
+	return parseSubmitResponse(status, body)
+}
+
+// Отправляет часть исходника и возвращает, сколько байт сервер уже получил
+func (a *APIClient) putChunk(ctx context.Context, base string, offset int, chunk []byte) (int, error) {
+	endpoint := withQueryParam(base, "offset", offset)
+	req, err := a.newRequest(ctx, "PUT", endpoint, bytes.NewReader(chunk))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := a.doRequest(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode >= 400 {
+		return 0, newAPIError(resp.StatusCode, body)
+	}
+
+	var state uploadState
+	if err := json.Unmarshal(body, &state); err != nil {
+		// Без подтверждения считаем, что часть принята целиком
+		return offset + len(chunk), nil
+	}
+	return state.Received, nil
+}
+
+func (a *APIClient) uploadReceived(ctx context.Context, base string) (int, error) {
+	body, status, err := a.get(ctx, base)
+	if err != nil {
+		return 0, err
+	}
+	if status >= 400 {
+		return 0, newAPIError(status, body)
+	}
+	var state uploadState
+	if err := json.Unmarshal(body, &state); err != nil {
+		return 0, err
+	}
+	return state.Received, nil
+}
+
+func printUploadProgress(done, total int) {
+	filled := 0
+	if total > 0 {
+		filled = done * progressBarWidth / total
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+	progressf("\r  [%s] %3d%% %s / %s", bar, done*100/max(total, 1), formatSize(done), formatSize(total))
+}
+
+func formatSize(size int) string {
+	if size < 1024 {
+		return T("unit.b", size)
+	}
+	return T("unit.kb", float64(size)/1024)
+}
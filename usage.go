@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	apiUsageStateFile = "api_usage.json"
+	// Сколько дней хранить статистику запросов
+	apiUsageRetentionDays = 30
+)
+
+// Счетчики запросов к одному endpoint за день
+type EndpointUsage struct {
+	Calls       int `json:"calls"`
+	RateLimited int `json:"rate_limited"` // Ответы 429
+	Errors      int `json:"errors"`       // Сетевые ошибки и 5xx
+}
+
+func (u *EndpointUsage) add(other EndpointUsage) {
+	u.Calls += other.Calls
+	u.RateLimited += other.RateLimited
+	u.Errors += other.Errors
+}
+
+// Статистика запросов: дата (2006-01-02) -> endpoint -> счетчики
+type APIUsage struct {
+	Days map[string]map[string]*EndpointUsage `json:"days"`
+}
+
+func LoadAPIUsage() (*APIUsage, error) {
+	usage := &APIUsage{}
+	if err := loadState(apiUsageStateFile, usage); err != nil {
+		return nil, err
+	}
+	if usage.Days == nil {
+		usage.Days = make(map[string]map[string]*EndpointUsage)
+	}
+	return usage, nil
+}
+
+// Числовые части пути заменяются на :id, чтобы /submission/123 и /submission/456 считались вместе
+var reNumericPathSegment = regexp.MustCompile(`/\d+`)
+
+func usageEndpointKey(path string) string {
+	if path == "" {
+		path = "/"
+	}
+	return reNumericPathSegment.ReplaceAllString(path, "/:id")
+}
+
+// Накопитель счетчиков текущего запуска. Пишем на диск пачкой, а не после каждого запроса
+type usageRecorder struct {
+	mu      sync.Mutex
+	pending map[string]map[string]*EndpointUsage
+	count   int
+}
+
+const usageFlushThreshold = 50
+
+func (r *usageRecorder) record(path string, status int, err error) {
+	r.mu.Lock()
+
+	day := time.Now().Format("2006-01-02")
+	if r.pending == nil {
+		r.pending = make(map[string]map[string]*EndpointUsage)
+	}
+	if r.pending[day] == nil {
+		r.pending[day] = make(map[string]*EndpointUsage)
+	}
+	key := usageEndpointKey(path)
+	entry := r.pending[day][key]
+	if entry == nil {
+		entry = &EndpointUsage{}
+		r.pending[day][key] = entry
+	}
+
+	entry.Calls++
+	switch {
+	case err != nil || status >= 500:
+		entry.Errors++
+	case status == 429:
+		entry.RateLimited++
+	}
+	r.count++
+	flush := r.count >= usageFlushThreshold
+	r.mu.Unlock()
+
+	// Долгие команды (presence, standings --refresh) не должны копить все в памяти
+	if flush {
+		r.flush()
+	}
+}
+
+func (r *usageRecorder) flush() error {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = nil
+	r.count = 0
+	r.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	// Поврежденный файл не перезаписываем: updateState вернет ошибку чтения
+	usage := &APIUsage{}
+	return updateState(apiUsageStateFile, usage, func() error {
+		if usage.Days == nil {
+			usage.Days = make(map[string]map[string]*EndpointUsage)
+		}
+		for day, endpoints := range pending {
+			if usage.Days[day] == nil {
+				usage.Days[day] = make(map[string]*EndpointUsage)
+			}
+			for endpoint, counts := range endpoints {
+				if usage.Days[day][endpoint] == nil {
+					usage.Days[day][endpoint] = &EndpointUsage{}
+				}
+				usage.Days[day][endpoint].add(*counts)
+			}
+		}
+
+		cutoff := time.Now().AddDate(0, 0, -apiUsageRetentionDays).Format("2006-01-02")
+		for day := range usage.Days {
+			if day < cutoff {
+				delete(usage.Days, day)
+			}
+		}
+		return nil
+	})
+}
+
+// Сохраняет счетчики запросов текущего запуска
+func (a *APIClient) FlushUsage() {
+	a.usage.flush()
+}
+
+// Суммарные счетчики по endpoint за последние days дней
+func (u *APIUsage) Summary(days int) (map[string]*EndpointUsage, []string) {
+	from := time.Now().AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+
+	totals := make(map[string]*EndpointUsage)
+	var dates []string
+	for day, endpoints := range u.Days {
+		if day < from {
+			continue
+		}
+		dates = append(dates, day)
+		for endpoint, counts := range endpoints {
+			if totals[endpoint] == nil {
+				totals[endpoint] = &EndpointUsage{}
+			}
+			totals[endpoint].add(*counts)
+		}
+	}
+	sort.Strings(dates)
+	return totals, dates
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
+
+func (v *VSCodeExtension) handleAPIStats(days int) {
+	if days <= 0 {
+		days = 7
+	}
+
+	usage, err := LoadAPIUsage()
+	if err != nil {
+		fmt.Print(T("usage.read_error", err))
+		return
+	}
+
+	totals, dates := usage.Summary(days)
+	if len(totals) == 0 {
+		fmt.Print(T("usage.empty", days))
+		return
+	}
+
+	endpoints := make([]string, 0, len(totals))
+	for endpoint := range totals {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if totals[endpoints[i]].Calls != totals[endpoints[j]].Calls {
+			return totals[endpoints[i]].Calls > totals[endpoints[j]].Calls
+		}
+		return endpoints[i] < endpoints[j]
+	})
+
+	width := len("Endpoint")
+	for _, endpoint := range endpoints {
+		if len(endpoint) > width {
+			width = len(endpoint)
+		}
+	}
+
+	fmt.Print(T("usage.header", days))
+	// Заголовки выравниваем вручную: кириллица занимает больше байт, чем символов
+	fmt.Print(T("usage.columns", padRunes("Endpoint", width)))
+	fmt.Printf("  %s\n", strings.Repeat("─", width+36))
+
+	var all EndpointUsage
+	for _, endpoint := range endpoints {
+		counts := totals[endpoint]
+		all.add(*counts)
+		fmt.Printf("  %-*s  %7d  %6d  %6.1f%%  %6d\n", width, endpoint,
+			counts.Calls, counts.RateLimited, percent(counts.RateLimited, counts.Calls), counts.Errors)
+	}
+	fmt.Printf("  %s\n", strings.Repeat("─", width+36))
+	fmt.Printf("  %s  %7d  %6d  %6.1f%%  %6d\n", padRunes(T("usage.total"), width),
+		all.Calls, all.RateLimited, percent(all.RateLimited, all.Calls), all.Errors)
+
+	fmt.Println(T("usage.by_day"))
+	for _, day := range dates {
+		var dayTotal EndpointUsage
+		for _, counts := range usage.Days[day] {
+			dayTotal.add(*counts)
+		}
+		line := T("usage.day", day, dayTotal.Calls)
+		if dayTotal.RateLimited > 0 {
+			line += fmt.Sprintf(", 429: %d (%.1f%%)", dayTotal.RateLimited, percent(dayTotal.RateLimited, dayTotal.Calls))
+		}
+		fmt.Println(line)
+	}
+
+	if all.RateLimited > 0 {
+		progressln(T("usage.low_bandwidth_hint"))
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Данные пользователя с сервера. Запрос заодно проверяет токен: 401
+// перехватывается в doRequest и превращается в ErrNotAuthenticated
+type UserInfo struct {
+	ID        int           `json:"id"`
+	Username  string        `json:"username"`
+	Rating    int           `json:"rating"`
+	HasRating bool          `json:"has_rating,omitempty"` // сохраняется в кэше вместе с профилем
+	Solved    int           `json:"solved"`
+	HasSolved bool          `json:"has_solved,omitempty"`
+	Contests  []UserContest `json:"contests"`
+	Recent    []Submission  `json:"recent,omitempty"` // последние отправки, если сервер их отдает
+}
+
+type UserContest struct {
+	ID   int    `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// Точный endpoint не документирован: пробуем известные варианты и запоминаем рабочий
+func (a *APIClient) GetUserInfo(ctx context.Context) (*UserInfo, error) {
+	if !a.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	var lastErr error
+	for _, endpoint := range orderEndpoints("user_info", []string{"/getMe", "/users/me", "/getUserInfo", "/me"}) {
+		body, status, err := a.get(ctx, endpoint)
+		if isFatalAPIError(err) {
+			return nil, err
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if status != http.StatusOK {
+			lastErr = newAPIError(status, body)
+			continue
+		}
+		info, err := parseUserInfo(body)
+		if err != nil {
+			a.quarantine("user_info", endpoint, body, err)
+			lastErr = err
+			continue
+		}
+		rememberEndpoint("user_info", endpoint)
+		return info, nil
+	}
+	return nil, fmt.Errorf("%s: %w", T("user.info_unavailable"), cmp.Or(lastErr, errors.New(T("user.no_endpoint"))))
+}
+
+// Публичный профиль другого пользователя, {name} - имя пользователя
+var userProfileEndpoints = []string{
+	"/getUser?username={name}",
+	"/users/{name}",
+	"/getUserInfo?username={name}",
+	"/profile/{name}",
+}
+
+func (a *APIClient) GetUserProfile(ctx context.Context, username string) (*UserInfo, error) {
+	if !a.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	return cached(a, "users/"+strings.ToLower(username), ttlUserProfile, func() (*UserInfo, error) {
+		var lastErr error
+		for _, template := range orderEndpoints("user_profile", userProfileEndpoints) {
+			endpoint := strings.ReplaceAll(template, "{name}", url.PathEscape(username))
+			body, status, err := a.get(ctx, endpoint)
+			if isFatalAPIError(err) {
+				return nil, err
+			}
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if status != http.StatusOK {
+				lastErr = newAPIError(status, body)
+				continue
+			}
+			info, err := parseUserInfo(body)
+			if err != nil {
+				a.quarantine("user_profile", endpoint, body, err)
+				lastErr = err
+				continue
+			}
+			rememberEndpoint("user_profile", template)
+			return info, nil
+		}
+		return nil, fmt.Errorf("%s: %w", T("user.profile_unavailable", username), cmp.Or(lastErr, ErrNotFound))
+	})
+}
+
+// Разбирает ответ в любом из встречающихся форматов: поля пользователя
+// на верхнем уровне или внутри user/data, ID числом или строкой
+func parseUserInfo(body []byte) (*UserInfo, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"user", "data"} {
+		if nested, ok := raw[key]; ok {
+			var inner map[string]json.RawMessage
+			if json.Unmarshal(nested, &inner) == nil && inner != nil {
+				raw = inner
+				break
+			}
+		}
+	}
+
+	info := &UserInfo{}
+	for _, key := range []string{"id", "uid", "user_id"} {
+		if id, ok := jsonInt(raw[key]); ok {
+			info.ID = id
+			break
+		}
+	}
+	for _, key := range []string{"username", "login", "handle", "name"} {
+		var name string
+		if json.Unmarshal(raw[key], &name) == nil && name != "" {
+			info.Username = name
+			break
+		}
+	}
+	if info.ID == 0 && info.Username == "" {
+		return nil, errors.New(T("user.no_identity"))
+	}
+	info.Rating, info.HasRating = jsonInt(raw["rating"])
+	for _, key := range []string{"solved", "solved_count", "problems_solved"} {
+		if info.Solved, info.HasSolved = jsonInt(raw[key]); info.HasSolved {
+			break
+		}
+	}
+	for _, key := range []string{"recent_submissions", "submissions", "activity"} {
+		var recent []Submission
+		if json.Unmarshal(raw[key], &recent) == nil && len(recent) > 0 {
+			info.Recent = recent
+			break
+		}
+	}
+
+	for _, key := range []string{"contests", "registered_contests", "registrations"} {
+		if contests, ok := parseUserContests(raw[key]); ok {
+			info.Contests = contests
+			break
+		}
+	}
+	return info, nil
+}
+
+// Контесты приходят списком ID или объектами {id, name}
+func parseUserContests(data json.RawMessage) ([]UserContest, bool) {
+	var items []json.RawMessage
+	if len(data) == 0 || json.Unmarshal(data, &items) != nil {
+		return nil, false
+	}
+	contests := make([]UserContest, 0, len(items))
+	for _, item := range items {
+		if id, ok := jsonInt(item); ok {
+			contests = append(contests, UserContest{ID: id})
+			continue
+		}
+		var contest struct {
+			ID        json.RawMessage `json:"id"`
+			ContestID json.RawMessage `json:"contest_id"`
+			Name      string          `json:"name"`
+			Title     string          `json:"title"`
+		}
+		if json.Unmarshal(item, &contest) != nil {
+			continue
+		}
+		id, ok := jsonInt(contest.ID)
+		if !ok {
+			id, ok = jsonInt(contest.ContestID)
+		}
+		if ok {
+			contests = append(contests, UserContest{ID: id, Name: cmp.Or(contest.Name, contest.Title)})
+		}
+	}
+	return contests, true
+}
+
+// Число из JSON: 42 или "42"
+func jsonInt(data json.RawMessage) (int, bool) {
+	if len(data) == 0 || string(data) == "null" {
+		return 0, false
+	}
+	var n int
+	if json.Unmarshal(data, &n) == nil {
+		return n, true
+	}
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		if n, err := strconv.Atoi(s); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// При auth вручную вводится только имя, а ID часто совпадает с ним. Настоящие
+// данные с сервера сохраняем: по числовому ID строка ищется в таблице результатов
+func (v *VSCodeExtension) syncUserInfo(info *UserInfo) {
+	creds := v.config.credentials()
+	if info.Username != "" {
+		creds.Username = info.Username
+	}
+	if info.ID != 0 {
+		creds.UserID = strconv.Itoa(info.ID)
+	}
+	if creds == v.config.credentials() {
+		return
+	}
+	v.config.setCredentials(creds)
+	if err := SaveConfig(v.config); err != nil {
+		fmt.Print(T("config.save_error", err))
+		return
+	}
+	fmt.Println(T("whoami.updated"))
+}
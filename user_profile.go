@@ -0,0 +1,125 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Профиль пользователя: рейтинг, число решенных задач и последние отправки,
+// свой или одноклассника, чтобы сравниться без сайта
+
+const profileRecentLimit = 5
+
+// Свои последние отправки из локальной базы, если сервер их не отдает
+func localRecentSubmissions(limit int) []Submission {
+	db, err := OpenSubmissionDB()
+	if err != nil {
+		return nil
+	}
+	defer db.Close()
+	submissions, err := db.AllSubmissions("")
+	if err != nil {
+		return nil
+	}
+	recent := make([]Submission, 0, limit)
+	for i := len(submissions) - 1; i >= 0 && len(recent) < limit; i-- {
+		recent = append(recent, submissions[i])
+	}
+	return recent
+}
+
+func (v *VSCodeExtension) handleUserProfile(ctx context.Context, username string) error {
+	if !v.apiClient.IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+	// @list - пользователь с именем подкоманды
+	username = strings.TrimPrefix(username, "@")
+
+	var info *UserInfo
+	var err error
+	if username == "" {
+		info, err = v.apiClient.GetUserInfo(ctx)
+	} else {
+		info, err = v.apiClient.GetUserProfile(ctx, username)
+	}
+	if errors.Is(err, ErrNotFound) {
+		return errors.New(T("user.not_found", username))
+	}
+	if err != nil {
+		return err
+	}
+
+	// Свои данные нужны только для сравнения, без них профиль тоже полезен
+	var me *UserInfo
+	if username != "" {
+		if own, err := v.apiClient.GetUserInfo(ctx); err == nil && !strings.EqualFold(own.Username, info.Username) {
+			me = own
+		}
+	}
+
+	// Про себя недостающее берем из базы sortme sync
+	localSolved := false
+	if username == "" {
+		if len(info.Recent) == 0 {
+			info.Recent = localRecentSubmissions(profileRecentLimit)
+		}
+		if !info.HasSolved {
+			if db, err := OpenSubmissionDB(); err == nil {
+				if submissions, err := db.AllSubmissions(""); err == nil && len(submissions) > 0 {
+					info.Solved, info.HasSolved, localSolved = buildHistoryStats(submissions).Solved, true, true
+				}
+				db.Close()
+			}
+		}
+	}
+	if len(info.Recent) > profileRecentLimit {
+		info.Recent = info.Recent[:profileRecentLimit]
+	}
+
+	v.emitJSON(info)
+
+	fmt.Printf("👤 %s", cmp.Or(info.Username, username))
+	if info.ID != 0 {
+		fmt.Printf(" (ID %d)", info.ID)
+	}
+	fmt.Println()
+	if info.HasRating {
+		fmt.Print(T("user.rating", info.Rating))
+		if me != nil && me.HasRating {
+			fmt.Print(T("user.rating_diff", me.Rating, info.Rating-me.Rating))
+		}
+		fmt.Println()
+	}
+	if info.HasSolved {
+		fmt.Print(T("user.solved", info.Solved))
+		if localSolved {
+			fmt.Print(T("user.solved_local"))
+		}
+		if me != nil && me.HasSolved {
+			fmt.Print(T("user.solved_mine", me.Solved))
+		}
+		fmt.Println()
+	}
+	if len(info.Contests) > 0 {
+		fmt.Print(T("user.contests", len(info.Contests)))
+	}
+
+	if len(info.Recent) == 0 {
+		fmt.Println(T("user.no_recent"))
+		return nil
+	}
+	fmt.Println(T("user.recent"))
+	for _, sub := range info.Recent {
+		task := cmp.Or(sub.ProblemName, sub.TaskName, fmt.Sprint(cmp.Or(sub.ProblemID, sub.TaskID)))
+		submitted := ""
+		if at, ok := parseSubmitTime(cmp.Or(sub.SubmitTime, sub.Time)); ok {
+			submitted = at.Local().Format("02.01 15:04")
+		}
+		fmt.Printf("  %s %s  %s  %3d  %s\n", getShortStatusEmoji(sub.ShownVerdict), padRunes(task, 28),
+			padRunes(cmp.Or(sub.ContestName, sub.ContestID), 20), sub.TotalPoints, submitted)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Поток состояний отправки для tui, monitor, daemon и внешних программ: в отличие
+// от watchSubmission ничего не печатает и останавливается по ctx.
+// Сначала слушается WebSocket, при его недоступности статус опрашивается через REST
+const verdictPollInterval = 3 * time.Second
+
+// Очередное состояние отправки
+type VerdictEvent struct {
+	Status *SubmissionStatus
+	Final  bool   // вердикт окончательный, после него поток закрывается
+	Source string // websocket или poll
+	Err    error  // поток прерван ошибкой, событие последнее
+}
+
+// Возвращает канал состояний отправки. Канал закрывается после финального
+// вердикта, ошибки или отмены ctx; одинаковые состояния подряд не повторяются.
+// Метод экспортирован как будущий SDK для ботов и дашбордов, но пока плагин
+// собран одним package main, импортировать его из другого модуля нельзя:
+// для этого клиент нужно вынести в отдельный пакет
+func (a *APIClient) WatchSubmission(ctx context.Context, submissionID string) (<-chan VerdictEvent, error) {
+	if !a.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	submissionID = cleanSubmissionID(submissionID)
+	if submissionID == "" {
+		return nil, errors.New(T("source.empty_id"))
+	}
+
+	events := make(chan VerdictEvent, 8)
+	go func() {
+		defer close(events)
+
+		var last SubmissionStatus
+		send := func(event VerdictEvent) bool {
+			if event.Status != nil && event.Err == nil && !event.Final && reflect.DeepEqual(*event.Status, last) {
+				return true
+			}
+			if event.Status != nil {
+				last = *event.Status
+			}
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		err := a.streamVerdicts(ctx, submissionID, send)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		// WebSocket недоступен или замолчал: дальше опрашиваем REST
+		a.pollVerdicts(ctx, submissionID, verdictPollInterval, send)
+	}()
+	return events, nil
+}
+
+// Слушает WebSocket до финального вердикта. nil - вердикт отправлен в поток
+func (a *APIClient) streamVerdicts(ctx context.Context, submissionID string, send func(VerdictEvent) bool) error {
+	conn, err := a.dialWebSocket(ctx, "/ws/submission?id="+submissionID+"&token="+a.config.SessionToken)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	defer closeOnCancel(ctx, conn)()
+
+	queue := newQueueTracker(a.clock)
+	keepalive := startKeepAlive(conn, wsFirstMessageTimeout)
+	defer keepalive.Stop()
+	for {
+		messageType, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		logger.Debug("кадр websocket", "type", messageType, "data", logBody(message))
+		a.tape.frame(conn, message)
+		if messageType != websocket.TextMessage {
+			continue
+		}
+
+		status, err := a.parseWebSocketMessage(message)
+		if err != nil {
+			a.quarantine("ws_submission", "/ws/submission?id="+submissionID, message, err)
+			continue
+		}
+		status.ID = submissionID
+		final := a.isFinalStatus(status.Status)
+		if !send(VerdictEvent{Status: status, Final: final, Source: "websocket"}) {
+			return ctx.Err()
+		}
+		if final {
+			return nil
+		}
+		queue.observe(status)
+		keepalive.message(queue.timeout())
+	}
+}
+
+// Опрашивает REST статус раз в interval, пока не придет финальный вердикт
+func (a *APIClient) pollVerdicts(ctx context.Context, submissionID string, interval time.Duration, send func(VerdictEvent) bool) {
+	failures := 0
+	for {
+		status, err := a.tryRESTStatus(ctx, submissionID)
+		switch {
+		case err == nil:
+			failures = 0
+			if status.ID == "" {
+				status.ID = submissionID
+			}
+			final := a.isFinalStatus(status.Status)
+			if !send(VerdictEvent{Status: status, Final: final, Source: "poll"}) || final {
+				return
+			}
+		case failures >= a.config.MaxRetries:
+			send(VerdictEvent{Err: errors.Join(errors.New(T("stream.status_failed")), err), Source: "poll"})
+			return
+		default:
+			failures++
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.clock.After(interval):
+		}
+	}
+}
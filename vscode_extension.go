@@ -2,12 +2,16 @@ package main
 
 import (
 	"bufio"
-	"crypto/tls"
+	"bytes"
+	"cmp"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,6 +21,7 @@ import (
 type VSCodeExtension struct {
 	config    *Config
 	apiClient *APIClient
+	output    jsonOutput
 }
 
 func NewVSCodeExtension() *VSCodeExtension {
@@ -25,6 +30,9 @@ func NewVSCodeExtension() *VSCodeExtension {
 		fmt.Printf("Warning: failed to load config: %v\n", err)
 		config = &Config{}
 	}
+	if err := config.applyWorkspace("."); err != nil {
+		fmt.Fprint(os.Stderr, T("workspace.read_failed", err))
+	}
 
 	return &VSCodeExtension{
 		config:    config,
@@ -33,53 +41,312 @@ func NewVSCodeExtension() *VSCodeExtension {
 }
 
 func (v *VSCodeExtension) CreateRootCommand() *cobra.Command {
+	// Язык нужен уже сейчас: из него берутся описания команд
+	if err := setLanguage(v.config.Language); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	setLanguage(langFromArgs(os.Args[1:]))
+
 	var rootCmd = &cobra.Command{
 		Use:   "sortme",
 		Short: "Sort-me.org VSCode Plugin",
-		Long:  "Плагин для отправки решений на sort-me.org через VSCode",
+		Long:  T("root.long"),
+		// Ошибки печатает main, иначе сообщение выводится дважды
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			verbosity, _ := cmd.Flags().GetCount("verbose")
+			logFormat, _ := cmd.Flags().GetString("log-format")
+			if err := setupLogging(verbosity, logFormat); err != nil {
+				return err
+			}
+			// Новый профиль создает только auth, остальные команды работают с существующими
+			if cmd.Flags().Changed("profile") {
+				name, _ := cmd.Flags().GetString("profile")
+				if err := v.switchProfile(name, cmd.Name() == "auth"); err != nil {
+					return err
+				}
+			}
+			// Флаг включает режим только для текущего запуска, в конфиге можно задать low_bandwidth: true
+			if cmd.Flags().Changed("low-bandwidth") {
+				v.config.LowBandwidth, _ = cmd.Flags().GetBool("low-bandwidth")
+			}
+			if cmd.Flags().Changed("lang") {
+				lang, _ := cmd.Flags().GetString("lang")
+				if err := setLanguage(lang); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+				}
+			}
+			if noCache, _ := cmd.Flags().GetBool("no-cache"); noCache {
+				v.apiClient.cache.disabled = true
+			}
+			quietMode, _ = cmd.Flags().GetBool("quiet")
+			record, _ := cmd.Flags().GetString("record")
+			replay, _ := cmd.Flags().GetString("replay")
+			switch {
+			case record != "" && replay != "":
+				return errors.New(T("replay.record_with_replay"))
+			case record != "":
+				if err := v.apiClient.useTape(record, false); err != nil {
+					cmd.SilenceUsage = true
+					return fmt.Errorf("%s: %w", T("replay.record_failed"), err)
+				}
+			case replay != "":
+				if err := v.apiClient.useTape(replay, true); err != nil {
+					cmd.SilenceUsage = true
+					return fmt.Errorf("%s: %w", T("replay.open_failed"), err)
+				}
+			}
+			if jsonFlag, _ := cmd.Flags().GetBool("json"); jsonFlag {
+				v.enableJSONOutput()
+			}
+			if cmd.Name() != "doctor" {
+				v.warnInsecureSettings()
+			}
+			return nil
+		},
 	}
 
+	rootCmd.PersistentFlags().Bool("low-bandwidth", false, T("flag.low_bandwidth"))
+	rootCmd.PersistentFlags().Bool("json", false, T("flag.json"))
+	rootCmd.PersistentFlags().String("lang", "", T("flag.lang"))
+	rootCmd.PersistentFlags().Bool("no-cache", false, T("flag.no_cache"))
+	rootCmd.PersistentFlags().String("profile", "", T("flag.profile"))
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, T("flag.quiet"))
+	rootCmd.PersistentFlags().CountP("verbose", "v", T("flag.verbose"))
+	rootCmd.PersistentFlags().String("log-format", "text", T("flag.log_format"))
+	rootCmd.PersistentFlags().String("record", "", T("flag.record"))
+	rootCmd.PersistentFlags().String("replay", "", T("flag.replay"))
+
 	rootCmd.AddCommand(
 		v.createAuthCommand(),
 		v.createSubmitCommand(),
+		v.createWatchCommand(),
 		v.createStatusCommand(),
 		v.createWhoamiCommand(),
 		v.createLogoutCommand(),
 		v.createListCommand(),
 		v.createProblemsCommand(),
 		v.createDownloadCommand(),
+		v.createReadCommand(),
 		v.createContestsCommand(),
+		v.createAgendaCommand(),
+		v.createTagCommand(),
+		v.createStatsCommand(),
+		v.createPresenceCommand(),
+		v.createStandingsCommand(),
+		v.createTestCommand(),
+		v.createStressCommand(),
+		v.createGenCommand(),
+		v.createTestsCommand(),
+		v.createUseContestCommand(),
+		v.createWaitCommand(),
+		v.createCountdownCommand(),
+		v.createRemindCommand(),
+		v.createSyncCommand(),
+		v.createStartCommand(),
+		v.createInitCommand(),
+		v.createStateCommand(),
+		v.createProfileCommand(),
+		v.createCacheCommand(),
+		v.createDoctorCommand(),
+		v.createDaemonCommand(),
+		v.createMonitorCommand(),
+		v.createTUICommand(),
+		v.createReportCommand(),
+		v.createSearchCommand(),
+		v.createCodeCommand(),
+		v.createResubmitCommand(),
+		v.createDiffCommand(),
+		v.createLanguagesCommand(),
+		v.createBundleCommand(),
+		v.createHistoryCommand(),
+		v.createRegisterCommand(),
+		v.createNotifyCommand(),
+		v.createDevtoolsCommand(),
+		v.createOpenCommand(),
+		v.createCICommand(),
 	)
 
+	applyRenames(rootCmd)
+	v.applyDynamicExamples(rootCmd)
+	v.applyCompletions(rootCmd)
+
 	return rootCmd
 }
 
+func (v *VSCodeExtension) createUseContestCommand() *cobra.Command {
+	var clear bool
+
+	cmd := &cobra.Command{
+		Use:   "use-contest [contest_id]",
+		Short: T("usecontest.short"),
+		Long:  T("usecontest.long"),
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if clear {
+				v.config.setCurrentContest("")
+				if err := SaveConfig(v.config); err != nil {
+					fmt.Print(T("config.save_error", err))
+					return
+				}
+				fmt.Println(T("usecontest.cleared"))
+				return
+			}
+
+			if len(args) == 0 {
+				if v.config.CurrentContest == "" {
+					fmt.Println(T("usecontest.not_set"))
+					progressln(T("usecontest.hint"))
+					return
+				}
+				fmt.Print(T("usecontest.current", v.config.CurrentContest))
+				if v.config.workspaceFile != "" {
+					fmt.Print(T("usecontest.from_workspace", v.config.workspaceFile))
+				}
+				return
+			}
+
+			contestID := strings.TrimSpace(args[0])
+			if _, err := strconv.Atoi(contestID); err != nil {
+				fmt.Print(T("contest.invalid_id", contestID))
+				return
+			}
+
+			// Проверяем контест, но не запрещаем сохранить его без сети
+			contestName := ""
+			if v.apiClient.IsAuthenticated() {
+				if info, err := v.apiClient.GetContestInfo(cmd.Context(), contestID); err == nil {
+					contestName = info.Name
+					rememberContest(contestID, info)
+				} else {
+					fmt.Print(T("usecontest.check_failed", err))
+				}
+			}
+
+			v.config.setCurrentContest(contestID)
+			if err := SaveConfig(v.config); err != nil {
+				fmt.Print(T("config.save_error", err))
+				return
+			}
+
+			if contestName != "" {
+				fmt.Print(T("usecontest.set_named", contestName, contestID))
+			} else {
+				fmt.Print(T("usecontest.set", contestID))
+			}
+			if v.config.workspaceFile != "" && v.config.workspace.ContestID != contestID {
+				fmt.Print(T("usecontest.workspace_overrides", v.config.workspaceFile, v.config.workspace.ContestID))
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&clear, "clear", false, T("flag.clear_contest"))
+	return cmd
+}
+
 func (v *VSCodeExtension) createContestsCommand() *cobra.Command {
-	return &cobra.Command{
+	var opts ContestsOptions
+
+	cmd := &cobra.Command{
 		Use:   "contests",
-		Short: "Показать список доступных контестов",
+		Short: T("contests.short"),
+		Long:  T("contests.long"),
+		Args:  cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			v.handleContests()
+			v.handleContests(cmd.Context(), opts)
 		},
 	}
+
+	cmd.Flags().StringVarP(&opts.Status, "status", "s", "", T("flag.contests_status"))
+	cmd.Flags().StringVarP(&opts.Name, "name", "n", "", T("flag.contests_name"))
+	cmd.Flags().BoolVarP(&opts.All, "all", "a", false, T("flag.contests_all"))
+	cmd.Flags().IntVarP(&opts.Limit, "limit", "l", 0, T("flag.contests_limit"))
+	cmd.Flags().IntVar(&opts.Page, "page", 0, T("flag.contests_page"))
+	return cmd
 }
 
-func (v *VSCodeExtension) handleContests() {
+type ContestsOptions struct {
+	Status string // active, upcoming, archive ("" - все)
+	Name   string // подстрока названия
+	All    bool   // без сокращения списков
+	Limit  int
+	Page   int
+}
+
+// Сокращенный вид по умолчанию: сколько предстоящих и архивных контестов показать
+const (
+	contestsUpcomingShown = 5
+	contestsArchiveShown  = 8
+)
+
+// Оставляет контесты с нужным статусом и названием, порядок как в выводе:
+// предстоящие, активные, архивные
+func filterContests(contests []Contest, status, name string) []Contest {
+	name = strings.ToLower(strings.TrimSpace(name))
+	var filtered []Contest
+	for _, group := range []string{"upcoming", "active", "archive"} {
+		if status != "" && status != group {
+			continue
+		}
+		for _, contest := range contests {
+			if contest.Status != group {
+				continue
+			}
+			if name != "" && !strings.Contains(strings.ToLower(contest.Name), name) && contest.ID != name {
+				continue
+			}
+			filtered = append(filtered, contest)
+		}
+	}
+	return filtered
+}
+
+func (v *VSCodeExtension) handleContests(ctx context.Context, opts ContestsOptions) {
 	if !v.apiClient.IsAuthenticated() {
-		fmt.Println("❌ Вы не аутентифицированы")
+		v.fail(T("auth.required"))
+		return
+	}
+	opts.Status = strings.ToLower(opts.Status)
+	switch opts.Status {
+	case "", "active", "upcoming", "archive":
+	default:
+		v.fail(T("contests.bad_status", opts.Status))
+		return
+	}
+	page, err := listPage(opts.Page, 0, opts.Limit)
+	if err != nil {
+		v.fail(T("error.generic", err))
 		return
 	}
 
-	fmt.Println("🏆 Поиск контестов...")
+	progressln(T("contests.searching"))
 
-	contests, err := v.apiClient.GetContests()
+	contests, err := v.apiClient.GetContests(ctx)
 	if err != nil {
-		fmt.Printf("❌ Ошибка: %v\n", err)
+		v.fail(T("error.generic", err))
 		return
 	}
 
+	// С фильтрами, --all, --limit или --page списки не сокращаются
+	paged := opts.All || page.Limit > 0
+	contests = filterContests(contests, opts.Status, opts.Name)
+	total := len(contests)
+	if page.Limit > 0 {
+		contests = applyPage(contests, page)
+	}
+
+	if v.jsonMode() {
+		if contests == nil {
+			contests = []Contest{}
+		}
+		v.emitJSON(contests)
+	}
+
 	if len(contests) == 0 {
-		fmt.Println("📭 Контесты не найдены")
+		fmt.Println(T("contests.none"))
+		if total > 0 {
+			fmt.Print(T("contests.page_empty", total))
+		}
 		return
 	}
 
@@ -95,13 +362,17 @@ func (v *VSCodeExtension) handleContests() {
 			upcoming = append(upcoming, contest)
 		}
 	}
+	upcomingShown, archiveShown := contestsUpcomingShown, contestsArchiveShown
+	if paged || opts.Status != "" || opts.Name != "" {
+		upcomingShown, archiveShown = len(upcoming), len(archive)
+	}
 
 	// Сначала показываем предстоящие контесты
 	if len(upcoming) > 0 {
-		fmt.Printf("\n📅 Предстоящие контесты (%d):\n", len(upcoming))
+		fmt.Print(T("contests.upcoming", len(upcoming)))
 		for i, contest := range upcoming {
-			if i >= 5 {
-				fmt.Printf("   ... и еще %d предстоящих контестов\n", len(upcoming)-5)
+			if i >= upcomingShown {
+				fmt.Print(T("contests.upcoming_more", len(upcoming)-upcomingShown))
 				break
 			}
 			name := contest.Name
@@ -112,9 +383,9 @@ func (v *VSCodeExtension) handleContests() {
 		}
 	}
 
-	// Затем активные контесты
+	// Затем активные контесты (при фильтре по другому статусу не упоминаем)
 	if len(active) > 0 {
-		fmt.Printf("\n🎯 Активные контесты (%d):\n", len(active))
+		fmt.Print(T("contests.active", len(active)))
 		for _, contest := range active {
 			name := contest.Name
 			if len(name) > 40 {
@@ -122,16 +393,17 @@ func (v *VSCodeExtension) handleContests() {
 			}
 			fmt.Printf("   🟢 %s (ID: %s)\n", name, contest.ID)
 		}
-	} else {
-		fmt.Println("\n🎯 Активные контесты: нет активных контестов")
+	} else if opts.Status == "" && opts.Name == "" && page.Offset == 0 {
+		fmt.Println(T("contests.active_none"))
 	}
 
 	// Затем архивные
 	if len(archive) > 0 {
-		fmt.Printf("\n📚 Архивные контесты (%d):\n", len(archive))
+		fmt.Print(T("contests.archive", len(archive)))
 		for i, contest := range archive {
-			if i >= 8 {
-				fmt.Printf("   ... и еще %d архивных контестов\n", len(archive)-8)
+			if i >= archiveShown {
+				fmt.Print(T("contests.archive_more", len(archive)-archiveShown))
+				progress(T("contests.hint_all"))
 				break
 			}
 			name := contest.Name
@@ -142,21 +414,38 @@ func (v *VSCodeExtension) handleContests() {
 		}
 	}
 
-	fmt.Printf("\n💡 Команды:\n")
-	fmt.Printf("   sortme problems ID_контеста    - показать задачи контеста\n")
-	fmt.Printf("   sortme submit файл -c ID -p ID - отправить решение\n")
+	if page.Limit > 0 {
+		fmt.Print(T("contests.page_range", page.Offset+1, page.Offset+len(contests), total))
+		if page.Offset+len(contests) < total {
+			next := fmt.Sprintf("sortme contests --page %d", page.Offset/page.Limit+2)
+			if opts.Limit > 0 {
+				next += fmt.Sprintf(" --limit %d", opts.Limit)
+			}
+			if opts.Status != "" {
+				next += " --status " + opts.Status
+			}
+			if opts.Name != "" {
+				next += fmt.Sprintf(" --name %q", opts.Name)
+			}
+			progress(T("contests.hint_next", next))
+		}
+	}
+
+	progress(T("hint.commands"))
+	progress(T("contests.hint_problems"))
+	progress(T("contests.hint_submit"))
 
 	// Показываем пример с реальным ID из списка
 	if len(active) > 0 {
-		fmt.Printf("   sortme problems %s         - пример с активным контестом\n", active[0].ID)
+		progress(T("contests.example_active", active[0].ID))
 	} else if len(upcoming) > 0 {
-		fmt.Printf("   sortme problems %s         - пример с предстоящим контестом\n", upcoming[0].ID)
+		progress(T("contests.example_upcoming", upcoming[0].ID))
 	} else if len(archive) > 0 {
-		fmt.Printf("   sortme problems %s         - пример с архивным контестом\n", archive[0].ID)
+		progress(T("contests.example_archive", archive[0].ID))
 	}
 
 	// Показываем все ID контестов
-	fmt.Printf("\n🔢 Все ID контестов: ")
+	fmt.Print(T("contests.all_ids"))
 	displayed := 0
 	for _, contest := range contests {
 		if displayed > 0 {
@@ -173,86 +462,247 @@ func (v *VSCodeExtension) handleContests() {
 }
 
 func (v *VSCodeExtension) createAuthCommand() *cobra.Command {
-	return &cobra.Command{
+	var browser bool
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
 		Use:   "auth",
-		Short: "Аутентификация в sort-me.org",
-		Long:  "Ввод данных аутентификации для работы с sort-me.org",
+		Short: T("auth.short"),
+		Long:  T("auth.long"),
 		Run: func(cmd *cobra.Command, args []string) {
-			reader := bufio.NewReader(os.Stdin)
+			if browser {
+				creds, err := v.browserLogin(cmd.Context(), timeout)
+				if err != nil {
+					fmt.Printf("❌ %v\n", err)
+					progressln(T("auth.manual_hint"))
+					return
+				}
+				v.saveAuth(creds, cmd.Flags().Changed("profile"))
+				return
+			}
+
+			v.saveAuth(promptCredentials(), cmd.Flags().Changed("profile"))
+		},
+	}
 
-			fmt.Print("Введите ваш username: ")
-			username, _ := reader.ReadString('\n')
-			username = strings.TrimSpace(username)
+	cmd.Flags().BoolVar(&browser, "browser", false, T("flag.auth_browser"))
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Minute, T("flag.auth_timeout"))
+	return cmd
+}
 
-			fmt.Print("Введите session token: ")
-			token, _ := reader.ReadString('\n')
-			token = strings.TrimSpace(token)
+// Ручной ввод username и session token
+func promptCredentials() ProfileCredentials {
+	reader := bufio.NewReader(os.Stdin)
 
-			v.config.Username = username
-			v.config.SessionToken = token
-			v.config.UserID = username
+	fmt.Print(T("auth.prompt_username"))
+	username, _ := reader.ReadString('\n')
+	username = strings.TrimSpace(username)
 
-			if err := SaveConfig(v.config); err != nil {
-				fmt.Printf("Ошибка сохранения: %v\n", err)
-				return
-			}
+	fmt.Print(T("auth.prompt_token"))
+	token, _ := reader.ReadString('\n')
+	token = strings.TrimSpace(token)
 
-			fmt.Println("✅ Данные сохранены!")
-			fmt.Printf("Username: %s\n", username)
-			fmt.Printf("Token: %s\n", maskToken(token))
-		},
+	return ProfileCredentials{SessionToken: token, UserID: username, Username: username}
+}
+
+// Сохраняет вход. makeCurrent - вход в профиль из --profile делает его активным
+func (v *VSCodeExtension) saveAuth(creds ProfileCredentials, makeCurrent bool) {
+	v.config.setCredentials(creds)
+	if makeCurrent {
+		v.config.CurrentProfile = v.config.profile
+	}
+
+	if err := SaveConfig(v.config); err != nil {
+		fmt.Print(T("config.save_error", err))
+		return
+	}
+
+	fmt.Println(T("auth.saved"))
+	fmt.Printf("Username: %s\n", creds.Username)
+	fmt.Printf("Token: %s\n", maskToken(creds.SessionToken))
+	if v.config.profile != "" {
+		fmt.Print(T("auth.profile", v.config.profile))
 	}
 }
 
+// Параметры отправки решения
+type SubmitOptions struct {
+	ContestID string
+	ProblemID string
+	Language  string
+	AssumeYes bool // Не задавать вопросов
+	Watch     bool // Дождаться вердикта
+	Bundle    bool // Собрать локальные заголовки и пакеты в один файл
+	Strip     bool // Удалить комментарии и пустые строки
+	DryRun    bool // Все проверить и показать запрос, но не отправлять
+	Force     bool // Отправить, даже если задача уже решена
+	Copy      bool // Скопировать ссылку на отправку в буфер обмена
+}
+
 func (v *VSCodeExtension) createSubmitCommand() *cobra.Command {
-	var contestID, problemID, language string
+	var opts SubmitOptions
 
 	cmd := &cobra.Command{
-		Use:   "submit [file]",
-		Short: "Отправить решение на проверку",
+		Use:   "submit [file|-]",
+		Short: T("submit.short"),
+		Long:  T("submit.long"),
 		Args:  cobra.ExactArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
 			filename := args[0]
-			v.handleSubmit(filename, contestID, problemID, language)
+			// У кода из stdin нет расширения, язык только явно
+			if filename == stdinSource && opts.Language == "" {
+				v.fail(T("submit.stdin_language"))
+				fmt.Println(T("submit.languages", languageNames(v.config.Languages)))
+				return nil
+			}
+			v.applyWorkspaceBinding(filename, &opts)
+			if opts.ContestID == "" {
+				opts.ContestID = v.config.CurrentContest
+			}
+			// В терминале недостающее выбирается из списка
+			if (opts.ContestID == "" || opts.ProblemID == "") && filename != stdinSource && v.canPick() && v.apiClient.IsAuthenticated() {
+				if err := v.pickSubmitTarget(cmd.Context(), filename, &opts); err != nil {
+					return err
+				}
+			}
+			if opts.ContestID == "" {
+				v.fail(T("contest.missing"))
+				progressln(T("submit.contest_hint"))
+				return nil
+			}
+			if opts.ProblemID == "" {
+				v.fail(T("problem.missing"))
+				progressln(T("submit.problem_hint"))
+				return nil
+			}
+			var snapshot *acceptedSnapshot
+			if opts.Watch && !opts.DryRun {
+				snapshot = v.snapshotForGit(filename)
+			}
+			result := v.handleSubmit(cmd.Context(), filename, opts)
+			if result == nil || !opts.Watch || opts.DryRun {
+				return nil
+			}
+			submissionID := result["submission_id"].(string)
+			if err := v.watchVerdict(cmd.Context(), result, submissionID, opts.ContestID, opts.ProblemID); err != nil {
+				return err
+			}
+			v.commitAccepted(cmd.Context(), snapshot, opts.ContestID, opts.ProblemID, submissionID)
+			return nil
 		},
 	}
 
-	cmd.Flags().StringVarP(&contestID, "contest", "c", "", "ID контеста (обязательно)")
-	cmd.Flags().StringVarP(&problemID, "problem", "p", "", "ID задачи (обязательно)")
-	cmd.Flags().StringVarP(&language, "language", "l", "", "Язык программирования (опционально)")
-
-	cmd.MarkFlagRequired("contest")
-	cmd.MarkFlagRequired("problem")
+	cmd.Flags().StringVarP(&opts.ContestID, "contest", "c", "", T("flag.contest_default"))
+	cmd.Flags().StringVarP(&opts.ProblemID, "problem", "p", "", T("flag.problem_required"))
+	cmd.Flags().StringVarP(&opts.Language, "language", "l", "", T("flag.language"))
+	cmd.Flags().BoolVarP(&opts.AssumeYes, "yes", "y", false, T("flag.yes"))
+	cmd.Flags().BoolVarP(&opts.Watch, "watch", "w", false, T("flag.watch"))
+	cmd.Flags().BoolVar(&opts.Bundle, "bundle", false, T("flag.bundle"))
+	cmd.Flags().BoolVar(&opts.Strip, "strip-comments", false, T("flag.strip_comments"))
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, T("flag.dry_run"))
+	cmd.Flags().BoolVarP(&opts.Force, "force", "f", false, T("flag.submit_force"))
+	addCopyFlag(cmd, &opts.Copy, v.config)
 
 	return cmd
 }
 
 func (v *VSCodeExtension) createStatusCommand() *cobra.Command {
-	return &cobra.Command{
+	var contestID, problemID string
+	var poll, timeout time.Duration
+
+	cmd := &cobra.Command{
 		Use:   "status [submission_id]",
-		Short: "Проверить статус отправки",
+		Short: T("status.short"),
+		Long:  T("status.long"),
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			submissionID := args[0]
-			v.handleStatus(submissionID)
+			v.handleStatus(cmd.Context(), submissionID, contestID, problemID, StatusPoll{Interval: poll, Timeout: timeout})
 		},
 	}
+
+	cmd.Flags().StringVarP(&contestID, "contest", "c", "", T("flag.status_contest"))
+	cmd.Flags().StringVarP(&problemID, "problem", "p", "", T("flag.status_problem"))
+	cmd.Flags().DurationVar(&poll, "poll", 0, T("flag.status_poll"))
+	cmd.Flags().DurationVar(&timeout, "timeout", statusPollTimeout, T("flag.status_timeout"))
+
+	return cmd
 }
 
 func (v *VSCodeExtension) createWhoamiCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "whoami",
-		Short: "Показать текущего пользователя",
+		Short: T("whoami.short"),
 		Run: func(cmd *cobra.Command, args []string) {
 			if !v.apiClient.IsAuthenticated() {
-				fmt.Println("❌ Вы не аутентифицированы")
-				fmt.Println("Используйте команду:")
-				fmt.Println("  sortme auth - для аутентификации")
+				v.fail(T("auth.required"))
+				fmt.Println(T("whoami.use_command"))
+				progressln(T("whoami.hint_auth"))
 				return
 			}
-			fmt.Printf("✅ Текущий пользователь: %s\n", v.config.Username)
-			fmt.Printf("User ID: %s\n", v.config.UserID)
-			fmt.Printf("Session token: %s\n", maskToken(v.config.SessionToken))
+
+			expired := v.apiClient.SessionExpired()
+			if expired {
+				progress(T("session.marked_expired"))
+			}
+
+			info, err := v.apiClient.GetUserInfo(cmd.Context())
+			if errors.Is(err, ErrNotAuthenticated) {
+				v.fail(T("whoami.token_rejected"))
+				return
+			}
+			if err == nil && expired {
+				v.apiClient.clearSessionExpired()
+			}
+			if err != nil {
+				// Сервер недоступен: показываем то, что сохранено при auth
+				fmt.Print(T("whoami.offline", err))
+				info = &UserInfo{Username: v.config.Username}
+				info.ID, _ = strconv.Atoi(v.config.UserID)
+			} else {
+				v.syncUserInfo(info)
+			}
+
+			v.emitJSON(map[string]interface{}{
+				"id":       info.ID,
+				"username": info.Username,
+				"rating":   info.Rating,
+				"contests": info.Contests,
+				"profile":  v.config.profileName(),
+				"verified": err == nil,
+			})
+			userID := v.config.UserID
+			if info.ID != 0 {
+				userID = strconv.Itoa(info.ID)
+			}
+			fmt.Print(T("whoami.user", cmp.Or(info.Username, v.config.Username)))
+			fmt.Printf("User ID: %s\n", userID)
+			if info.HasRating {
+				fmt.Print(T("whoami.rating", info.Rating))
+			}
+			if err == nil {
+				if len(info.Contests) == 0 {
+					fmt.Println(T("whoami.no_contests"))
+				} else {
+					fmt.Print(T("whoami.contests", len(info.Contests)))
+					for _, contest := range info.Contests {
+						if contest.Name != "" {
+							fmt.Printf("   • %d - %s\n", contest.ID, contest.Name)
+						} else {
+							fmt.Printf("   • %d\n", contest.ID)
+						}
+					}
+				}
+			}
+			if len(v.config.Profiles) > 0 || v.config.profile != "" {
+				fmt.Printf("Profile: %s\n", v.config.profileName())
+			}
+			fmt.Printf("Session token: %s", maskToken(v.config.SessionToken))
+			if err == nil {
+				fmt.Print(T("whoami.token_valid"))
+			}
+			fmt.Println()
 		},
 	}
 }
@@ -260,20 +710,19 @@ func (v *VSCodeExtension) createWhoamiCommand() *cobra.Command {
 func (v *VSCodeExtension) createLogoutCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "logout",
-		Short: "Выйти из системы",
+		Short: T("logout.short"),
 		Run: func(cmd *cobra.Command, args []string) {
 			v.config.SessionToken = ""
 			v.config.UserID = ""
 			v.config.Username = ""
-			v.config.TelegramToken = ""
 
 			if err := SaveConfig(v.config); err != nil {
-				fmt.Printf("Ошибка при выходе: %v\n", err)
+				fmt.Print(T("logout.error", err))
 				return
 			}
 
-			fmt.Println("✅ Вы успешно вышли из системы")
-			fmt.Println("Все аутентификационные данные удалены")
+			fmt.Println(T("logout.done"))
+			fmt.Println(T("logout.cleared"))
 		},
 	}
 }
@@ -282,21 +731,35 @@ func (v *VSCodeExtension) createLogoutCommand() *cobra.Command {
 func (v *VSCodeExtension) createListCommand() *cobra.Command {
 	var limit int
 	var contestID string
+	var tagFilter string
+	var local bool
+	var pageNum, offset int
+	var format string
 
 	cmd := &cobra.Command{
 		Use:   "list [contest_id]",
-		Short: "Список отправок в контесте",
-		Long: `Показать список отправок в конкретном контесте
-
-Примеры:
-  sortme list           # Отправки в текущем контесте
-  sortme list 456       # Отправки в контесте 456
-  sortme list --limit 5 # Последние 5 отправок
-  sortme list --contest 0 # Отправки в контесте 0`,
-		Args: cobra.MaximumNArgs(1),
+		Short: T("list.short"),
+		Long:  T("list.long"),
+		Args:  cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			if !v.apiClient.IsAuthenticated() {
-				fmt.Println("❌ Вы не аутентифицированы")
+			if err := validListFormat(format); err != nil {
+				v.fail(err.Error())
+				return
+			}
+			// Для csv/tsv в stdout идет только таблица, остальное - в stderr, как с --json
+			export := os.Stdout
+			if format != listFormatTable {
+				if v.jsonMode() {
+					v.fail(T("submissions.format_with_json"))
+					return
+				}
+				os.Stdout = os.Stderr
+				defer func() { os.Stdout = export }()
+			}
+
+			// Локальной базе авторизация не нужна
+			if !local && !v.apiClient.IsAuthenticated() {
+				v.fail(T("auth.required"))
 				return
 			}
 
@@ -309,37 +772,103 @@ func (v *VSCodeExtension) createListCommand() *cobra.Command {
 				targetContestID = args[0]
 			}
 
+			if targetContestID == "" && v.canPick() {
+				picked, err := v.pickContestForWorkspace(cmd.Context())
+				if err != nil {
+					v.fail(err.Error())
+					return
+				}
+				targetContestID = picked
+			}
+
 			if targetContestID == "" {
-				fmt.Println("❌ Не указан контест")
-				fmt.Println("\n💡 Используйте:")
-				fmt.Println("  sortme list 456          - отправки в контесте 456")
-				fmt.Println("  sortme list --contest 0  - отправки в контесте 0")
-				fmt.Println("  sortme use-contest 456   - установить контест по умолчанию")
-				fmt.Println("  sortme contests          - список доступных контестов")
+				v.fail(T("contest.missing"))
+				progressln(T("hint.use"))
+				progressln(T("list.hint_contest"))
+				progressln(T("list.hint_contest_flag"))
+				progressln(T("list.hint_use_contest"))
+				progressln(T("list.hint_contests"))
+				return
+			}
+
+			page, err := listPage(pageNum, offset, limit)
+			if err != nil {
+				v.fail(T("error.generic", err))
 				return
 			}
 
-			fmt.Printf("🔍 Поиск отправок в контесте %s...\n", targetContestID)
+			progress(T("list.searching", targetContestID))
 
-			submissions, err := v.apiClient.GetContestSubmissions(targetContestID, limit)
+			var submissions []Submission
+			if local {
+				submissions, err = loadLocalSubmissions(targetContestID, page)
+			} else {
+				submissions, err = v.apiClient.GetContestSubmissionsPage(cmd.Context(), targetContestID, page)
+				if err != nil {
+					if cached, ok := v.apiClient.fallbackLocalSubmissions(targetContestID, page, err); ok {
+						submissions, err = cached, nil
+					}
+				}
+			}
 			if err != nil {
-				fmt.Printf("❌ Ошибка: %v\n", err)
-				fmt.Println("\n💡 Проверьте:")
-				fmt.Println("  - Правильность ID контеста")
-				fmt.Println("  - Доступность контеста")
-				fmt.Println("  - sortme contests - список контестов")
+				v.fail(T("error.generic", err))
+				progressln(T("hint.check"))
+				progressln(T("list.check_id"))
+				progressln(T("list.check_access"))
+				progressln(T("list.check_contests"))
 				return
 			}
 
+			if tagFilter != "" {
+				submissions, err = filterSubmissionsByTag(submissions, tagFilter)
+				if err != nil {
+					v.fail(T("tags.read_error", err))
+					return
+				}
+				progress(T("list.tag_filter", normalizeTag(tagFilter)))
+			}
+
+			if format != listFormatTable {
+				if err := writeSubmissionsExport(export, submissions, format); err != nil {
+					v.fail(T("error.generic", err))
+					return
+				}
+				fmt.Print(T("submissions.exported", len(submissions)))
+				return
+			}
+
+			if v.jsonMode() {
+				if submissions == nil {
+					submissions = []Submission{}
+				}
+				v.emitJSON(map[string]interface{}{
+					"contest_id":  targetContestID,
+					"offset":      page.Offset,
+					"limit":       page.Limit,
+					"submissions": submissions,
+				})
+			}
+
+			if len(submissions) == 0 && page.Offset > 0 {
+				fmt.Print(T("list.page_empty", page.Offset))
+				return
+			}
 			if len(submissions) == 0 {
-				fmt.Printf("📭 В контесте %s нет отправок\n", targetContestID)
-				fmt.Println("\n💡 Попробуйте отправить решение:")
-				fmt.Printf("  sortme submit файл.cpp -c %s -p ID_задачи\n", targetContestID)
+				fmt.Print(T("list.empty", targetContestID))
+				if local {
+					progress(T("list.hint_sync", targetContestID))
+					return
+				}
+				fmt.Println(T("list.try_submit"))
+				progress(T("list.hint_submit", targetContestID))
 				return
 			}
 
 			// Вывод таблицы отправок
-			fmt.Printf("\n📊 Отправки в контесте %s (%d):\n", targetContestID, len(submissions))
+			fmt.Print(T("list.header", targetContestID, len(submissions)))
+			if page.Offset > 0 {
+				fmt.Print(T("list.page_range", page.Offset+1, page.Offset+len(submissions)))
+			}
 
 			// Определяем максимальную ширину для названия задачи
 			maxTaskWidth := 25
@@ -359,7 +888,7 @@ func (v *VSCodeExtension) createListCommand() *cobra.Command {
 			fmt.Printf(headerFormat, taskHeader)
 
 			fmt.Printf("│ %-8s │ %-*s │ %-8s │ %-8s │ %-10s │\n",
-				"ID", maxTaskWidth, "Задача", "Статус", "Баллы", "Время")
+				"ID", maxTaskWidth, T("list.col_task"), T("list.col_status"), T("list.col_points"), T("list.col_time"))
 
 			separatorFormat := "├──────────┼─%s┼──────────┼──────────┼────────────┤\n"
 			fmt.Printf(separatorFormat, strings.Repeat("─", maxTaskWidth+2))
@@ -414,32 +943,87 @@ func (v *VSCodeExtension) createListCommand() *cobra.Command {
 				totalPoints += sub.TotalPoints
 			}
 
-			fmt.Printf("\n📈 Статистика: %d/%d успешных отправок", successCount, len(submissions))
+			fmt.Print(T("list.stats", successCount, len(submissions)))
 			if totalPoints > 0 {
-				fmt.Printf(", всего баллов: %d", totalPoints)
+				fmt.Print(T("list.stats_points", totalPoints))
 			}
 			fmt.Println()
 
 			// Текущий контест
 			if v.config.CurrentContest == targetContestID {
-				fmt.Printf("🎯 Текущий контест: %s\n", targetContestID)
+				fmt.Print(T("usecontest.current", targetContestID))
 			}
 
-			fmt.Printf("\n💡 Команды:\n")
+			progress(T("hint.commands"))
 			if len(submissions) > 0 {
-				fmt.Printf("  sortme status %d      - детальная информация\n", submissions[0].ID)
+				progress(T("list.hint_status", submissions[0].ID))
 			}
-			fmt.Printf("  sortme use-contest %s - установить контест по умолчанию\n", targetContestID)
-			fmt.Printf("  sortme problems %s    - список задач контеста\n", targetContestID)
+			// Страница заполнена целиком - дальше могут быть еще отправки
+			if page.Limit > 0 && len(submissions) == page.Limit && tagFilter == "" {
+				progress(T("list.hint_next", targetContestID, page.Offset+page.Limit, page.Limit))
+			}
+			progress(T("list.hint_use", targetContestID))
+			progress(T("list.hint_problems", targetContestID))
 		},
 	}
 
-	cmd.Flags().IntVarP(&limit, "limit", "l", 0, "Ограничить количество отправок")
-	cmd.Flags().StringVarP(&contestID, "contest", "c", "", "ID контеста")
+	cmd.Flags().IntVarP(&limit, "limit", "l", 0, T("flag.limit"))
+	cmd.Flags().StringVarP(&contestID, "contest", "c", "", T("flag.contest"))
+	cmd.Flags().StringVarP(&tagFilter, "tag", "t", "", T("flag.tag_filter"))
+	cmd.Flags().BoolVar(&local, "local", false, T("flag.local"))
+	cmd.Flags().IntVar(&pageNum, "page", 0, T("flag.page"))
+	cmd.Flags().IntVar(&offset, "offset", 0, T("flag.offset"))
+	cmd.Flags().StringVar(&format, "format", listFormatTable, T("flag.list_format"))
 
 	return cmd
 }
 
+// Размер страницы для --page, если --limit не указан
+const listPageSize = 20
+
+// Страница из флагов list: --page считается от 1 и листает по --limit отправок,
+// --offset пропускает указанное число самых новых
+func listPage(pageNum, offset, limit int) (Page, error) {
+	if pageNum < 0 || offset < 0 || limit < 0 {
+		return Page{}, errors.New(T("page.negative"))
+	}
+	if pageNum > 0 && offset > 0 {
+		return Page{}, errors.New(T("page.conflict"))
+	}
+	if pageNum > 0 {
+		if limit == 0 {
+			limit = listPageSize
+		}
+		return Page{Offset: (pageNum - 1) * limit, Limit: limit}, nil
+	}
+	return Page{Offset: offset, Limit: limit}, nil
+}
+
+// Оставляет только отправки по задачам с указанным тегом
+func filterSubmissionsByTag(submissions []Submission, tag string) ([]Submission, error) {
+	store, err := LoadTags()
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []Submission
+	for _, sub := range submissions {
+		if store.HasTag(sub.ProblemID, tag) {
+			filtered = append(filtered, sub)
+		}
+	}
+	return filtered, nil
+}
+
+// Задает вопрос да/нет, по умолчанию - нет
+func askConfirmation(question string) bool {
+	fmt.Printf("%s [y/N]: ", question)
+	reader := bufio.NewReader(os.Stdin)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes" || answer == "д" || answer == "да"
+}
+
 // Добавим функцию для короткого текста статуса
 func getShortStatusText(verdict int) string {
 	switch verdict {
@@ -460,6 +1044,14 @@ func getShortStatusText(verdict int) string {
 	}
 }
 
+// Буква задачи по ее позиции в контесте: 0 -> A, 25 -> Z, 26 -> AA
+func taskLetter(index int) string {
+	if index < 26 {
+		return string(rune('A' + index))
+	}
+	return taskLetter(index/26-1) + taskLetter(index%26)
+}
+
 // Обновим функцию для отображения имени задачи
 func getTaskDisplayName(sub Submission) string {
 	if sub.ProblemName != "" {
@@ -473,12 +1065,24 @@ func getTaskDisplayName(sub Submission) string {
 	return fmt.Sprintf("%d", sub.ProblemID)
 }
 
+// Порядок задач в problems
+const (
+	problemsOrderContest    = "contest"    // как в контесте
+	problemsOrderPopularity = "popularity" // сначала те, что решило больше участников
+)
+
+type ProblemsOptions struct {
+	Local bool   // Статусы из локальной базы
+	Order string // problemsOrderContest или problemsOrderPopularity
+}
+
 func (v *VSCodeExtension) createProblemsCommand() *cobra.Command {
 	var contestID string
+	var opts ProblemsOptions
 
 	cmd := &cobra.Command{
 		Use:   "problems [contest_id]",
-		Short: "Показать задачи контеста",
+		Short: T("problems.short"),
 		Args:  cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			// Определяем ID контеста
@@ -490,33 +1094,69 @@ func (v *VSCodeExtension) createProblemsCommand() *cobra.Command {
 				targetContestID = args[0]
 			}
 
+			if targetContestID == "" && v.canPick() && v.apiClient.IsAuthenticated() {
+				picked, err := v.pickContestForWorkspace(cmd.Context())
+				if err != nil {
+					v.fail(err.Error())
+					return
+				}
+				targetContestID = picked
+			}
+
 			if targetContestID == "" {
-				fmt.Println("❌ Не указан контест")
-				fmt.Println("\n💡 Используйте:")
-				fmt.Println("  sortme problems 456     - задачи контеста 456")
-				fmt.Println("  sortme problems --contest 0")
-				fmt.Println("  sortme use-contest 456  - установить контест по умолчанию")
+				v.fail(T("contest.missing"))
+				progressln(T("hint.use"))
+				progressln(T("problems.hint_contest"))
+				progressln("  sortme problems --contest 0")
+				progressln(T("problems.hint_use_contest"))
+				return
+			}
+
+			if opts.Order != problemsOrderContest && opts.Order != problemsOrderPopularity {
+				v.fail(T("problems.order_invalid", opts.Order))
 				return
 			}
 
 			// ВЫЗЫВАЕМ handleProblems
-			v.handleProblems(targetContestID)
+			v.handleProblems(cmd.Context(), targetContestID, opts)
 		},
 	}
 
-	cmd.Flags().StringVarP(&contestID, "contest", "c", "", "ID контеста")
+	cmd.Flags().StringVarP(&contestID, "contest", "c", "", T("flag.contest"))
+	cmd.Flags().BoolVar(&opts.Local, "local", false, T("flag.local"))
+	cmd.Flags().StringVar(&opts.Order, "order", problemsOrderContest, T("flag.problems_order"))
 	return cmd
 }
 
+// Задачи контеста в режиме --json
+type ProblemsJSON struct {
+	ContestID  string        `json:"contest_id"`
+	Name       string        `json:"name"`
+	Registered bool          `json:"registered"`
+	Solved     int           `json:"solved"`
+	Order      string        `json:"order"`
+	Tasks      []ProblemJSON `json:"tasks"`
+}
+
+type ProblemJSON struct {
+	ID       int    `json:"id"`
+	Letter   string `json:"letter"`
+	Name     string `json:"name"`
+	Solved   *bool  `json:"solved,omitempty"` // nil - статус не загружался
+	Points   int    `json:"points"`
+	Attempts int    `json:"attempts"`
+	Solves   *int   `json:"solves,omitempty"` // сколько участников решили, только с --order popularity
+}
+
 // Детальный метод для получения статуса задачи
-func (a *APIClient) GetTaskStatus(contestID string, taskID int) (solved bool, points int, submissionsCount int, err error) {
+func (a *APIClient) GetTaskStatus(ctx context.Context, contestID string, taskID int) (solved bool, points int, submissionsCount int, err error) {
 	if !a.IsAuthenticated() {
-		return false, 0, 0, fmt.Errorf("not authenticated")
+		return false, 0, 0, ErrNotAuthenticated
 	}
 
 	// Получаем все отправки для этой задачи
 	endpoint := fmt.Sprintf("/getMySubmissionsByTask?id=%d&contestid=%s", taskID, contestID)
-	submissions, err := a.tryGetSubmissions(endpoint, 0)
+	submissions, err := a.tryGetSubmissions(ctx, endpoint, 0)
 	if err != nil {
 		return false, 0, 0, err
 	}
@@ -549,9 +1189,10 @@ func (a *APIClient) GetTaskStatus(contestID string, taskID int) (solved bool, po
 	}
 
 	// Для некоторых контестов баллы > 0 могут считаться решением
-	if !solved && maxPoints > 0 {
+	// (лишний запрос информации о контесте не делаем в режиме экономии трафика)
+	if !solved && maxPoints > 0 && !a.config.LowBandwidth {
 		// Проверяем контест - если это учебный, то частичное решение может считаться
-		contestInfo, err := a.GetContestInfo(contestID)
+		contestInfo, err := a.GetContestInfo(ctx, contestID)
 		if err == nil && contestInfo != nil {
 			// Эвристика: если в названии есть "лабораторная" или "учебная", то частичное решение ок
 			if strings.Contains(strings.ToLower(contestInfo.Name), "лабораторная") ||
@@ -565,26 +1206,69 @@ func (a *APIClient) GetTaskStatus(contestID string, taskID int) (solved bool, po
 	return solved, maxPoints, submissionsCount, nil
 }
 
-func (v *VSCodeExtension) handleProblems(contestID string) {
+// Порядок задач по популярности из таблицы результатов. Без таблицы - порядок контеста
+func (v *VSCodeExtension) popularityOrder(ctx context.Context, contestID string, taskCount int) ([]int, []TaskPopularity) {
+	order := make([]int, taskCount)
+	for i := range order {
+		order[i] = i
+	}
+
+	standings, err := v.apiClient.GetStandings(ctx, contestID)
+	if err != nil {
+		progress(T("problems.order_unavailable", err))
+		return order, nil
+	}
+	if len(standings.Rows) == 0 {
+		progress(T("problems.order_empty"))
+		return order, nil
+	}
+
+	popularity := standings.Popularity(taskCount)
+	// Больше решивших - проще задача; при равенстве смотрим, сколько пытались
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := popularity[order[i]], popularity[order[j]]
+		if a.Solved != b.Solved {
+			return a.Solved > b.Solved
+		}
+		return a.Tried > b.Tried
+	})
+	progress(T("problems.order_popularity", len(standings.Rows)))
+	return order, popularity
+}
+
+func (v *VSCodeExtension) handleProblems(ctx context.Context, contestID string, opts ProblemsOptions) {
+	local := opts.Local
+
 	if !v.apiClient.IsAuthenticated() {
-		fmt.Println("❌ Вы не аутентифицированы")
+		v.fail(T("auth.required"))
 		return
 	}
 
-	fmt.Printf("📚 Получение списка задач для контеста %s...\n", contestID)
+	progress(T("problems.loading", contestID))
 
-	contestInfo, err := v.apiClient.GetContestInfo(contestID)
+	contestInfo, err := v.apiClient.GetContestInfo(ctx, contestID)
 	if err != nil {
-		fmt.Printf("❌ Ошибка получения задач: %v\n", err)
+		v.fail(T("problems.error", err))
 		return
 	}
 
 	if len(contestInfo.Tasks) == 0 {
-		fmt.Println("📭 Задачи не найдены")
+		fmt.Println(T("problems.none"))
+		if !contestInfo.Registered {
+			progress(T("problems.hint_register", contestID))
+		}
+		v.emitJSON(ProblemsJSON{ContestID: contestID, Name: contestInfo.Name, Registered: contestInfo.Registered, Tasks: []ProblemJSON{}})
 		return
 	}
 
-	fmt.Printf("\n📚 Задачи контеста \"%s\":\n", contestInfo.Name)
+	rememberContest(contestID, contestInfo)
+
+	// Сервер недоступен: статусы задач берем из локальной базы, а не ждем каждый запрос
+	if v.apiClient.Offline() {
+		local = true
+	}
+
+	fmt.Print(T("problems.header", contestInfo.Name))
 
 	// Сначала собираем все статусы с детальной информацией
 	taskStatuses := make([]struct {
@@ -594,18 +1278,70 @@ func (v *VSCodeExtension) handleProblems(contestID string) {
 	}, len(contestInfo.Tasks))
 
 	solvedCount := 0
+	problemsJSON := ProblemsJSON{ContestID: contestID, Name: contestInfo.Name, Registered: contestInfo.Registered, Order: problemsOrderContest}
+
+	order := make([]int, len(contestInfo.Tasks))
+	for i := range order {
+		order[i] = i
+	}
+	var popularity []TaskPopularity
+	if opts.Order == problemsOrderPopularity {
+		order, popularity = v.popularityOrder(ctx, contestID, len(contestInfo.Tasks))
+		if popularity != nil {
+			problemsJSON.Order = problemsOrderPopularity
+		}
+	}
+	// Сколько участников решили задачу: для вывода и JSON
+	solvesOf := func(i int) (*int, string) {
+		if popularity == nil {
+			return nil, ""
+		}
+		solves := popularity[i].Solved
+		return &solves, T("problems.solves", solves, popularity[i].Tried)
+	}
+
+	// В режиме экономии трафика не запрашиваем статус каждой задачи
+	if v.config.LowBandwidth {
+		for _, i := range order {
+			task := contestInfo.Tasks[i]
+			solves, solvesInfo := solvesOf(i)
+			fmt.Printf("  • %d. %s%s (ID: %d)\n", i+1, task.Name, solvesInfo, task.ID)
+			problemsJSON.Tasks = append(problemsJSON.Tasks, ProblemJSON{ID: task.ID, Letter: taskLetter(i), Name: task.Name, Solves: solves})
+		}
+		v.emitJSON(problemsJSON)
+		progress(T("problems.low_bandwidth"))
+		progress(T("problems.submit_hint"))
+		progress(T("problems.submit_example", contestID))
+		return
+	}
 
-	for i, task := range contestInfo.Tasks {
-		// Добавляем задержку чтобы избежать rate limiting
-		if i > 0 {
-			time.Sleep(300 * time.Millisecond)
+	// Статусы из локальной базы (sortme sync) без запросов к API
+	var db *SubmissionDB
+	if local {
+		db, err = OpenSubmissionDB()
+		if err != nil {
+			v.fail(T("db.open_error", err))
+			return
 		}
+		defer db.Close()
+	}
 
-		solved, points, submissions, err := v.apiClient.GetTaskStatus(contestID, task.ID)
+	for _, i := range order {
+		task := contestInfo.Tasks[i]
+		var solved bool
+		var points, submissions int
+		var err error
+		if db != nil {
+			var summary TaskSummary
+			summary, err = db.TaskSummary(contestID, task.ID)
+			solved, points, submissions = summary.Solved, summary.Points, summary.Attempts
+		} else {
+			solved, points, submissions, err = v.apiClient.GetTaskStatus(ctx, contestID, task.ID)
+		}
 		status := "❌" // По умолчанию не решена
 		if err != nil {
 			status = "❓" // Неизвестно из-за ошибки
-			fmt.Printf("  ⚠️ Ошибка проверки задачи %d: %v\n", task.ID, err)
+			progress(T("problems.status_error", task.ID, err))
 		} else if solved {
 			status = "✅" // Решена
 			solvedCount++
@@ -617,25 +1353,35 @@ func (v *VSCodeExtension) handleProblems(contestID string) {
 			submissions int
 		}{solved, points, submissions}
 
+		solves, solvesInfo := solvesOf(i)
+		problemJSON := ProblemJSON{ID: task.ID, Letter: taskLetter(i), Name: task.Name, Points: points, Attempts: submissions, Solves: solves}
+		if err == nil {
+			problemJSON.Solved = &solved
+		}
+		problemsJSON.Tasks = append(problemsJSON.Tasks, problemJSON)
+
 		// Выводим задачу со статусом
 		pointsInfo := ""
 		if points > 0 {
-			pointsInfo = fmt.Sprintf(" (%d баллов)", points)
+			pointsInfo = fmt.Sprintf(T("problems.points"), points)
 		}
 		submissionsInfo := ""
 		if submissions > 0 {
-			submissionsInfo = fmt.Sprintf(" [%d попыток]", submissions)
+			submissionsInfo = fmt.Sprintf(T("problems.attempts"), submissions)
 		}
 
-		fmt.Printf("  %s %d. %s%s%s (ID: %d)\n", status, i+1, task.Name, pointsInfo, submissionsInfo, task.ID)
+		fmt.Printf("  %s %d. %s%s%s%s (ID: %d)\n", status, i+1, task.Name, pointsInfo, submissionsInfo, solvesInfo, task.ID)
 	}
 
-	fmt.Printf("\n💡 Для отправки решения используйте:\n")
-	fmt.Printf("   sortme submit файл.cpp -c %s -p ID_задачи\n", contestID)
+	problemsJSON.Solved = solvedCount
+	v.emitJSON(problemsJSON)
+
+	progress(T("problems.submit_hint"))
+	progress(T("problems.submit_example", contestID))
 
 	// Статистика
 	totalCount := len(contestInfo.Tasks)
-	fmt.Printf("\n📊 Прогресс: %d/%d задач решено", solvedCount, totalCount)
+	fmt.Print(T("problems.progress", solvedCount, totalCount))
 
 	if totalCount > 0 {
 		percent := (solvedCount * 100) / totalCount
@@ -659,121 +1405,371 @@ func (v *VSCodeExtension) handleProblems(contestID string) {
 }
 
 func (v *VSCodeExtension) createDownloadCommand() *cobra.Command {
-	return &cobra.Command{
+	var outputDir string
+	var opts DownloadOptions
+
+	cmd := &cobra.Command{
 		Use:   "download [contest_id] [problem_id]",
-		Short: "Скачать условие задачи",
-		Args:  cobra.ExactArgs(2),
+		Short: T("download.short"),
+		Long:  T("download.long"),
+		Args:  cobra.RangeArgs(1, 2),
 		Run: func(cmd *cobra.Command, args []string) {
-			contestID := args[0]
-			problemID := args[1]
-			v.handleDownload(contestID, problemID)
+			contestID := v.config.CurrentContest
+			problemID := args[0]
+			if len(args) == 2 {
+				contestID = args[0]
+				problemID = args[1]
+			}
+			if contestID == "" {
+				v.fail(T("contest.missing"))
+				progressln(T("download.hint"))
+				return
+			}
+			v.handleDownload(cmd.Context(), contestID, problemID, outputDir, opts)
 		},
 	}
+
+	cmd.Flags().StringVarP(&outputDir, "output", "o", ".", T("flag.output_dir"))
+	cmd.Flags().BoolVar(&opts.ScaffoldIO, "scaffold-io", false, T("flag.scaffold_io"))
+	cmd.Flags().StringVarP(&opts.Language, "language", "l", "c++", T("flag.scaffold_language"))
+	return cmd
 }
 
-func (v *VSCodeExtension) handleSubmit(filename, contestID, problemID, language string) {
+// Возвращает описание отправки для --json или nil, если отправить не удалось
+func (v *VSCodeExtension) handleSubmit(ctx context.Context, filename string, opts SubmitOptions) map[string]interface{} {
+	contestID, problemID, language := opts.ContestID, opts.ProblemID, opts.Language
+	fromStdin := filename == stdinSource
+
 	// Проверяем существование файла
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		fmt.Printf("❌ Файл не существует: %s\n", filename)
-		return
+	if _, err := os.Stat(filename); !fromStdin && os.IsNotExist(err) {
+		v.fail(T("file.not_found", filename))
+		return nil
 	}
 
 	// Проверяем аутентификацию
 	if !v.apiClient.IsAuthenticated() {
-		fmt.Println("❌ Вы не аутентифицированы.")
-		fmt.Println("Сначала выполните аутентификацию одной из команд:")
-		fmt.Println("  sortme auth      - через Telegram бота")
-		fmt.Println("  sortme webauth   - через веб-сайт")
-		fmt.Println("  sortme manualauth - ручной ввод")
-		return
+		v.fail(T("auth.required"))
+		fmt.Println(T("submit.auth_first"))
+		fmt.Println(T("submit.auth_telegram"))
+		return nil
 	}
 
 	// Определяем язык если не указан
 	if language == "" {
 		language = v.apiClient.DetectLanguage(filename)
 		if language == "unknown" {
-			fmt.Println("❌ Не удалось определить язык программирования.")
-			fmt.Println("Укажите явно через --language")
-			fmt.Println("Доступные языки: python, java, c++, c, go, javascript, rust, typescript, php, ruby, csharp")
-			return
-		}
-		fmt.Printf("🔍 Автоопределен язык: %s\n", language)
-	} else {
-		// Проверяем поддерживаемый язык
-		supportedLangs := map[string]bool{
-			"python": true, "java": true, "c++": true, "c": true,
-			"go": true, "javascript": true, "rust": true,
-			"typescript": true, "php": true, "ruby": true, "csharp": true,
-		}
-		if !supportedLangs[language] {
-			fmt.Printf("❌ Неподдерживаемый язык: %s\n", language)
-			fmt.Println("Доступные языки: python, java, c++, c, go, javascript, rust, typescript, php, ruby, csharp")
-			return
+			v.fail(T("submit.language_unknown"))
+			progressln(T("submit.language_hint"))
+			fmt.Println(T("submit.languages", languageNames(v.config.Languages)))
+			return nil
 		}
+		fmt.Print(T("submit.language_detected", language))
+	}
+	// Проверяем язык по списку сервера и берем его точный ID
+	judgeLanguage, err := v.judgeLanguage(ctx, language)
+	if err != nil {
+		v.fail(err.Error())
+		fmt.Println(T("submit.languages", v.availableLanguages(ctx)))
+		return nil
 	}
+	language = judgeLanguage
 
 	// Читаем исходный код
-	sourceCode, err := ReadSourceCode(filename)
+	sourceCode, err := readSubmitSource(filename)
 	if err != nil {
-		fmt.Printf("❌ Ошибка чтения файла: %v\n", err)
-		return
+		v.fail(T("file.read_error", err))
+		return nil
+	}
+	family := detectLanguage(filename, v.config.Languages)
+	if spec, ok := languageFromServerName(language); ok && family == "unknown" {
+		family = spec.Name
+	}
+	// Заголовки ищутся рядом с файлом, а у stdin файла нет
+	if fromStdin && opts.Bundle {
+		v.fail(T("submit.stdin_bundle"))
+		return nil
+	}
+	if !fromStdin && (opts.Bundle || v.config.Bundle) {
+		bundle, err := bundleSource(filename, family, v.config.Languages)
+		if err != nil {
+			v.fail(T("submit.bundle_error", err))
+			return nil
+		}
+		if len(bundle.Files) > 0 {
+			fmt.Print(T("submit.bundled", len(bundle.Files), strings.Join(bundle.Files, ", ")))
+		}
+		sourceCode = bundle.Code
+	}
+	if opts.Strip {
+		stripped, ok := stripComments(sourceCode, family)
+		if ok {
+			fmt.Print(T("submit.stripped", formatTestSize(int64(len(sourceCode))), formatTestSize(int64(len(stripped)))))
+			sourceCode = stripped
+		} else {
+			fmt.Print(T("submit.strip_unsupported", family))
+		}
 	}
 
-	fmt.Printf("📤 Отправка решения...\n")
-	fmt.Printf("📝 Файл: %s\n", filename)
-	fmt.Printf("🏆 Контест: %s\n", contestID)
-	fmt.Printf("📚 Задача: %s\n", problemID)
-	fmt.Printf("💻 Язык: %s\n", language)
-	fmt.Printf("📊 Размер кода: %d символов\n", len(sourceCode))
+	// Защита от отправки файла другой задачи
+	if !v.confirmTaskMatch(ctx, filename, sourceCode, contestID, problemID, opts.AssumeYes) ||
+		!v.confirmNotSolved(ctx, contestID, problemID, opts) {
+		v.fail(T("submit.cancelled"))
+		// stdin уже прочитан до конца, ответить на вопрос было нечем
+		if fromStdin && !opts.AssumeYes {
+			progressln(T("submit.stdin_confirm_hint"))
+		}
+		return nil
+	}
+
+	opts.Language = language
+	source := filename
+	if fromStdin {
+		source = "stdin"
+	}
+	return v.sendSolution(ctx, source, sourceCode, opts)
+}
+
+// Имя файла "-" в submit: код читается из stdin
+const stdinSource = "-"
+
+// Код решения из файла или stdin. Пустой код отправлять бессмысленно
+func readSubmitSource(filename string) (string, error) {
+	if filename != stdinSource {
+		return ReadSourceCode(filename)
+	}
+	code, err := readTestInput(filename, T("submit.stdin_prompt"))
+	if err != nil {
+		return "", err
+	}
+	if len(bytes.TrimSpace(code)) == 0 {
+		return "", errors.New(T("submit.stdin_empty"))
+	}
+	return string(code), nil
+}
+
+// Отправляет уже прочитанный код. source - откуда код (файл или прошлая отправка),
+// попадает в вывод и в поле file результата
+func (v *VSCodeExtension) sendSolution(ctx context.Context, source, sourceCode string, opts SubmitOptions) map[string]interface{} {
+	contestID, problemID, language := opts.ContestID, opts.ProblemID, opts.Language
+
+	// Слишком большой код сервер все равно отклонит
+	if err := v.checkSourceSize(ctx, sourceCode, contestID, problemID); err != nil {
+		v.fail(err.Error())
+		if !opts.Strip {
+			progressln(T("submit.size_hint"))
+		}
+		return nil
+	}
+
+	progress(T("submit.sending"))
+	fmt.Print(T("submit.file", source))
+	fmt.Print(T("submit.contest", contestID))
+	fmt.Print(T("submit.problem", problemID))
+	fmt.Print(T("submit.language", language))
+	fmt.Print(T("submit.size", len(sourceCode)))
+
+	if opts.DryRun {
+		return v.dryRunSubmit(source, sourceCode, contestID, problemID, language)
+	}
 
 	// Отправляем решение
-	response, err := v.apiClient.SubmitSolution(contestID, problemID, language, sourceCode)
+	response, err := v.apiClient.SubmitSolution(ctx, contestID, problemID, language, sourceCode)
 	if err != nil {
-		fmt.Printf("❌ Ошибка отправки: %v\n", err)
-		fmt.Println("Проверьте:")
-		fmt.Println("  - Интернет соединение")
-		fmt.Println("  - Корректность contest ID и problem ID")
-		fmt.Println("  - Актуальность session token (попробуйте переаутентифицироваться)")
-		return
+		v.fail(T("submit.error", err))
+		// Про токен уже сказано, остальные советы не помогут
+		if errors.Is(err, ErrNotAuthenticated) {
+			return nil
+		}
+		var rateLimited *ErrRateLimited
+		if errors.As(err, &rateLimited) {
+			progressln(T("submit.rate_limited"))
+			return nil
+		}
+		progressln(T("submit.check"))
+		progressln(T("submit.check_network"))
+		progressln(T("submit.check_ids"))
+		progressln(T("submit.check_token"))
+		return nil
 	}
 
-	fmt.Printf("✅ Решение отправлено успешно!\n")
-	fmt.Printf("🎯 ID отправки: %s\n", response.ID)
-	fmt.Printf("📈 Статус: %s\n", response.Status)
+	// Запоминаем отправку с хэшем кода: sync его не знает
+	if id, err := strconv.Atoi(response.ID); err == nil {
+		if taskID, err := strconv.Atoi(problemID); err == nil {
+			if db, err := OpenSubmissionDB(); err == nil {
+				db.RecordSubmit(id, contestID, taskID, language, sourceCode)
+				db.Close()
+			}
+		}
+	}
+
+	result := map[string]interface{}{
+		"submission_id": response.ID,
+		"status":        response.Status,
+		"message":       response.Message,
+		"contest_id":    contestID,
+		"problem_id":    problemID,
+		"language":      language,
+		"file":          source,
+	}
+	// С --watch результат печатается вместе с вердиктом
+	if !opts.Watch {
+		v.emitJSON(result)
+	}
+
+	fmt.Print(T("submit.done"))
+	fmt.Print(T("submit.id", response.ID))
+	fmt.Print(T("submit.status", response.Status))
 	if response.Message != "" {
-		fmt.Printf("💬 Сообщение: %s\n", response.Message)
+		fmt.Print(T("submit.message", response.Message))
+	}
+
+	if opts.Copy {
+		link := submissionPageURL(response.ID)
+		if err := copyToClipboard(link); err != nil {
+			progress(T("submit.link_copy_failed", err))
+		} else {
+			progress(T("submit.link_copied", link))
+		}
+	}
+
+	if !opts.Watch {
+		progress(T("submit.status_hint"))
+		progressf("sortme status %s\n", response.ID)
+	}
+	return result
+}
+
+// --copy, по умолчанию из copy_link в конфиге. Конфиг читается до
+// разбора флагов, так что значение по умолчанию уже известно
+func addCopyFlag(cmd *cobra.Command, copy *bool, config *Config) {
+	cmd.Flags().BoolVar(copy, "copy", config.CopyLink, T("flag.submit_copy"))
+}
+
+// --dry-run: печатает запрос, который ушел бы на сервер. Все проверки
+// к этому моменту уже пройдены
+func (v *VSCodeExtension) dryRunSubmit(source, sourceCode, contestID, problemID, language string) map[string]interface{} {
+	request, err := newSubmitRequest(contestID, problemID, language, sourceCode)
+	if err != nil {
+		v.fail(T("submit.error", err))
+		progressln(T("submit.check_ids"))
+		return nil
+	}
+	payload, err := json.MarshalIndent(request, "", "  ")
+	if err != nil {
+		v.fail(T("submit.error", err))
+		return nil
+	}
+
+	endpoint := "/submit"
+	if shouldUploadChunked(len(sourceCode)) {
+		// Большой код уходит частями, если сервер это умеет, иначе обычным /submit
+		endpoint = "/submit/upload"
 	}
 
-	fmt.Printf("\nДля проверки статуса выполните:\n")
-	fmt.Printf("sortme status %s\n", response.ID)
+	v.emitJSON(map[string]interface{}{
+		"dry_run":    true,
+		"endpoint":   v.apiClient.transport.URL(endpoint),
+		"payload":    request,
+		"contest_id": contestID,
+		"problem_id": problemID,
+		"language":   language,
+		"file":       source,
+	})
+
+	fmt.Print(T("submit.dry_run", "POST "+v.apiClient.transport.URL(endpoint)))
+	fmt.Println(string(payload))
+	fmt.Println(T("submit.dry_run_done"))
+	return map[string]interface{}{"dry_run": true}
 }
 
-func (a *APIClient) GetSubmissionStatus(submissionID string) (*SubmissionStatus, error) {
+func (a *APIClient) GetSubmissionStatus(ctx context.Context, submissionID string) (*SubmissionStatus, error) {
+	return a.getSubmissionStatus(ctx, submissionID, nil)
+}
+
+// onUpdate получает промежуточные статусы, если вердикт пришлось ждать по WebSocket
+func (a *APIClient) getSubmissionStatus(ctx context.Context, submissionID string, onUpdate func(*SubmissionStatus)) (*SubmissionStatus, error) {
 	if !a.IsAuthenticated() {
-		return nil, fmt.Errorf("not authenticated")
+		return nil, ErrNotAuthenticated
 	}
 
-	// Сначала пробуем REST через IP
-	status, err := a.tryRESTStatusViaIP(submissionID)
+	// Сначала пробуем REST
+	status, err := a.tryRESTStatus(ctx, submissionID)
 	if err == nil {
 		return status, nil
 	}
 
 	// Если REST не работает, используем WebSocket
-	fmt.Printf("🔌 Подключаемся к WebSocket для статуса %s\n", submissionID)
-	return a.getStatusViaWebSocket(submissionID)
+	fmt.Print(T("status.websocket", submissionID))
+	return a.getStatusViaWebSocket(ctx, submissionID, onUpdate)
 }
 
-func (a *APIClient) tryRESTStatusViaIP(submissionID string) (*SubmissionStatus, error) {
-	insecureClient := &http.Client{
-		Timeout: 15 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
+// Настройки status --poll: опрос REST вместо WebSocket
+type StatusPoll struct {
+	Interval time.Duration
+	Timeout  time.Duration // 0 - без ограничения
+}
+
+const (
+	statusPollTimeout = 10 * time.Minute
+	// Чаще опрашивать незачем: вердикт от этого быстрее не придет
+	statusPollMinInterval = time.Second
+)
+
+// Опрашивает REST статус до финального вердикта, не трогая WebSocket
+// (его режет, например, корпоративный файрвол). Если вердикт не пришел
+// за poll.Timeout, возвращает последний известный статус
+func (a *APIClient) PollSubmissionStatus(ctx context.Context, submissionID string, poll StatusPoll) (*SubmissionStatus, error) {
+	return a.pollSubmissionStatus(ctx, submissionID, poll, nil)
+}
+
+// onUpdate получает каждый прочитанный статус
+func (a *APIClient) pollSubmissionStatus(ctx context.Context, submissionID string, poll StatusPoll, onUpdate func(*SubmissionStatus)) (*SubmissionStatus, error) {
+	if !a.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	if poll.Interval < statusPollMinInterval {
+		return nil, errors.New(T("status.poll_too_small", statusPollMinInterval))
+	}
+	pollCtx := ctx
+	if poll.Timeout > 0 {
+		var cancel context.CancelFunc
+		pollCtx, cancel = context.WithTimeout(ctx, poll.Timeout)
+		defer cancel()
+	}
+
+	progress(T("status.polling", poll.Interval))
+	var last *SubmissionStatus
+	var pollErr error
+	a.pollVerdicts(pollCtx, submissionID, poll.Interval, func(event VerdictEvent) bool {
+		if event.Err != nil {
+			pollErr = event.Err
+			return false
+		}
+		if last == nil || last.Status != event.Status.Status {
+			progress(T("status.current", getStatusEmoji(event.Status.Status)))
+		}
+		last = event.Status
+		if onUpdate != nil {
+			onUpdate(event.Status)
+		}
+		return true
+	})
+
+	switch {
+	case ctx.Err() != nil:
+		return nil, ctx.Err()
+	case pollErr != nil:
+		return nil, pollErr
+	case last != nil && a.isFinalStatus(last.Status):
+		return last, nil
+	case last != nil:
+		progress(T("status.poll_timeout", poll.Timeout, getStatusEmoji(last.Status)))
+		return last, nil
 	}
+	return nil, errors.New(T("status.poll_failed", poll.Timeout))
+}
 
+func (a *APIClient) tryRESTStatus(ctx context.Context, submissionID string) (*SubmissionStatus, error) {
 	endpoints := []string{
 		"/submission/" + submissionID,
 		"/submissions/" + submissionID,
@@ -781,79 +1777,146 @@ func (a *APIClient) tryRESTStatusViaIP(submissionID string) (*SubmissionStatus,
 	}
 
 	for _, endpoint := range endpoints {
-		url := "https://94.103.85.238" + endpoint
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			continue
+		body, code, err := a.get(ctx, endpoint)
+		if isFatalAPIError(err) {
+			return nil, err
 		}
-
-		req.Host = "api.sort-me.org"
-		req.Header.Set("Authorization", "Bearer "+a.config.SessionToken)
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := insecureClient.Do(req)
-		if err != nil {
+		if err != nil || code != http.StatusOK {
 			continue
 		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode == http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			var status SubmissionStatus
-			if err := json.Unmarshal(body, &status); err == nil {
-				return &status, nil
+		// Итог проверки с подзадачами приходит в том же виде, что и по WebSocket
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(body, &fields) == nil && fields["shown_verdict"] != nil {
+			if status, err := a.parseWebSocketMessage(body); err == nil {
+				status.ID = submissionID
+				return status, nil
 			}
 		}
+		var status SubmissionStatus
+		if err := json.Unmarshal(body, &status); err == nil {
+			return &status, nil
+		}
 	}
 
-	return nil, fmt.Errorf("REST статус недоступен")
+	return nil, errors.New(T("status.rest_unavailable"))
 }
 
-func (v *VSCodeExtension) handleStatus(submissionID string) {
+func (v *VSCodeExtension) handleStatus(ctx context.Context, submissionID, contestID, problemID string, poll StatusPoll) {
 	if !v.apiClient.IsAuthenticated() {
-		fmt.Println("❌ Вы не аутентифицированы")
+		v.fail(T("auth.required"))
 		return
 	}
 
 	// Очищаем ID от возможного JSON формата
 	cleanID := cleanSubmissionID(submissionID)
-	fmt.Printf("🔍 Запрос статуса отправки %s...\n", cleanID)
+	progress(T("status.requesting", cleanID))
+
+	// Уведомления уходят, только если отправка дошла до вердикта, пока команда
+	// ее ждала: status по уже проверенной отправке их не повторяет
+	judging := false
+	onUpdate := func(status *SubmissionStatus) {
+		if !v.apiClient.isFinalStatus(status.Status) {
+			judging = true
+		}
+	}
 
-	status, err := v.apiClient.GetSubmissionStatus(cleanID)
+	var status *SubmissionStatus
+	var err error
+	if poll.Interval > 0 {
+		status, err = v.apiClient.pollSubmissionStatus(ctx, cleanID, poll, onUpdate)
+	} else {
+		status, err = v.apiClient.getSubmissionStatus(ctx, cleanID, onUpdate)
+	}
 	if err != nil {
-		fmt.Printf("❌ Ошибка получения статуса: %v\n", err)
+		v.fail(T("status.error", err))
 		return
 	}
 
-	fmt.Printf("📊 Статус отправки %s:\n", cleanID)
+	v.emitJSON(status)
+
+	fmt.Print(T("status.header", cleanID))
 	fmt.Printf("   🆔 ID: %s\n", status.ID)
-	fmt.Printf("   📈 Статус: %s\n", getStatusEmoji(status.Status))
+	fmt.Print(T("status.status", getStatusEmoji(status.Status)))
 
 	if status.Result != "" {
-		fmt.Printf("   🎯 Результат: %s\n", status.Result)
+		fmt.Print(T("status.result", status.Result))
 	}
 	if status.Score > 0 {
-		fmt.Printf("   ⭐ Баллы: %d\n", status.Score)
+		fmt.Print(T("status.score", status.Score))
 	}
 	if status.Time != "" {
-		fmt.Printf("   ⏱️  Время: %s\n", status.Time)
+		fmt.Print(T("status.time", status.Time))
 	}
 	if status.Memory != "" {
-		fmt.Printf("   💾 Память: %s\n", status.Memory)
+		fmt.Print(T("status.memory", status.Memory))
+	}
+	if status.FailedTest > 0 {
+		fmt.Print(T("status.failed_test", status.FailedTest))
+	}
+	printSubtaskBreakdown(status.Subtasks)
+
+	fmt.Print(T("status.details", cleanID))
+
+	v.warnNearLimits(ctx, status, contestID, problemID)
+
+	if judging {
+		v.notifyVerdict(status, contestID, problemID, "")
+	}
+}
+
+// Таблица по подзадачам: баллы, худшее время и непройденные тесты
+func printSubtaskBreakdown(subtasks []Subtask) {
+	if len(subtasks) == 0 {
+		return
 	}
 
-	fmt.Printf("   🌐 Подробнее: https://sort-me.org/submission/%s\n", cleanID)
+	const failedWidth = 24
+	fmt.Print(T("status.subtasks"))
+	fmt.Println("   ┌─────┬───────┬──────────┬─" + strings.Repeat("─", failedWidth) + "─┐")
+	fmt.Printf("   │ %3s │ %5s │ %8s │ %s │\n", T("status.col_subtask"), T("status.col_points"),
+		T("status.col_worst_time"), padRunes(T("status.col_failed"), failedWidth))
+	fmt.Println("   ├─────┼───────┼──────────┼─" + strings.Repeat("─", failedWidth) + "─┤")
+	for i, subtask := range subtasks {
+		worstTime := fmt.Sprintf("%d ms", subtask.WorstTime)
+		failed := formatTestRanges(subtask.FailedTests)
+		if subtask.Skipped {
+			worstTime, failed = "-", T("status.subtask_skipped")
+		}
+		fmt.Printf("   │ %3d │ %5d │ %8s │ %s │\n", i+1, subtask.Points, worstTime, padRunes(failed, failedWidth))
+	}
+	fmt.Println("   └─────┴───────┴──────────┴─" + strings.Repeat("─", failedWidth) + "─┘")
+}
+
+// 3, 5, 6, 7 -> "3, 5-7"
+func formatTestRanges(tests []int) string {
+	sorted := append([]int(nil), tests...)
+	sort.Ints(sorted)
+
+	var parts []string
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] <= sorted[j]+1 {
+			j++
+		}
+		if sorted[j] > sorted[i] {
+			parts = append(parts, fmt.Sprintf("%d-%d", sorted[i], sorted[j]))
+		} else {
+			parts = append(parts, strconv.Itoa(sorted[i]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
 }
 
 // Улучшенный метод для проверки решена ли задача
-func (a *APIClient) IsTaskSolved(contestID string, taskID int) (bool, error) {
+func (a *APIClient) IsTaskSolved(ctx context.Context, contestID string, taskID int) (bool, error) {
 	if !a.IsAuthenticated() {
-		return false, fmt.Errorf("not authenticated")
+		return false, ErrNotAuthenticated
 	}
 
 	// Получаем все отправки для этой задачи
 	endpoint := fmt.Sprintf("/getMySubmissionsByTask?id=%d&contestid=%s", taskID, contestID)
-	submissions, err := a.tryGetSubmissions(endpoint, 0)
+	submissions, err := a.tryGetSubmissions(ctx, endpoint, 0)
 	if err != nil {
 		return false, err
 	}
@@ -868,7 +1931,7 @@ func (a *APIClient) IsTaskSolved(contestID string, taskID int) (bool, error) {
 			}
 			if submission.TotalPoints > 0 {
 				// Для некоторых контестов частичное решение может считаться решенным
-				fmt.Printf("   ⚠️ Задача %d: частичное решение (%d баллов)\n", taskID, submission.TotalPoints)
+				fmt.Print(T("problems.partial", taskID, submission.TotalPoints))
 				return true, nil
 			}
 		}
@@ -883,29 +1946,26 @@ func (a *APIClient) IsTaskSolved(contestID string, taskID int) (bool, error) {
 	return false, nil
 }
 
-func (v *VSCodeExtension) handleDownload(contestID, problemID string) {
-	fmt.Printf("🔍 Скачивание условия задачи %s из контеста %s...\n", problemID, contestID)
-	fmt.Println("⏳ Функция в разработке. Используйте sortme explore для исследования API")
-}
-
 func getStatusEmoji(status string) string {
 	switch status {
 	case "accepted", "AC":
-		return "✅ Принято"
+		return T("verdict.accepted")
 	case "wrong_answer", "WA":
-		return "❌ Неверный ответ"
+		return T("verdict.wrong_answer")
 	case "time_limit_exceeded", "TLE":
-		return "⏰ Превышено время"
+		return T("verdict.time_limit")
 	case "memory_limit_exceeded", "MLE":
-		return "💾 Превышена память"
+		return T("verdict.memory_limit")
 	case "compilation_error", "CE":
-		return "🔨 Ошибка компиляции"
+		return T("verdict.compilation_error")
 	case "runtime_error", "RE":
-		return "💥 Ошибка выполнения"
+		return T("verdict.runtime_error")
+	case "partial":
+		return T("verdict.partial")
 	case "pending", "in_queue":
-		return "⏳ В очереди"
+		return T("verdict.pending")
 	case "testing", "running":
-		return "🔍 Тестируется"
+		return T("verdict.testing")
 	default:
 		return status
 	}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Настройка VS Code для каталога контеста (sortme init --vscode):
+// .vscode/tasks.json с задачами build, test и submit для каждой задачи
+// контеста и .vscode/launch.json с отладкой решения на первом примере.
+// Свои задачи и конфигурации в этих файлах остаются, заменяются только
+// созданные sortme - с префиксом "sortme: " в имени
+
+const vscodeLabelPrefix = "sortme: "
+
+// Отладочные сборки лежат в .vscode/build, а не рядом с решениями
+const vscodeBuildDir = "build"
+
+// Задача контеста в каталоге init
+type vscodeProblem struct {
+	Letter    string
+	Name      string
+	ProblemID string
+	File      string // Решение в каталоге задачи
+	Language  string
+	Samples   int
+}
+
+type vscodeTask struct {
+	Label          string              `json:"label"`
+	Type           string              `json:"type"`
+	Command        string              `json:"command"`
+	Args           []string            `json:"args"`
+	Options        vscodeTaskOptions   `json:"options"`
+	Group          *vscodeTaskGroup    `json:"group,omitempty"`
+	ProblemMatcher []string            `json:"problemMatcher"`
+	Presentation   *vscodePresentation `json:"presentation,omitempty"`
+}
+
+type vscodeTaskOptions struct {
+	Cwd string `json:"cwd"`
+}
+
+type vscodeTaskGroup struct {
+	Kind string `json:"kind"`
+}
+
+type vscodePresentation struct {
+	Reveal string `json:"reveal"`
+	Panel  string `json:"panel"`
+}
+
+// Конфигурации отладки у расширений разные, общее - только эти поля
+type vscodeLaunch struct {
+	Name          string   `json:"name"`
+	Type          string   `json:"type"`
+	Request       string   `json:"request"`
+	Mode          string   `json:"mode,omitempty"`
+	Program       string   `json:"program,omitempty"`
+	MainClass     string   `json:"mainClass,omitempty"`
+	Args          []string `json:"args,omitempty"`
+	Cwd           string   `json:"cwd"`
+	Console       string   `json:"console,omitempty"`
+	PreLaunchTask string   `json:"preLaunchTask,omitempty"`
+}
+
+// Флаги отладочной сборки: без оптимизаций и с отладочной информацией
+func debugBuildFlags(flags []string) []string {
+	flags = slices.DeleteFunc(slices.Clone(flags), func(flag string) bool {
+		return strings.HasPrefix(flag, "-O")
+	})
+	return append(flags, "-g")
+}
+
+// Команда сборки задачи для отладчика, nil - язык собирать не нужно
+// или отлаживать его через cppdbg нельзя
+func vscodeBuildCommand(problem vscodeProblem, languages map[string]LanguageConfig) []string {
+	switch problem.Language {
+	case "c", "c++", "rust":
+	default:
+		return nil
+	}
+	runner, err := resolveRunner(problem.Language, languages)
+	if err != nil || len(runner.Compile) == 0 {
+		return nil
+	}
+	return expandRunnerArgs(runner.Compile, debugBuildFlags(runner.Flags), map[string]string{
+		"{src}": "${workspaceFolder}/" + problem.Letter + "/" + problem.File,
+		"{bin}": vscodeBinary(problem),
+		"{dir}": "${workspaceFolder}/" + problem.Letter,
+	})
+}
+
+func vscodeBinary(problem vscodeProblem) string {
+	return "${workspaceFolder}/.vscode/" + vscodeBuildDir + "/" + strings.TrimSuffix(problem.File, filepath.Ext(problem.File))
+}
+
+func vscodeTasks(contestID string, problems []vscodeProblem, languages map[string]LanguageConfig) []vscodeTask {
+	var tasks []vscodeTask
+	for _, problem := range problems {
+		cwd := "${workspaceFolder}/" + problem.Letter
+		if build := vscodeBuildCommand(problem, languages); build != nil {
+			matcher := []string{}
+			if problem.Language != "rust" {
+				matcher = []string{"$gcc"}
+			}
+			tasks = append(tasks, vscodeTask{
+				Label:          vscodeLabelPrefix + "build " + problem.Letter,
+				Type:           "process",
+				Command:        build[0],
+				Args:           build[1:],
+				Options:        vscodeTaskOptions{Cwd: cwd},
+				Group:          &vscodeTaskGroup{Kind: "build"},
+				ProblemMatcher: matcher,
+			})
+		}
+		tasks = append(tasks,
+			vscodeTask{
+				Label:          vscodeLabelPrefix + "test " + problem.Letter,
+				Type:           "process",
+				Command:        "sortme",
+				Args:           []string{"test", problem.File},
+				Options:        vscodeTaskOptions{Cwd: cwd},
+				Group:          &vscodeTaskGroup{Kind: "test"},
+				ProblemMatcher: []string{},
+			},
+			vscodeTask{
+				Label:          vscodeLabelPrefix + "submit " + problem.Letter,
+				Type:           "process",
+				Command:        "sortme",
+				Args:           []string{"submit", problem.File, "-c", contestID, "-p", problem.ProblemID, "--watch"},
+				Options:        vscodeTaskOptions{Cwd: cwd},
+				ProblemMatcher: []string{},
+				// Отправка спрашивает подтверждение, нужен терминал с вводом
+				Presentation: &vscodePresentation{Reveal: "always", Panel: "dedicated"},
+			},
+		)
+	}
+	return tasks
+}
+
+func vscodeLaunches(problems []vscodeProblem, languages map[string]LanguageConfig) []vscodeLaunch {
+	var launches []vscodeLaunch
+	for _, problem := range problems {
+		cwd := "${workspaceFolder}/" + problem.Letter
+		source := cwd + "/" + problem.File
+		launch := vscodeLaunch{
+			Name:    fmt.Sprintf("%s%s. %s", vscodeLabelPrefix, problem.Letter, problem.Name),
+			Request: "launch",
+			Cwd:     cwd,
+		}
+		switch problem.Language {
+		case "c", "c++", "rust":
+			if vscodeBuildCommand(problem, languages) == nil {
+				continue
+			}
+			launch.Type = "cppdbg"
+			launch.Program = vscodeBinary(problem)
+			launch.PreLaunchTask = vscodeLabelPrefix + "build " + problem.Letter
+			// gdb запускает программу через оболочку, так что < работает
+			if problem.Samples > 0 {
+				launch.Args = []string{"<", cwd + "/tests/sample1.in"}
+			}
+		case "python":
+			launch.Type = "debugpy"
+			launch.Program = source
+			launch.Console = "integratedTerminal"
+		case "go":
+			launch.Type = "go"
+			launch.Mode = "debug"
+			launch.Program = source
+			launch.Console = "integratedTerminal"
+		case "java":
+			launch.Type = "java"
+			launch.MainClass = source
+			launch.Console = "integratedTerminal"
+		default:
+			continue
+		}
+		launches = append(launches, launch)
+	}
+	return launches
+}
+
+// Записывает .vscode/tasks.json и launch.json в каталог контеста
+func writeVSCodeConfig(dir, contestID string, problems []vscodeProblem, languages map[string]LanguageConfig) error {
+	vscodeDir := filepath.Join(dir, ".vscode")
+	// Компилятор сам каталог для бинарника не создает
+	if err := os.MkdirAll(filepath.Join(vscodeDir, vscodeBuildDir), 0755); err != nil {
+		return err
+	}
+	tasks := vscodeTasks(contestID, problems, languages)
+	if err := mergeVSCodeFile(filepath.Join(vscodeDir, "tasks.json"), "2.0.0", "tasks", "label", tasks); err != nil {
+		return err
+	}
+	launches := vscodeLaunches(problems, languages)
+	return mergeVSCodeFile(filepath.Join(vscodeDir, "launch.json"), "0.2.0", "configurations", "name", launches)
+}
+
+// Заменяет в списке key файла элементы sortme (по префиксу поля nameField)
+// на новые, остальное содержимое файла не меняется. version - для нового файла
+func mergeVSCodeFile[E any](path, version, key, nameField string, entries []E) error {
+	file := map[string]json.RawMessage{"version": json.RawMessage(strconv.Quote(version))}
+	var existing []json.RawMessage
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		// VS Code разрешает в этих файлах комментарии, а мы их не сохраним
+		if err := json.Unmarshal(data, &file); err != nil {
+			return fmt.Errorf("%s: %w", T("vscode.parse_failed", path), err)
+		}
+		if list, ok := file[key]; ok {
+			if err := json.Unmarshal(list, &existing); err != nil {
+				return fmt.Errorf("%s: %w", T("vscode.not_list", path, key), err)
+			}
+		}
+	}
+
+	merged := []any{}
+	for _, entry := range existing {
+		var named map[string]json.RawMessage
+		var name string
+		if json.Unmarshal(entry, &named) == nil && json.Unmarshal(named[nameField], &name) == nil &&
+			strings.HasPrefix(name, vscodeLabelPrefix) {
+			continue
+		}
+		merged = append(merged, entry)
+	}
+	for _, entry := range entries {
+		merged = append(merged, entry)
+	}
+	out := make(map[string]any, len(file))
+	for name, value := range file {
+		out[name] = value
+	}
+	out[key] = merged
+
+	// Без экранирования < и >: в args бывает перенаправление ввода
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(out); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, buf.Bytes())
+}
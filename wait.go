@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func (v *VSCodeExtension) createWaitCommand() *cobra.Command {
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "wait [contest_id]",
+		Short: T("wait.short"),
+		Long:  T("wait.long"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			contestID := v.config.CurrentContest
+			if len(args) > 0 {
+				contestID = args[0]
+			}
+			if contestID == "" {
+				return fmt.Errorf("%s", T("contest.missing"))
+			}
+			if !v.apiClient.IsAuthenticated() {
+				return fmt.Errorf("%s", T("auth.required"))
+			}
+			return v.handleWait(cmd.Context(), contestID, timeout)
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, T("flag.wait_timeout"))
+	return cmd
+}
+
+// Разница между часами сервера (заголовок Date) и локальными часами
+func (a *APIClient) ServerClockOffset(ctx context.Context) (time.Duration, error) {
+	req, err := a.newRequest(ctx, http.MethodHead, "/", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := time.Now()
+	resp, err := a.doRequest(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	received := time.Now()
+
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", T("wait.no_server_time"), err)
+	}
+	// Date округлен до секунды, сравниваем с серединой запроса
+	local := sent.Add(received.Sub(sent) / 2)
+	return serverTime.Sub(local), nil
+}
+
+// Как часто опрашивать сервер: редко, пока до начала далеко, и чаще ближе к старту
+func waitPollInterval(remaining time.Duration) time.Duration {
+	switch {
+	case remaining > time.Hour:
+		return 10 * time.Minute
+	case remaining > 10*time.Minute:
+		return 2 * time.Minute
+	case remaining > time.Minute:
+		return 20 * time.Second
+	case remaining > time.Second:
+		return remaining
+	default:
+		return time.Second
+	}
+}
+
+func formatWaitRemaining(d time.Duration) string {
+	if d < time.Minute {
+		return T("unit.seconds", int(d.Seconds()+0.5))
+	}
+	return formatCountdown(d)
+}
+
+func (v *VSCodeExtension) handleWait(ctx context.Context, contestID string, timeout time.Duration) error {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	// Часы сервера сверяем один раз: за время ожидания расхождение не меняется,
+	// а лишний запрос на каждом опросе ни к чему. Без Date полагаемся на локальные
+	offset, _ := v.apiClient.ServerClockOffset(ctx)
+	for {
+		contests, err := v.apiClient.getUpcomingContestList(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", T("wait.contests_failed"), err)
+		}
+
+		var contest *UpcomingContest
+		for i := range contests {
+			if strconv.Itoa(contests[i].ID) == contestID {
+				contest = &contests[i]
+				break
+			}
+		}
+		if contest == nil {
+			// Контеста нет среди предстоящих: если он доступен, значит уже идет или в архиве
+			if _, err := v.apiClient.GetContestInfo(ctx, contestID); err == nil {
+				fmt.Print(T("wait.available", contestID))
+				return nil
+			}
+			return errors.New(T("wait.not_upcoming", contestID))
+		}
+
+		now := time.Now().Add(offset)
+		starts := time.Unix(contest.Starts, 0)
+		if !now.Before(starts) {
+			if contest.Ends > 0 && now.After(time.Unix(contest.Ends, 0)) {
+				return errors.New(T("wait.already_ended", contest.Name))
+			}
+			fmt.Print(T("wait.started", contest.Name))
+			return nil
+		}
+
+		remaining := starts.Sub(now)
+		progress(T("wait.starts_in",
+			contest.Name, formatWaitRemaining(remaining), starts.Local().Format("02.01 15:04:05")))
+
+		sleep := waitPollInterval(remaining)
+		if !deadline.IsZero() {
+			left := time.Until(deadline)
+			if left <= 0 {
+				return errors.New(T("wait.timeout", timeout))
+			}
+			if sleep > left {
+				sleep = left
+			}
+		}
+		if err := sleepContext(ctx, sleep); err != nil {
+			return err
+		}
+	}
+}
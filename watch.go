@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+type WatchOptions struct {
+	Submit   SubmitOptions
+	Debounce time.Duration // Пауза после последнего изменения перед отправкой
+}
+
+func (v *VSCodeExtension) createWatchCommand() *cobra.Command {
+	var opts WatchOptions
+
+	cmd := &cobra.Command{
+		Use:   "watch <file>",
+		Short: T("watch.short"),
+		Long:  T("watch.long"),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			v.applyWorkspaceBinding(args[0], &opts.Submit)
+			if opts.Submit.ContestID == "" {
+				opts.Submit.ContestID = v.config.CurrentContest
+			}
+			if opts.Submit.ContestID == "" {
+				progressln(T("submit.contest_hint"))
+				return fmt.Errorf("%s", T("contest.missing"))
+			}
+			if opts.Submit.ProblemID == "" {
+				progressln(T("submit.problem_hint"))
+				return fmt.Errorf("%s", T("problem.missing"))
+			}
+			if !v.apiClient.IsAuthenticated() {
+				return fmt.Errorf("%s", T("auth.required"))
+			}
+			return v.handleWatch(cmd.Context(), args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Submit.ContestID, "contest", "c", "", T("flag.contest_default"))
+	cmd.Flags().StringVarP(&opts.Submit.ProblemID, "problem", "p", "", T("flag.problem_required"))
+	cmd.Flags().StringVarP(&opts.Submit.Language, "language", "l", "", T("flag.language"))
+	cmd.Flags().BoolVarP(&opts.Submit.AssumeYes, "yes", "y", false, T("flag.watch_yes"))
+	cmd.Flags().DurationVar(&opts.Debounce, "debounce", 500*time.Millisecond, T("flag.watch_debounce"))
+
+	return cmd
+}
+
+func fileHash(filename string) ([32]byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
+
+func (v *VSCodeExtension) handleWatch(ctx context.Context, filename string, opts WatchOptions) error {
+	filename = filepath.Clean(filename)
+	if _, err := os.Stat(filename); err != nil {
+		return fmt.Errorf("%s", T("file.not_found", filename))
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("%s: %w", T("watch.start_failed"), err)
+	}
+	defer watcher.Close()
+
+	// Следим за каталогом: редакторы часто сохраняют файл через переименование,
+	// и наблюдение за самим файлом после этого теряется
+	if err := watcher.Add(filepath.Dir(filename)); err != nil {
+		return fmt.Errorf("%s: %w", T("watch.start_failed"), err)
+	}
+
+	fmt.Print(T("watch.watching", filename, opts.Submit.ContestID, opts.Submit.ProblemID))
+	if opts.Submit.AssumeYes {
+		fmt.Println(T("watch.autosubmit"))
+	}
+	progressln(T("watch.exit_hint"))
+
+	// Отправляем только новое содержимое: повторное сохранение без правок пропускаем
+	lastSubmitted, _ := fileHash(filename)
+
+	submitOpts := opts.Submit
+	submitOpts.Watch = true
+
+	// Когда контест закончится, оставляем итоги рядом с решениями
+	contestEnd := v.contestEnd(ctx, opts.Submit.ContestID)
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-contestEnd:
+			contestEnd = nil
+			v.reportContestEnd(ctx, opts.Submit.ContestID, filepath.Dir(filename))
+			fmt.Print(T("watch.watching_upsolve", filename))
+
+		case <-ctx.Done():
+			fmt.Println(T("watch.stopped"))
+			return nil
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			progress(T("watch.error", err))
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) != filename || !event.Has(fsnotify.Write|fsnotify.Create) {
+				continue
+			}
+			// Ждем, пока редактор допишет файл
+			debounce = time.After(opts.Debounce)
+
+		case <-debounce:
+			debounce = nil
+
+			hash, err := fileHash(filename)
+			if err != nil || hash == lastSubmitted {
+				continue
+			}
+
+			fmt.Print(T("watch.changed", filename, time.Now().Format("15:04:05")))
+			if !opts.Submit.AssumeYes && !askConfirmation(T("watch.confirm")) {
+				fmt.Println(T("watch.skipped"))
+				lastSubmitted = hash
+				continue
+			}
+
+			snapshot := v.snapshotForGit(filename)
+			result := v.handleSubmit(ctx, filename, submitOpts)
+			if result == nil {
+				continue
+			}
+			lastSubmitted = hash
+			// Вердикт уже напечатан, код завершения в режиме наблюдения не нужен
+			submissionID := result["submission_id"].(string)
+			err = v.watchVerdict(ctx, result, submissionID, submitOpts.ContestID, submitOpts.ProblemID)
+			if err == nil {
+				v.commitAccepted(ctx, snapshot, submitOpts.ContestID, submitOpts.ProblemID, submissionID)
+			} else if !isExitCodeError(err) {
+				fmt.Printf("❌ %v\n", err)
+			}
+			fmt.Print(T("watch.watching_again", filename))
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Данные о финальном вердикте для внешних интеграций
+type VerdictWebhookPayload struct {
+	Event        string `json:"event"`
+	SubmissionID string `json:"submission_id"`
+	ContestID    string `json:"contest_id,omitempty"`
+	TaskID       string `json:"task_id,omitempty"`
+	TaskName     string `json:"task_name,omitempty"`
+	User         string `json:"user,omitempty"`
+	Verdict      string `json:"verdict"`
+	VerdictText  string `json:"verdict_text,omitempty"`
+	Score        int    `json:"score"`
+	Time         string `json:"time,omitempty"`
+	Memory       string `json:"memory,omitempty"`
+	URL          string `json:"url"`
+	Timestamp    int64  `json:"timestamp"`
+
+	// Готовый текст: Slack читает поле text, Discord - content
+	Text    string `json:"text"`
+	Content string `json:"content"`
+}
+
+func newVerdictWebhookPayload(config *Config, status *SubmissionStatus, contestID, taskID, taskName string) VerdictWebhookPayload {
+	payload := VerdictWebhookPayload{
+		Event:        "verdict",
+		SubmissionID: status.ID,
+		ContestID:    contestID,
+		TaskID:       taskID,
+		TaskName:     taskName,
+		User:         config.Username,
+		Verdict:      status.Status,
+		VerdictText:  status.Result,
+		Score:        status.Score,
+		Time:         status.Time,
+		Memory:       status.Memory,
+		URL:          "https://sort-me.org/submission/" + status.ID,
+		Timestamp:    time.Now().Unix(),
+	}
+
+	task := taskID
+	if taskName != "" {
+		task = taskName
+	}
+	text := T("webhook.text", getStatusEmoji(status.Status), status.ID)
+	if task != "" {
+		text += T("webhook.text_task", task)
+	}
+	if config.Username != "" {
+		text += T("webhook.text_user", config.Username)
+	}
+	text += T("webhook.text_points", status.Score, payload.URL)
+
+	payload.Text = text
+	payload.Content = text
+	return payload
+}
+
+// Заголовок с подписью тела: sha256=<hex HMAC-SHA256 с secret>, как у GitHub
+const webhookSignatureHeader = "X-Sortme-Signature-256"
+
+// Все получатели вердиктов: webhooks и webhook_url из старых конфигов
+func (c *Config) verdictWebhooks() []WebhookConfig {
+	var hooks []WebhookConfig
+	for _, hook := range c.Webhooks {
+		if hook.URL != "" {
+			hooks = append(hooks, hook)
+		}
+	}
+	if c.WebhookURL != "" {
+		hooks = append(hooks, WebhookConfig{URL: c.WebhookURL})
+	}
+	return hooks
+}
+
+func signWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Хост для вывода: в пути webhook Slack и Discord лежит токен
+func webhookHost(raw string) string {
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "webhook"
+}
+
+// Адрес для текста ошибок: без пути, запроса и пароля, как maskToken для токенов
+func maskWebhookURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "webhook"
+	}
+	masked := u.Scheme + "://" + u.Host
+	if strings.Trim(u.Path, "/") != "" || u.RawQuery != "" {
+		masked += "/***"
+	}
+	return masked
+}
+
+// В *url.Error от net/http и url.Parse полный адрес получателя
+func maskWebhookError(err error, raw string) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = maskWebhookURL(raw)
+	}
+	return err
+}
+
+// Отправляет POST с JSON, с подписью, если у получателя есть secret
+func sendVerdictWebhook(client *http.Client, hook WebhookConfig, payload VerdictWebhookPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%s: %w", T("webhook.bad_url"), maskWebhookError(err, hook.URL))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sortme-Event", payload.Event)
+	if hook.Secret != "" {
+		req.Header.Set(webhookSignatureHeader, signWebhookBody(hook.Secret, data))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", T("webhook.request_failed"), maskWebhookError(err, hook.URL))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return errors.New(T("webhook.status", resp.StatusCode, string(body)))
+	}
+	return nil
+}
+
+// Уведомляет внешние сервисы о финальном вердикте, если это настроено
+func (v *VSCodeExtension) notifyVerdict(status *SubmissionStatus, contestID, taskID, taskName string) {
+	if !v.apiClient.isFinalStatus(status.Status) {
+		return
+	}
+
+	payload := newVerdictWebhookPayload(v.config, status, contestID, taskID, taskName)
+
+	client := v.apiClient.transport.externalClient(10 * time.Second)
+	for _, hook := range v.config.verdictWebhooks() {
+		if err := sendVerdictWebhook(client, hook, payload); err != nil {
+			progress(T("webhook.failed", webhookHost(hook.URL), err))
+		} else {
+			progress(T("webhook.sent", webhookHost(hook.URL)))
+		}
+	}
+
+	notifier, err := v.telegramNotifier()
+	if err != nil {
+		progress(T("notify.token_read_failed", err))
+		return
+	}
+	if notifier != nil {
+		if err := notifier.Send(payload.Text); err != nil {
+			progress(T("notify.telegram_failed", err))
+		} else {
+			progressln(T("notify.telegram_sent"))
+		}
+	}
+}
+
+// Вердикт отправки из списка (monitor, tui): контест и задача берутся из нее
+func (v *VSCodeExtension) notifySubmissionVerdict(sub Submission, status *SubmissionStatus, contestID string) {
+	taskID := ""
+	if sub.ProblemID != 0 {
+		taskID = strconv.Itoa(sub.ProblemID)
+	}
+	v.notifyVerdict(status, cmp.Or(sub.ContestID, contestID), taskID, sub.ProblemName)
+}
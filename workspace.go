@@ -0,0 +1,364 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Файл настроек проекта. Ищется от текущего каталога (или каталога
+// отправляемого файла) вверх, как git ищет .git; найденные файлы
+// накладываются друг на друга, ближайший важнее. Контест из него
+// перекрывает current_contest глобального конфига, но только в памяти
+const workspaceFileName = ".sortme.yaml"
+
+// Свои заготовки лежат в ~/.config/sortme_plugin/templates/template.<ext>
+const templatesDirName = "templates"
+
+// Содержимое .sortme.yaml. Каталог задачи (sortme init) задает problem_id, file
+// и генератор тестов (путь от каталога задачи), каталог проекта - contest_id,
+// язык по умолчанию и соответствие задач:
+//
+//	contest_id: "456"
+//	language: c++
+//	problems:
+//	  A: 2472       # каталог или файл a.cpp, A.py
+//	  b_brute: 2473 # имя файла без расширения
+type WorkspaceBinding struct {
+	ContestID string            `mapstructure:"contest_id"`
+	ProblemID string            `mapstructure:"problem_id"`
+	Language  string            `mapstructure:"language"`
+	File      string            `mapstructure:"file"`
+	Generator string            `mapstructure:"generator"`
+	Problems  map[string]string `mapstructure:"problems"`
+
+	dir string // каталог ближайшего .sortme.yaml, от него считаются file и generator
+}
+
+type InitOptions struct {
+	Dir      string // Каталог контеста (по умолчанию contest_<id>)
+	Language string // Язык заготовок
+	VSCode   bool   // Создать .vscode/tasks.json и launch.json
+}
+
+func loadWorkspaceBinding(dir string) (*WorkspaceBinding, error) {
+	config := viper.New()
+	config.SetConfigFile(filepath.Join(dir, workspaceFileName))
+	config.SetConfigType("yaml")
+	if err := config.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	var binding WorkspaceBinding
+	if err := config.Unmarshal(&binding); err != nil {
+		return nil, err
+	}
+	return &binding, nil
+}
+
+func saveWorkspaceBinding(dir string, binding WorkspaceBinding) error {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	config.Set("contest_id", binding.ContestID)
+	config.Set("problem_id", binding.ProblemID)
+	config.Set("language", binding.Language)
+	config.Set("file", binding.File)
+	if binding.Generator != "" {
+		config.Set("generator", binding.Generator)
+	}
+	if len(binding.Problems) > 0 {
+		config.Set("problems", binding.Problems)
+	}
+	return config.WriteConfigAs(filepath.Join(dir, workspaceFileName))
+}
+
+// Ищет .sortme.yaml от dir вверх и объединяет найденные. Возвращает nil, если
+// файлов нет, и пути найденных файлов, ближайший первым
+func findWorkspace(dir string) (*WorkspaceBinding, []string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var files []string
+	var bindings []*WorkspaceBinding
+	for {
+		binding, err := loadWorkspaceBinding(dir)
+		if err == nil {
+			files = append(files, filepath.Join(dir, workspaceFileName))
+			bindings = append(bindings, binding)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, nil, fmt.Errorf("%s: %w", filepath.Join(dir, workspaceFileName), err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	if len(bindings) == 0 {
+		return nil, nil, nil
+	}
+
+	// Начинаем с самого верхнего, ближние файлы перекрывают его значения
+	merged := &WorkspaceBinding{Problems: make(map[string]string)}
+	for i := len(bindings) - 1; i >= 0; i-- {
+		b := bindings[i]
+		merged.ContestID = cmp.Or(b.ContestID, merged.ContestID)
+		merged.Language = cmp.Or(b.Language, merged.Language)
+		for key, id := range b.Problems {
+			merged.Problems[key] = id
+		}
+		// Задача, файл и генератор относятся только к своему каталогу
+		if i == 0 {
+			merged.ProblemID, merged.File, merged.Generator = b.ProblemID, b.File, b.Generator
+			merged.dir = filepath.Dir(files[0])
+		}
+	}
+	return merged, files, nil
+}
+
+// ID задачи для файла: problem_id каталога задачи, затем problems по имени файла,
+// имени без расширения и имени каталога (без учета регистра)
+func (w *WorkspaceBinding) ProblemFor(filename string) string {
+	if w.ProblemID != "" {
+		return w.ProblemID
+	}
+	if abs, err := filepath.Abs(filename); err == nil {
+		filename = abs
+	}
+	base := filepath.Base(filename)
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+	dir := filepath.Base(filepath.Dir(filename))
+	for _, name := range []string{base, stem, dir} {
+		for key, id := range w.Problems {
+			if strings.EqualFold(key, name) {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// Дополняет не указанные флагами контест, задачу и язык из .sortme.yaml
+// от каталога отправляемого файла вверх
+func (v *VSCodeExtension) applyWorkspaceBinding(filename string, opts *SubmitOptions) {
+	binding, files, err := findWorkspace(filepath.Dir(filename))
+	if err != nil {
+		progress(T("workspace.read_failed", err))
+		return
+	}
+	if binding == nil {
+		return
+	}
+
+	applied := false
+	if opts.ContestID == "" && binding.ContestID != "" {
+		opts.ContestID = binding.ContestID
+		applied = true
+	}
+	if problemID := binding.ProblemFor(filename); opts.ProblemID == "" && problemID != "" {
+		opts.ProblemID = problemID
+		applied = true
+	}
+	// Язык из файла берем для привязанного решения или если по расширению его не определить
+	if opts.Language == "" && binding.Language != "" &&
+		(filepath.Base(filename) == binding.File || v.apiClient.DetectLanguage(filename) == "unknown") {
+		opts.Language = binding.Language
+	}
+	if applied {
+		details := T("workspace.bound_contest", opts.ContestID)
+		if opts.ProblemID != "" {
+			details = T("workspace.bound_task", opts.ContestID, opts.ProblemID)
+		}
+		progressf("📌 %s: %s\n", files[0], details)
+	}
+}
+
+func (v *VSCodeExtension) createInitCommand() *cobra.Command {
+	var opts InitOptions
+
+	cmd := &cobra.Command{
+		Use:   "init <contest_id>",
+		Short: T("init.short"),
+		Long:  T("init.long"),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if !v.apiClient.IsAuthenticated() {
+				return fmt.Errorf("%s", T("auth.required"))
+			}
+			if !cmd.Flags().Changed("language") && v.config.workspace != nil && v.config.workspace.Language != "" {
+				opts.Language = v.config.workspace.Language
+			}
+			return v.handleInit(cmd.Context(), args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Dir, "dir", "d", "", T("flag.start_dir"))
+	cmd.Flags().StringVarP(&opts.Language, "language", "l", "c++", T("flag.scaffold_language"))
+	cmd.Flags().BoolVar(&opts.VSCode, "vscode", false, T("flag.init_vscode"))
+	return cmd
+}
+
+// Заготовка решения: своя из каталога templates или сгенерированная по условию.
+// Уже начатое решение не перезаписываем
+func writeTemplate(statement *TaskStatement, contestID, problemID, letter, dir, language string) (string, error) {
+	ext, ok := scaffoldExtensions[language]
+	if !ok {
+		return "", errors.New(T("workspace.template_unsupported", language))
+	}
+
+	custom, err := os.ReadFile(filepath.Join(getConfigPath(), templatesDirName, "template"+ext))
+	if errors.Is(err, os.ErrNotExist) {
+		return writeScaffold(statement, problemID, dir, language)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	className := "problem_" + problemID
+	filename := filepath.Join(dir, className+ext)
+	if _, err := os.Stat(filename); err == nil {
+		return "", errors.New(T("workspace.file_exists", filename))
+	}
+
+	code := strings.NewReplacer(
+		"{{contest_id}}", contestID,
+		"{{problem_id}}", problemID,
+		"{{letter}}", letter,
+		"{{name}}", statement.Name,
+		"{{class}}", className,
+	).Replace(string(custom))
+	return filename, os.WriteFile(filename, []byte(code), 0644)
+}
+
+func (v *VSCodeExtension) handleInit(ctx context.Context, contestID string, opts InitOptions) error {
+	if _, ok := scaffoldExtensions[opts.Language]; !ok {
+		return errors.New(T("workspace.template_unsupported_list", opts.Language))
+	}
+
+	info, err := v.apiClient.GetContestInfo(ctx, contestID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", T("api.contest_info_failed"), err)
+	}
+	if len(info.Tasks) == 0 {
+		if !info.Registered {
+			progress(T("workspace.register_hint", contestID))
+		}
+		progress(T("workspace.wait_hint", contestID))
+		return errors.New(T("contest.no_tasks_yet", contestID))
+	}
+	rememberContest(contestID, info)
+
+	dir := opts.Dir
+	if dir == "" {
+		dir = "contest_" + contestID
+	}
+	progress(T("workspace.dir_header", info.Name, dir, len(info.Tasks)))
+
+	// Созданные каталоги удаляем, если что-то пошло не так
+	var created rollbackLog
+	fail := func(err error) error {
+		if len(created.paths) > 0 {
+			progressln(T("start.rollback"))
+			created.undo()
+		}
+		return err
+	}
+
+	var problems []vscodeProblem
+	for i, task := range info.Tasks {
+		letter := taskLetter(i)
+		problemID := fmt.Sprintf("%d", task.ID)
+		taskDir := filepath.Join(dir, letter)
+		progressf("  [%d/%d] %s. %s", i+1, len(info.Tasks), letter, task.Name)
+
+		if err := created.mkdir(taskDir); err != nil {
+			progressln(" ❌")
+			return fail(fmt.Errorf("%s: %w", T("download.mkdir_failed"), err))
+		}
+
+		result, err := v.downloadTask(ctx, contestID, problemID, taskDir, DownloadOptions{})
+		if err != nil {
+			progressln(" ❌")
+			return fail(fmt.Errorf("%s: %w", T("start.task_failed", letter), err))
+		}
+
+		binding := WorkspaceBinding{ContestID: contestID, ProblemID: problemID, Language: opts.Language}
+		var templateErr error
+		existing, err := loadWorkspaceBinding(taskDir)
+		if err == nil && existing.File != "" {
+			if _, err := os.Stat(filepath.Join(taskDir, existing.File)); err != nil {
+				existing.File = ""
+			}
+		}
+		if err == nil {
+			binding.Generator = existing.Generator
+		}
+		if err == nil && existing.File != "" {
+			// Повторный init: решение уже начато, заготовку не создаем
+			binding.File, binding.Language = existing.File, existing.Language
+		} else {
+			var templateFile string
+			templateFile, templateErr = writeTemplate(result.Statement, contestID, problemID, letter, taskDir, opts.Language)
+			if templateErr == nil {
+				binding.File = filepath.Base(templateFile)
+			}
+		}
+
+		if err := saveWorkspaceBinding(taskDir, binding); err != nil {
+			progressln(" ❌")
+			return fail(fmt.Errorf("%s: %w", T("workspace.write_failed", letter, workspaceFileName), err))
+		}
+
+		if binding.File != "" {
+			problems = append(problems, vscodeProblem{
+				Letter:    letter,
+				Name:      task.Name,
+				ProblemID: problemID,
+				File:      binding.File,
+				Language:  binding.Language,
+				Samples:   result.Samples,
+			})
+		}
+
+		details := ""
+		if result.Samples > 0 {
+			details = T("start.samples_count", result.Samples)
+		}
+		progressf(" ✅%s\n", details)
+		if result.SamplesErr != nil {
+			progress(T("workspace.samples_failed", result.SamplesErr))
+		}
+		if templateErr != nil {
+			progress(T("start.scaffold_failed", templateErr))
+		}
+	}
+
+	first := filepath.Join(dir, taskLetter(0))
+	progressln()
+	fmt.Print(T("workspace.ready", dir))
+	if opts.VSCode {
+		// Каталог уже готов, без настроек редактора он тоже пригоден
+		if err := writeVSCodeConfig(dir, contestID, problems, v.config.Languages); err != nil {
+			progress(T("workspace.vscode_failed", err))
+		} else {
+			fmt.Print(T("workspace.vscode_written", filepath.Join(dir, ".vscode")))
+		}
+	}
+	progressln(T("workspace.flagless_hint"))
+	if binding, err := loadWorkspaceBinding(first); err == nil && binding.File != "" {
+		progressf("   cd %s && sortme test %s && sortme submit %s\n", first, binding.File, binding.File)
+	}
+	progress(T("workspace.templates_hint", filepath.Join(getConfigPath(), templatesDirName, "template.cpp")))
+	return nil
+}